@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // 运行镜像 (alpine) 不带时区数据库，checkin.timezone 依赖内嵌的 tzdata
 
 	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
 	"github.com/dedata/dedata-backend/internal/infrastructure/cache"
 	"github.com/dedata/dedata-backend/internal/infrastructure/database"
 	dbRepo "github.com/dedata/dedata-backend/internal/infrastructure/database"
@@ -17,8 +19,12 @@ import (
 	"github.com/dedata/dedata-backend/internal/interface/http/routes"
 	"github.com/dedata/dedata-backend/internal/usecase"
 	"github.com/dedata/dedata-backend/internal/worker"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	pkgCrypto "github.com/dedata/dedata-backend/pkg/crypto"
 	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
 	"github.com/dedata/dedata-backend/pkg/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/gin-gonic/gin"
 )
 
@@ -57,9 +63,39 @@ func main() {
 
 	// Repositories
 	userRepo := dbRepo.NewGormUserRepository(db)
-	authRepo := dbRepo.NewGormAuthRepository(db)
 	checkinRepo := dbRepo.NewGormCheckInRepository(db)
 	profileRepo := dbRepo.NewGormProfileRepository(db)
+	sessionRepo := dbRepo.NewGormSessionRepository(db)
+	walletRepo := dbRepo.NewGormWalletRepository(db)
+	migrationRepo := dbRepo.NewGormMigrationRepository(db)
+	credentialRepo := dbRepo.NewGormCredentialRepository(db)
+	authorizationRepo := dbRepo.NewGormAuthorizationRepository(db)
+	oauthClientRepo := dbRepo.NewGormOAuthClientRepository(db)
+	apiKeyRepo := dbRepo.NewGormAPIKeyRepository(db)
+	totpRepo := dbRepo.NewGormTOTPRepository(db)
+	passkeyRepo := dbRepo.NewGormPasskeyRepository(db)
+	streakRuleRepo := dbRepo.NewGormStreakRuleRepository(db)
+	tokenRepo := cache.NewRedisTokenRepository(cache.GetRedis())
+	walletLinkRepo := cache.NewRedisWalletLinkRepository(cache.GetRedis())
+	migrationChallengeRepo := cache.NewRedisMigrationChallengeRepository(cache.GetRedis())
+	rateLimitRepo := cache.NewRedisRateLimitRepository(cache.GetRedis())
+	loginAttemptRepo := cache.NewRedisLoginAttemptRepository(cache.GetRedis())
+	consentChallengeRepo := cache.NewRedisConsentChallengeRepository(cache.GetRedis())
+	oauthTokenRepo := cache.NewRedisOAuthTokenRepository(cache.GetRedis())
+	stepUpRepo := cache.NewRedisStepUpRepository(cache.GetRedis())
+	passkeyChallengeRepo := cache.NewRedisPasskeyChallengeRepository(cache.GetRedis())
+	idempotencyRepo := cache.NewRedisIdempotencyRepository(cache.GetRedis())
+	lockRepo := cache.NewRedisLockRepository(cache.GetRedis())
+
+	// Login challenge store (Redis by default, PostgreSQL as a fallback)
+	challengeLimits := repository.ChallengeLimits{
+		PerAddress: cfg.Auth.NonceLimitPerAddress(),
+		PerIP:      cfg.Auth.NonceLimitPerIP(),
+	}
+	var challengeStore repository.ChallengeStore = cache.NewRedisChallengeStore(cache.GetRedis(), challengeLimits)
+	if cfg.Auth.ChallengeStoreBackend() == "database" {
+		challengeStore = dbRepo.NewGormChallengeStore(db, challengeLimits)
+	}
 
 	// External Clients
 	x402Client := external.NewX402Client(cfg.X402.BaseURL, cfg.X402.APIToken, cfg.X402.MerchantID, logger.GetLogger())
@@ -71,22 +107,115 @@ func main() {
 	}
 	defer tokenIssuer.Close()
 
+	// Signature verifier (EOA + EIP-1271 contract wallets); every allowed EVM chain needs an RPC client,
+	// otherwise contract wallet sign-in on that chain would always fail
+	callers := map[int64]bind.ContractCaller{cfg.Blockchain.ChainID: tokenIssuer.Client()}
+	for _, chainID := range cfg.Auth.EVMChainIDs() {
+		if _, ok := callers[chainID]; ok {
+			continue
+		}
+		rpcURL := cfg.Auth.RPCURL(chainID)
+		if rpcURL == "" {
+			logger.Fatal(fmt.Sprintf("auth.allowed_chain_ids includes chain %d but auth.chain_rpcs has no RPC for it", chainID))
+		}
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to connect to RPC for chain %d: %v", chainID, err))
+		}
+		defer client.Close()
+		callers[chainID] = client
+	}
+	sigVerifier, err := pkgCrypto.NewContractWalletVerifier(callers)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize signature verifier: %v", err))
+	}
+
+	// Chain families enabled for wallet sign-in
+	chains := chain.NewRegistry(chain.NewEVM(sigVerifier), chain.NewSolana())
+
 	// JWT Manager
-	jwtMgr := pkgJWT.NewJWTManager(&cfg.JWT)
+	jwtMgr, err := pkgJWT.NewJWTManager(&cfg.JWT)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to initialize JWT manager: %v", err))
+	}
+	if !jwtMgr.HasSigningKey() {
+		logger.Warn("jwt.keys is not set, OAuth clients cannot request the openid scope (ID tokens are never signed with the HS256 secret)")
+	}
+
+	// TOTP secrets are encrypted at rest; without a key admins cannot enroll
+	var totpCipher *pkgCrypto.SecretCipher
+	if cfg.TOTP.EncryptionKey != "" {
+		totpCipher, err = pkgCrypto.NewSecretCipher(cfg.TOTP.EncryptionKey)
+		if err != nil {
+			logger.Fatal(fmt.Sprintf("Failed to initialize TOTP cipher: %v", err))
+		}
+	} else {
+		logger.Warn("totp.encryption_key is not set, TOTP enrollment is disabled")
+	}
+
+	// Check-in days start at midnight in a single configured timezone
+	checkinLoc, err := cfg.CheckIn.Location()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to load checkin timezone: %v", err))
+	}
+
+	// Stored check-in days follow checkin.timezone; recompute them after the timezone changes
+	recomputed, err := checkinRepo.RecomputeCheckinDays(context.Background(), checkinLoc.String())
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to recompute check-in days: %v", err))
+	}
+	if recomputed {
+		logger.Info(fmt.Sprintf("Recomputed check-in days for timezone %s", checkinLoc))
+	}
+
+	// Stored streaks follow checkin.streak_grace_days (and the check-in days above); recompute them after either changes
+	recomputed, err = checkinRepo.RecomputeStreaks(context.Background(), cfg.CheckIn.StreakGraceDays)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to recompute check-in streaks: %v", err))
+	}
+	if recomputed {
+		logger.Info(fmt.Sprintf("Recomputed check-in streaks with %d grace days", cfg.CheckIn.StreakGraceDays))
+	}
 
 	// Use Cases
-	authUseCase := usecase.NewAuthUseCase(authRepo, userRepo, jwtMgr, logger.GetLogger())
-	checkinUseCase := usecase.NewCheckInUseCase(checkinRepo, userRepo, x402Client, &cfg.CheckIn, logger.GetLogger())
-	userUseCase := usecase.NewUserUseCase(userRepo, profileRepo, checkinRepo)
+	securityUseCase := usecase.NewSecurityUseCase(loginAttemptRepo, rateLimitRepo, &cfg.Auth.Lockout, &cfg.PoW, logger.GetLogger())
+	authUseCase := usecase.NewAuthUseCase(challengeStore, securityUseCase, userRepo, walletRepo, tokenRepo, sessionRepo, jwtMgr, sigVerifier, chains, &cfg.Auth, logger.GetLogger())
+	passkeyUseCase := usecase.NewPasskeyUseCase(passkeyRepo, passkeyChallengeRepo, userRepo, authUseCase, securityUseCase, &cfg.Passkey, logger.GetLogger())
+	checkinUseCase := usecase.NewCheckInUseCase(checkinRepo, userRepo, lockRepo, x402Client, &cfg.CheckIn, checkinLoc, logger.GetLogger())
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(idempotencyRepo, &cfg.CheckIn, logger.GetLogger())
+	userUseCase := usecase.NewUserUseCase(userRepo, profileRepo, checkinRepo, &cfg.CheckIn, checkinLoc)
+	walletUseCase := usecase.NewWalletUseCase(walletRepo, walletLinkRepo, userRepo, chains, sigVerifier, &cfg.Auth, logger.GetLogger())
+	didUseCase := usecase.NewDIDUseCase(userRepo, walletRepo, profileRepo, jwtMgr, &cfg.JWT)
+	migrationUseCase := usecase.NewMigrationUseCase(migrationRepo, migrationChallengeRepo, userRepo, walletRepo, rateLimitRepo, authUseCase, sigVerifier, &cfg.Auth, logger.GetLogger())
+	credentialUseCase := usecase.NewCredentialUseCase(credentialRepo, userRepo, checkinRepo, jwtMgr, &cfg.JWT, &cfg.Credential, &cfg.CheckIn, checkinLoc, logger.GetLogger())
+	authorizationUseCase := usecase.NewAuthorizationUseCase(authorizationRepo, consentChallengeRepo, userRepo, chains, &cfg.Auth, &cfg.Authorization, logger.GetLogger())
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, rateLimitRepo, &cfg.APIKey, &cfg.Authorization, logger.GetLogger())
+	oauthUseCase := usecase.NewOAuthUseCase(oauthClientRepo, oauthTokenRepo, authorizationRepo, userRepo, profileRepo, checkinRepo, walletRepo, credentialRepo, jwtMgr, &cfg.Authorization, &cfg.OAuth, &cfg.CheckIn, checkinLoc, logger.GetLogger())
+	mfaUseCase := usecase.NewMFAUseCase(totpRepo, stepUpRepo, rateLimitRepo, userRepo, totpCipher, &cfg.TOTP, logger.GetLogger())
+	adminUseCase := usecase.NewAdminUseCase(userRepo, checkinRepo, streakRuleRepo, authUseCase, &cfg.CheckIn, logger.GetLogger())
 
 	// Workers
-	checkinWorker := worker.NewCheckinWorker(checkinRepo, userRepo, tokenIssuer, &cfg.CheckIn, logger.GetLogger())
+	challengeCleanupWorker := worker.NewChallengeCleanupWorker(challengeStore, cfg.Auth.ChallengeCleanupInterval(), logger.GetLogger())
+	authorizationExpiryWorker := worker.NewAuthorizationExpiryWorker(authorizationUseCase, cfg.Authorization.SweepEvery(), logger.GetLogger())
+	checkinWorker := worker.NewCheckinWorker(checkinRepo, userRepo, walletRepo, streakRuleRepo, tokenIssuer, cfg.Blockchain.ChainID, &cfg.CheckIn, logger.GetLogger())
 
 	// Handlers
 	healthHandler := handler.NewHealthHandler()
+	wellKnownHandler := handler.NewWellKnownHandler(jwtMgr)
 	authHandler := handler.NewAuthHandler(authUseCase)
 	checkinHandler := handler.NewCheckInHandler(checkinUseCase)
 	userHandler := handler.NewUserHandler(userUseCase)
+	walletHandler := handler.NewWalletHandler(walletUseCase)
+	didHandler := handler.NewDIDHandler(didUseCase)
+	migrationHandler := handler.NewMigrationHandler(migrationUseCase)
+	credentialHandler := handler.NewCredentialHandler(credentialUseCase)
+	adminHandler := handler.NewAdminHandler(adminUseCase)
+	securityHandler := handler.NewSecurityHandler(securityUseCase)
+	authorizationHandler := handler.NewAuthorizationHandler(authorizationUseCase)
+	oauthHandler := handler.NewOAuthHandler(oauthUseCase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase)
+	mfaHandler := handler.NewMFAHandler(mfaUseCase)
+	passkeyHandler := handler.NewPasskeyHandler(passkeyUseCase)
 
 	// Set Gin mode
 	if cfg.Server.Env == "production" {
@@ -101,13 +230,26 @@ func main() {
 	r.Use(middleware.Logger(logger.GetLogger()))
 	r.Use(middleware.CORS())
 
+	// Register well-known routes (served at the root, outside /api)
+	routes.RegisterWellKnownRoutes(r, wellKnownHandler, didHandler, oauthHandler)
+
 	// Register API routes
 	api := r.Group("/api")
 	{
 		routes.RegisterHealthRoutes(api, healthHandler)
-		routes.RegisterAuthRoutes(api, authHandler, jwtMgr)
-		routes.RegisterUserRoutes(api, userHandler, jwtMgr)
-		routes.RegisterCheckInRoutes(api, checkinHandler, jwtMgr)
+		routes.RegisterAuthRoutes(api, authHandler, authUseCase)
+		routes.RegisterPasskeyRoutes(api, passkeyHandler, authUseCase)
+		routes.RegisterUserRoutes(api, userHandler, walletHandler, authUseCase, apiKeyUseCase)
+		routes.RegisterCheckInRoutes(api, checkinHandler, authUseCase, idempotencyUseCase)
+		routes.RegisterAdminRoutes(api, adminHandler, authUseCase, apiKeyUseCase, mfaUseCase)
+		routes.RegisterSecurityRoutes(api, securityHandler, authUseCase, mfaUseCase)
+		routes.RegisterMigrationRoutes(api, migrationHandler, authUseCase, mfaUseCase)
+		routes.RegisterDIDRoutes(api, didHandler)
+		routes.RegisterCredentialRoutes(api, credentialHandler, authUseCase, mfaUseCase)
+		routes.RegisterAuthorizationRoutes(api, authorizationHandler, authUseCase, apiKeyUseCase)
+		routes.RegisterOAuthRoutes(api, oauthHandler, authUseCase, mfaUseCase)
+		routes.RegisterAPIKeyRoutes(api, apiKeyHandler, authUseCase, mfaUseCase)
+		routes.RegisterMFARoutes(api, mfaHandler, authUseCase, mfaUseCase)
 	}
 
 	// Start server
@@ -121,6 +263,14 @@ func main() {
 	// Start checkin worker in background
 	go checkinWorker.Run(ctx)
 
+	// Mark authorizations past their expiry as EXPIRED
+	go authorizationExpiryWorker.Run(ctx)
+
+	// Expired challenges in the database store are not removed by a TTL
+	if cfg.Auth.ChallengeStoreBackend() == "database" {
+		go challengeCleanupWorker.Run(ctx)
+	}
+
 	// Start HTTP server in background
 	go func() {
 		if err := r.Run(addr); err != nil {
@@ -234,7 +234,7 @@ func waitForReceipt(ctx context.Context, client *ethclient.Client, txHash common
 	defer ticker.Stop()
 
 	for {
-	，返回	select {
+		select {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("timeout waiting for transaction receipt")
 		case <-ticker.C:
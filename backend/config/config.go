@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,10 +14,19 @@ type Config struct {
 	Database   DatabaseConfig   `mapstructure:"database"`
 	Redis      RedisConfig      `mapstructure:"redis"`
 	JWT        JWTConfig        `mapstructure:"jwt"`
+	Auth       AuthConfig       `mapstructure:"auth"`
 	Log        LogConfig        `mapstructure:"log"`
 	X402       X402Config       `mapstructure:"x402"`
 	CheckIn    CheckInConfig    `mapstructure:"checkin"`
 	Blockchain BlockchainConfig `mapstructure:"blockchain"`
+	Credential CredentialConfig `mapstructure:"credential"`
+	PoW        PoWConfig        `mapstructure:"pow"`
+
+	Authorization AuthorizationConfig `mapstructure:"authorization"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	APIKey        APIKeyConfig        `mapstructure:"api_key"`
+	TOTP          TOTPConfig          `mapstructure:"totp"`
+	Passkey       PasskeyConfig       `mapstructure:"passkey"`
 }
 
 type ServerConfig struct {
@@ -46,8 +56,68 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string `mapstructure:"secret"`
-	ExpireHour int    `mapstructure:"expire_hour"`
+	Secret             string         `mapstructure:"secret"`               // HS256 密钥，仅在未配置 keys 时使用
+	AccessExpireMinute int            `mapstructure:"access_expire_minute"` // access token 有效期（分钟）
+	RefreshExpireHour  int            `mapstructure:"refresh_expire_hour"`  // refresh token 有效期（小时）
+	Issuer             string         `mapstructure:"issuer"`               // token iss，例如 "https://api.dedata.io"
+	SigningKeyID       string         `mapstructure:"signing_key_id"`       // 当前用于签发的 key id
+	Keys               []JWTKeyConfig `mapstructure:"keys"`                 // 非对称密钥 (ES256 / EdDSA)，轮换期间保留旧公钥
+}
+
+// JWTKeyConfig JWT 签名密钥 (PEM 文件)
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"id"`               // kid
+	PrivateKeyFile string `mapstructure:"private_key_file"` // 私钥，签发 key 必填
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // 公钥，仅用于验证已退役的 key
+}
+
+// AuthConfig 钱包登录 (SIWE / EIP-4361) 配置
+type AuthConfig struct {
+	Domain          string  `mapstructure:"domain"`            // 签名消息中的 domain，例如 "app.dedata.io"
+	URI             string  `mapstructure:"uri"`               // 签名消息中的 URI，例如 "https://app.dedata.io"
+	Statement       string  `mapstructure:"statement"`         // 展示给用户的说明文字
+	ChainID         int64   `mapstructure:"chain_id"`          // 默认链 ID (前端未传时使用)
+	AllowedChainIDs []int64 `mapstructure:"allowed_chain_ids"` // 允许登录的链 ID，空表示只允许 chain_id
+	SolanaChainID   string  `mapstructure:"solana_chain_id"`   // Solana 集群的 CAIP-2 reference，为空时不允许 Solana 钱包登录
+	NonceTTL        int     `mapstructure:"nonce_ttl"`         // nonce 有效期（秒）
+	AutoLinkChains  bool    `mapstructure:"auto_link_chains"`  // 已注册地址在其他链上登录时自动绑定到同一用户 (仅 EOA / Solana 钱包)，默认关闭
+
+	ChainRPCs []ChainRPCConfig `mapstructure:"chain_rpcs"` // 除 blockchain.chain_id 外每条允许登录的 EVM 链的 RPC，用于验证 EIP-1271 合约钱包签名
+
+	ChallengeStore       string `mapstructure:"challenge_store"`        // 登录挑战存储: redis (默认) 或 database
+	MaxNoncesPerAddress  int    `mapstructure:"max_nonces_per_address"` // 每个地址同时未使用的 nonce 上限，默认 5
+	MaxNoncesPerIP       int    `mapstructure:"max_nonces_per_ip"`      // 每个 IP 同时未使用的 nonce 上限，默认 20
+	ChallengeCleanupMins int    `mapstructure:"challenge_cleanup_mins"` // database 存储清理过期挑战的间隔（分钟），默认 10
+
+	MigrationTimelockHour int `mapstructure:"migration_timelock_hour"` // 丢失旧钱包时迁移的时间锁（小时）
+	RecoveryPerDID        int `mapstructure:"recovery_per_did"`        // 每个 DID 每天允许发起钱包恢复的次数，默认 3
+	RecoveryPerIP         int `mapstructure:"recovery_per_ip"`         // 每个 IP 每天允许发起钱包恢复的次数，默认 10
+
+	EIP712  EIP712Config  `mapstructure:"eip712"`
+	Lockout LockoutConfig `mapstructure:"lockout"`
+}
+
+// LockoutConfig 登录暴力破解防护：按地址 / IP 统计验签失败次数并指数退避锁定
+type LockoutConfig struct {
+	AddressMaxFailures int `mapstructure:"address_max_failures"` // 每个地址窗口内允许的失败次数，默认 5
+	IPMaxFailures      int `mapstructure:"ip_max_failures"`      // 每个 IP 窗口内允许的失败次数，默认 20
+	WindowMins         int `mapstructure:"window_mins"`          // 失败计数窗口（分钟），默认 15
+	BaseLockoutSecs    int `mapstructure:"base_lockout_secs"`    // 首次锁定时长（秒），之后每次翻倍，默认 60
+	MaxLockoutMins     int `mapstructure:"max_lockout_mins"`     // 锁定时长上限（分钟），默认 1440
+	NonceRatePerMin    int `mapstructure:"nonce_rate_per_min"`   // 每个 IP 每分钟允许请求 nonce 的次数，默认 30
+}
+
+// ChainRPCConfig 允许登录的 EVM 链的只读 RPC 节点
+type ChainRPCConfig struct {
+	ChainID int64  `mapstructure:"chain_id"`
+	RPCURL  string `mapstructure:"rpc_url"`
+}
+
+// EIP712Config EIP-712 typed data 签名的 domain 配置
+type EIP712Config struct {
+	Name              string `mapstructure:"name"`               // domain name，例如 "DeData"
+	Version           string `mapstructure:"version"`            // schema 版本，修改签名结构时递增
+	VerifyingContract string `mapstructure:"verifying_contract"` // 可选，验证合约地址
 }
 
 type LogConfig struct {
@@ -68,6 +138,13 @@ type CheckInConfig struct {
 	RewardAmount   string `mapstructure:"reward_amount"`   // "10"  (10 DeData tokens)
 	WorkerInterval int    `mapstructure:"worker_interval"` // worker 轮询间隔（秒）
 	MaxRetryCount  int    `mapstructure:"max_retry_count"` // 最大重试次数
+
+	DailyLimit          int    `mapstructure:"daily_limit"`           // 每个用户每天最多成功签到次数，默认 1
+	Timezone            string `mapstructure:"timezone"`              // 签到日切换时区 (IANA 名称，例如 Asia/Shanghai)，默认 UTC
+	IdempotencyTTLHours int    `mapstructure:"idempotency_ttl_hours"` // Idempotency-Key 首次响应的保留时间（小时），默认 24
+	LockTimeoutSec      int    `mapstructure:"lock_timeout_sec"`      // 发起签到的用户锁超时（秒），默认 60
+
+	StreakGraceDays int `mapstructure:"streak_grace_days"` // 允许中断的天数，间隔不超过 1 + grace 天的签到仍计入连续签到；奖励规则见 checkin_streak_rules 表
 }
 
 type BlockchainConfig struct {
@@ -80,6 +157,67 @@ type BlockchainConfig struct {
 	ConfirmBlocks uint64 `mapstructure:"confirm_blocks"` // 等待确认的区块数
 }
 
+// CredentialConfig Verifiable Credential 签发配置 (使用 jwt.keys 中的签发密钥，issuer 为 jwt.issuer 对应的 did:web)
+type CredentialConfig struct {
+	ExpireDay int `mapstructure:"expire_day"` // credential 有效期（天）
+}
+
+// AuthorizationConfig 平台数据访问授权配置
+type AuthorizationConfig struct {
+	DefaultExpireDay int              `mapstructure:"default_expire_day"` // 未指定时授权的有效期（天），默认 90
+	MaxExpireDay     int              `mapstructure:"max_expire_day"`     // 授权有效期上限（天），默认 365
+	SweepInterval    int              `mapstructure:"sweep_interval"`     // 过期授权清理间隔（秒），默认 300
+	Platforms        []PlatformConfig `mapstructure:"platforms"`
+}
+
+// PlatformConfig 接入的合作平台，使用绑定该平台的 API key (authorizations:read) 查询授权状态
+type PlatformConfig struct {
+	ID     string   `mapstructure:"id"`     // 平台标识，写入 Authorization.Platform
+	Name   string   `mapstructure:"name"`   // 展示在同意消息中的名称
+	Scopes []string `mapstructure:"scopes"` // 平台可以申请的 scope，为空表示不限制
+}
+
+// OAuthConfig OAuth2 / OpenID Connect 授权服务器配置，issuer 使用 jwt.issuer
+type OAuthConfig struct {
+	AuthorizeURL   string `mapstructure:"authorize_url"`    // 前端授权同意页，例如 "https://app.dedata.io/oauth/authorize"
+	CodeExpireSec  int    `mapstructure:"code_expire_sec"`  // 授权码有效期（秒），默认 60
+	TokenExpireMin int    `mapstructure:"token_expire_min"` // access token 与 ID token 有效期（分钟），默认 60
+}
+
+// APIKeyConfig 服务端集成 API key 配置
+type APIKeyConfig struct {
+	DefaultRateLimit int `mapstructure:"default_rate_limit"` // 未单独设置时每个 key 每分钟的请求数，默认 60
+	MaxExpireDay     int `mapstructure:"max_expire_day"`     // key 有效期上限（天），0 表示允许永不过期
+}
+
+// TOTPConfig 管理员 TOTP 二次验证配置
+type TOTPConfig struct {
+	Issuer        string `mapstructure:"issuer"`          // 验证器中显示的签发方名称，默认 DeData
+	EncryptionKey string `mapstructure:"encryption_key"`  // base64 编码的 32 字节 AES 密钥，用于加密落库的 TOTP 密钥，通过环境变量覆盖
+	StepUpMinutes int    `mapstructure:"step_up_minutes"` // 二次验证通过后敏感操作的放行时长（分钟），默认 10
+	RecoveryCodes int    `mapstructure:"recovery_codes"`  // 每次生成的恢复码数量，默认 10
+	Enforce       bool   `mapstructure:"enforce"`         // 为 true 时未绑定 TOTP 的管理员不能执行敏感操作
+}
+
+// PasskeyConfig WebAuthn passkey 登录配置，rp_id 为空时不启用
+type PasskeyConfig struct {
+	RPID               string   `mapstructure:"rp_id"`                // 依赖方 ID，前端页面的有效域名 (不含协议和端口)
+	RPName             string   `mapstructure:"rp_name"`              // 注册时显示的名称，默认 DeData
+	Origins            []string `mapstructure:"origins"`              // 允许发起 WebAuthn 请求的页面 origin
+	ChallengeExpireSec int      `mapstructure:"challenge_expire_sec"` // challenge 有效期（秒），默认 300
+	MaxPerUser         int      `mapstructure:"max_per_user"`         // 每个用户最多注册的 passkey 数量，默认 10
+}
+
+// PoWConfig /auth/nonce 的工作量证明 (hashcash) 配置
+// 难度为 SHA-256 前导 0 位数：base_difficulty + 窗口内该 IP 的 nonce 请求数 / step_requests，不超过 max_difficulty
+type PoWConfig struct {
+	Enabled        bool `mapstructure:"enabled"`         // 是否要求工作量证明
+	BaseDifficulty int  `mapstructure:"base_difficulty"` // 基础难度（位），默认 16
+	MaxDifficulty  int  `mapstructure:"max_difficulty"`  // 难度上限（位），默认 24
+	StepRequests   int  `mapstructure:"step_requests"`   // 窗口内每多少次请求难度加 1 位，默认 10
+	WindowMins     int  `mapstructure:"window_mins"`     // 请求量统计窗口（分钟），默认 10
+}
+
 // Load 加载配置文件
 // 优先级: 环境变量 > YAML 配置文件
 func Load() (*Config, error) {
@@ -126,3 +264,371 @@ func (c *DatabaseConfig) GetDSN() string {
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// IsChainAllowed 检查链 ID 是否允许登录
+func (c *AuthConfig) IsChainAllowed(chainID int64) bool {
+	if len(c.AllowedChainIDs) == 0 {
+		return chainID == c.ChainID
+	}
+	for _, id := range c.AllowedChainIDs {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// EVMChainIDs 返回允许登录的 EVM 链 ID
+func (c *AuthConfig) EVMChainIDs() []int64 {
+	if len(c.AllowedChainIDs) == 0 {
+		return []int64{c.ChainID}
+	}
+	return c.AllowedChainIDs
+}
+
+// RPCURL 返回 chain_rpcs 中链 chainID 的 RPC 地址，未配置时返回空字符串
+func (c *AuthConfig) RPCURL(chainID int64) string {
+	for _, rpc := range c.ChainRPCs {
+		if rpc.ChainID == chainID {
+			return rpc.RPCURL
+		}
+	}
+	return ""
+}
+
+// SolanaEnabled 是否允许 Solana 钱包登录
+func (c *AuthConfig) SolanaEnabled() bool {
+	return c.SolanaChainID != ""
+}
+
+// NonceLifetime 返回 nonce 有效期，默认 5 分钟
+func (c *AuthConfig) NonceLifetime() time.Duration {
+	if c.NonceTTL <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(c.NonceTTL) * time.Second
+}
+
+// ChallengeStoreBackend 返回登录挑战存储后端，默认 redis
+func (c *AuthConfig) ChallengeStoreBackend() string {
+	if c.ChallengeStore == "" {
+		return "redis"
+	}
+	return c.ChallengeStore
+}
+
+// NonceLimitPerAddress 返回每个地址同时未使用的 nonce 上限，默认 5
+func (c *AuthConfig) NonceLimitPerAddress() int {
+	if c.MaxNoncesPerAddress <= 0 {
+		return 5
+	}
+	return c.MaxNoncesPerAddress
+}
+
+// NonceLimitPerIP 返回每个 IP 同时未使用的 nonce 上限，默认 20
+func (c *AuthConfig) NonceLimitPerIP() int {
+	if c.MaxNoncesPerIP <= 0 {
+		return 20
+	}
+	return c.MaxNoncesPerIP
+}
+
+// ChallengeCleanupInterval 返回 database 存储清理过期挑战的间隔，默认 10 分钟
+func (c *AuthConfig) ChallengeCleanupInterval() time.Duration {
+	if c.ChallengeCleanupMins <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.ChallengeCleanupMins) * time.Minute
+}
+
+// MigrationTimelock 返回 RECOVERY 迁移的时间锁，默认 7 天
+func (c *AuthConfig) MigrationTimelock() time.Duration {
+	if c.MigrationTimelockHour <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(c.MigrationTimelockHour) * time.Hour
+}
+
+// RecoveryDIDLimit 返回每个 DID 每天允许发起钱包恢复的次数，默认 3
+func (c *AuthConfig) RecoveryDIDLimit() int {
+	if c.RecoveryPerDID <= 0 {
+		return 3
+	}
+	return c.RecoveryPerDID
+}
+
+// RecoveryIPLimit 返回每个 IP 每天允许发起钱包恢复的次数，默认 10
+func (c *AuthConfig) RecoveryIPLimit() int {
+	if c.RecoveryPerIP <= 0 {
+		return 10
+	}
+	return c.RecoveryPerIP
+}
+
+// Window 返回失败计数窗口，默认 15 分钟
+func (c *LockoutConfig) Window() time.Duration {
+	if c.WindowMins <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(c.WindowMins) * time.Minute
+}
+
+// BaseLockout 返回首次锁定时长，默认 1 分钟
+func (c *LockoutConfig) BaseLockout() time.Duration {
+	if c.BaseLockoutSecs <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.BaseLockoutSecs) * time.Second
+}
+
+// MaxLockout 返回锁定时长上限，默认 24 小时
+func (c *LockoutConfig) MaxLockout() time.Duration {
+	if c.MaxLockoutMins <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.MaxLockoutMins) * time.Minute
+}
+
+// AddressLimit 返回每个地址窗口内允许的失败次数，默认 5
+func (c *LockoutConfig) AddressLimit() int {
+	if c.AddressMaxFailures <= 0 {
+		return 5
+	}
+	return c.AddressMaxFailures
+}
+
+// IPLimit 返回每个 IP 窗口内允许的失败次数，默认 20
+func (c *LockoutConfig) IPLimit() int {
+	if c.IPMaxFailures <= 0 {
+		return 20
+	}
+	return c.IPMaxFailures
+}
+
+// NonceRate 返回每个 IP 每分钟允许请求 nonce 的次数，默认 30
+func (c *LockoutConfig) NonceRate() int {
+	if c.NonceRatePerMin <= 0 {
+		return 30
+	}
+	return c.NonceRatePerMin
+}
+
+// PerDayLimit 返回每个用户每天最多成功签到次数，默认 1
+func (c *CheckInConfig) PerDayLimit() int {
+	if c.DailyLimit <= 0 {
+		return 1
+	}
+	return c.DailyLimit
+}
+
+// Location 加载签到日切换时区，所有用户共用同一时区，避免通过修改时区在一天内多次签到
+func (c *CheckInConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	// 签到日同时由数据库按时区名计算，"Local" 取决于进程环境，不能使用
+	if c.Timezone == "Local" {
+		return nil, fmt.Errorf("invalid checkin timezone %q: use an IANA timezone name", c.Timezone)
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkin timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// IdempotencyTTL 返回 Idempotency-Key 首次响应的保留时间，默认 24 小时
+func (c *CheckInConfig) IdempotencyTTL() time.Duration {
+	if c.IdempotencyTTLHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.IdempotencyTTLHours) * time.Hour
+}
+
+// LockTimeout 返回发起签到的用户锁超时，需大于 x402 请求超时，默认 60 秒
+func (c *CheckInConfig) LockTimeout() time.Duration {
+	if c.LockTimeoutSec <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(c.LockTimeoutSec) * time.Second
+}
+
+// Lifetime 返回 credential 有效期，默认 365 天
+func (c *CredentialConfig) Lifetime() time.Duration {
+	if c.ExpireDay <= 0 {
+		return 365 * 24 * time.Hour
+	}
+	return time.Duration(c.ExpireDay) * 24 * time.Hour
+}
+
+// Platform 通过 ID 查找平台
+func (c *AuthorizationConfig) Platform(id string) (*PlatformConfig, bool) {
+	for i := range c.Platforms {
+		if c.Platforms[i].ID == id {
+			return &c.Platforms[i], true
+		}
+	}
+	return nil, false
+}
+
+// GrantLifetime 返回授权有效期：未指定时使用默认值，超过上限时截断
+func (c *AuthorizationConfig) GrantLifetime(days int) time.Duration {
+	defaultDays, maxDays := c.DefaultExpireDay, c.MaxExpireDay
+	if defaultDays <= 0 {
+		defaultDays = 90
+	}
+	if maxDays <= 0 {
+		maxDays = 365
+	}
+	if days <= 0 {
+		days = defaultDays
+	}
+	if days > maxDays {
+		days = maxDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// SweepEvery 返回过期授权清理间隔，默认 5 分钟
+func (c *AuthorizationConfig) SweepEvery() time.Duration {
+	if c.SweepInterval <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(c.SweepInterval) * time.Second
+}
+
+// AllowsScope 平台是否可以申请该 scope
+func (p *PlatformConfig) AllowsScope(scope string) bool {
+	if len(p.Scopes) == 0 {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CodeLifetime 返回授权码有效期，默认 60 秒
+func (c *OAuthConfig) CodeLifetime() time.Duration {
+	if c.CodeExpireSec <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.CodeExpireSec) * time.Second
+}
+
+// TokenLifetime 返回 access token 与 ID token 有效期，默认 60 分钟
+func (c *OAuthConfig) TokenLifetime() time.Duration {
+	if c.TokenExpireMin <= 0 {
+		return time.Hour
+	}
+	return time.Duration(c.TokenExpireMin) * time.Minute
+}
+
+// RateLimit 返回 key 每分钟允许的请求数
+func (c *APIKeyConfig) RateLimit(perKey int) int {
+	if perKey > 0 {
+		return perKey
+	}
+	if c.DefaultRateLimit <= 0 {
+		return 60
+	}
+	return c.DefaultRateLimit
+}
+
+// ExpireDays 返回 key 的有效期（天），0 表示永不过期；配置了上限时不超过上限
+func (c *APIKeyConfig) ExpireDays(days int) int {
+	if c.MaxExpireDay > 0 && (days <= 0 || days > c.MaxExpireDay) {
+		return c.MaxExpireDay
+	}
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// IssuerName 返回 TOTP 签发方名称
+func (c *TOTPConfig) IssuerName() string {
+	if c.Issuer == "" {
+		return "DeData"
+	}
+	return c.Issuer
+}
+
+// StepUpLifetime 返回二次验证的放行时长，默认 10 分钟
+func (c *TOTPConfig) StepUpLifetime() time.Duration {
+	if c.StepUpMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.StepUpMinutes) * time.Minute
+}
+
+// RecoveryCodeCount 返回每次生成的恢复码数量，默认 10
+func (c *TOTPConfig) RecoveryCodeCount() int {
+	if c.RecoveryCodes <= 0 {
+		return 10
+	}
+	return c.RecoveryCodes
+}
+
+// Enabled 是否启用 passkey 登录
+func (c *PasskeyConfig) Enabled() bool {
+	return c.RPID != "" && len(c.Origins) > 0
+}
+
+// DisplayName 返回依赖方名称
+func (c *PasskeyConfig) DisplayName() string {
+	if c.RPName == "" {
+		return "DeData"
+	}
+	return c.RPName
+}
+
+// ChallengeLifetime 返回 challenge 有效期，默认 5 分钟
+func (c *PasskeyConfig) ChallengeLifetime() time.Duration {
+	if c.ChallengeExpireSec <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(c.ChallengeExpireSec) * time.Second
+}
+
+// Limit 返回每个用户最多注册的 passkey 数量
+func (c *PasskeyConfig) Limit() int {
+	if c.MaxPerUser <= 0 {
+		return 10
+	}
+	return c.MaxPerUser
+}
+
+// Difficulty 根据窗口内的请求数计算难度，未启用时返回 0
+func (c *PoWConfig) Difficulty(requests int64) int {
+	if !c.Enabled {
+		return 0
+	}
+
+	base, max, step := c.BaseDifficulty, c.MaxDifficulty, c.StepRequests
+	if base <= 0 {
+		base = 16
+	}
+	if max <= 0 {
+		max = 24
+	}
+	if step <= 0 {
+		step = 10
+	}
+
+	difficulty := base + int(requests/int64(step))
+	if difficulty > max {
+		return max
+	}
+	return difficulty
+}
+
+// Window 返回请求量统计窗口，默认 10 分钟
+func (c *PoWConfig) Window() time.Duration {
+	if c.WindowMins <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.WindowMins) * time.Minute
+}
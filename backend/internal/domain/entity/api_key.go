@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix API key 的固定前缀，middleware 据此区分 API key 与用户 JWT
+const APIKeyPrefix = "ddk_"
+
+// API key 可以授予的权限范围
+const (
+	APIScopeLeaderboardRead    = "leaderboard:read"    // 排行榜
+	APIScopeUsersRead          = "users:read"          // 用户列表与详情 (管理后台)
+	APIScopeCheckinsRead       = "checkins:read"       // 用户签到记录 (管理后台)
+	APIScopeAdminPayouts       = "admin:payouts"       // 待发放的签到奖励 (管理后台)
+	APIScopeAuthorizationsRead = "authorizations:read" // 合作平台查询用户授权状态，key 需绑定平台
+)
+
+// KnownAPIScopes 所有支持的 API key scope
+var KnownAPIScopes = []string{APIScopeLeaderboardRead, APIScopeUsersRead, APIScopeCheckinsRead, APIScopeAdminPayouts, APIScopeAuthorizationsRead}
+
+// APIScopeRole 返回 scope 对应的角色，API key 请求以该角色通过 RequireRole 校验
+// 管理后台的只读 scope 对应 ADMIN，其余对应 USER
+func APIScopeRole(scope string) UserRole {
+	switch scope {
+	case APIScopeUsersRead, APIScopeCheckinsRead, APIScopeAdminPayouts:
+		return RoleAdmin
+	default:
+		return RoleUser
+	}
+}
+
+// APIKey 服务端集成使用的 API key，只保存摘要
+// 完整的 key 为 Prefix + "_" + secret，Prefix 用于查找与展示
+type APIKey struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(16);uniqueIndex;not null"` // 例如 ddk_1a2b3c4d
+	KeyHash    string     `json:"-" gorm:"column:key_hash;type:varchar(64);not null"`
+	Scope      string     `json:"scope" gorm:"type:varchar(200);not null"`      // 空格分隔
+	Platform   string     `json:"platform,omitempty" gorm:"type:varchar(50)"`   // 合作平台 ID，authorizations:read 需要
+	RateLimit  int        `json:"rateLimit" gorm:"column:rate_limit;default:0"` // 每分钟请求数，0 表示使用默认值
+	CreatedBy  string     `json:"createdBy" gorm:"column:created_by;type:uuid"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" gorm:"column:last_used_at"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty" gorm:"column:last_used_ip;type:varchar(64)"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" gorm:"column:expires_at"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive 是否未吊销且未过期
+func (k *APIKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
+
+// Scopes 返回 scope 列表
+func (k *APIKey) Scopes() []string {
+	return strings.Fields(k.Scope)
+}
+
+// HasScope 是否包含该 scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
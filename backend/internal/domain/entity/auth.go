@@ -4,12 +4,19 @@ import "time"
 
 // LoginChallenge 登录挑战(nonce)
 type LoginChallenge struct {
-	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Address   string     `json:"address" gorm:"column:wallet_address;index;not null"`
-	Nonce     string     `json:"nonce" gorm:"uniqueIndex;not null"`
-	Used      bool       `json:"used" gorm:"default:false"`
-	ExpiresAt time.Time  `json:"expiresAt" gorm:"column:expires_at;index;not null"`
-	CreatedAt time.Time  `json:"createdAt" gorm:"column:created_at"`
+	ID            string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Address       string    `json:"address" gorm:"column:wallet_address;index;not null"`
+	Nonce         string    `json:"nonce" gorm:"uniqueIndex;not null"`
+	Namespace     string    `json:"chainNamespace" gorm:"column:chain_namespace;type:varchar(16);default:'eip155'"`
+	ChainID       string    `json:"chainId" gorm:"column:chain_id;type:varchar(64);not null"` // CAIP-2 reference，EVM 为十进制链 ID
+	Domain        string    `json:"domain" gorm:"type:varchar(255);not null"`
+	URI           string    `json:"uri" gorm:"column:uri;type:varchar(255);not null"`
+	IPAddress     string    `json:"ipAddress" gorm:"column:ip_address;type:varchar(64)"`  // 请求 nonce 的客户端 IP，用于限制未使用的挑战数
+	PoWDifficulty int       `json:"powDifficulty" gorm:"column:pow_difficulty;default:0"` // 要求的工作量证明难度 (前导 0 位数)，0 表示不要求
+	Used          bool      `json:"used" gorm:"default:false"`
+	IssuedAt      time.Time `json:"issuedAt" gorm:"column:issued_at;not null"`
+	ExpiresAt     time.Time `json:"expiresAt" gorm:"column:expires_at;index;not null"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"column:created_at"`
 }
 
 // IsExpired 检查是否过期
@@ -26,3 +33,50 @@ func (c *LoginChallenge) IsUsed() bool {
 func (LoginChallenge) TableName() string {
 	return "login_challenges"
 }
+
+// SessionScope 登录会话的权限范围
+type SessionScope string
+
+const (
+	SessionScopeFull SessionScope = "full" // 钱包签名登录，不受限制
+	SessionScopeRead SessionScope = "read" // passkey 登录，只能执行读操作，支付与钱包变更需要钱包签名
+)
+
+// RefreshToken 刷新令牌 (存储于 Redis，只保存令牌摘要)
+// 同一次登录轮换出的 refresh token 共享 SessionID (token family)
+type RefreshToken struct {
+	TokenHash string       `json:"tokenHash"`
+	SessionID string       `json:"sessionId"`
+	UserID    string       `json:"userId"`
+	Scope     SessionScope `json:"scope,omitempty"` // 轮换出的 access token 沿用会话的权限范围
+	ExpiresAt time.Time    `json:"expiresAt"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// IsExpired 检查是否过期
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// Session 登录会话，对应一个 refresh token family，ID 即 token 中的 sid
+type Session struct {
+	ID         string       `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     string       `json:"userId" gorm:"index;not null"`
+	Scope      SessionScope `json:"scope" gorm:"type:varchar(16);default:'full'"`
+	UserAgent  string       `json:"userAgent" gorm:"type:text"`
+	IPAddress  string       `json:"ipAddress" gorm:"column:ip_address;type:varchar(64)"`
+	LastSeenAt time.Time    `json:"lastSeenAt" gorm:"column:last_seen_at;not null"`
+	ExpiresAt  time.Time    `json:"expiresAt" gorm:"column:expires_at;not null"`
+	RevokedAt  *time.Time   `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	CreatedAt  time.Time    `json:"createdAt" gorm:"column:created_at"`
+}
+
+// IsActive 会话是否仍然有效
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "user_sessions"
+}
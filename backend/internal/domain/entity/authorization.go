@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type AuthorizationStatus string
 
@@ -10,14 +13,86 @@ const (
 	AuthExpired AuthorizationStatus = "EXPIRED"
 )
 
+// 平台可以申请的数据访问范围
+const (
+	ScopeProfileRead     = "profile:read"     // 昵称、头像等公开资料
+	ScopeCheckinsRead    = "checkins:read"    // 签到记录与统计
+	ScopeCredentialsRead = "credentials:read" // 已签发的 Verifiable Credential
+	ScopeWalletsRead     = "wallets:read"     // 绑定的钱包列表
+)
+
+// KnownScopes 所有支持的 scope
+var KnownScopes = []string{ScopeProfileRead, ScopeCheckinsRead, ScopeCredentialsRead, ScopeWalletsRead}
+
 // Authorization represents a platform authorization
+// 用户用主钱包签名同意消息后授予平台 Scope 内的数据访问权限，同一平台同时只有一条 ACTIVE 授权
 type Authorization struct {
 	ID        string              `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID    string              `json:"userId" gorm:"index;not null"`
 	Platform  string              `json:"platform" gorm:"type:varchar(50);not null"`
-	Scope     string              `json:"scope" gorm:"type:varchar(200)"`
+	Scope     string              `json:"scope" gorm:"type:varchar(200)"` // 空格分隔的 scope 列表
 	Status    AuthorizationStatus `json:"status" gorm:"type:varchar(20);default:'ACTIVE'"`
+	SignedBy  string              `json:"signedBy" gorm:"column:signed_by;type:varchar(200)"` // 签署同意消息的钱包 (CAIP-10)
+	Message   string              `json:"message" gorm:"type:text"`                           // 用户签署的同意消息原文
+	Signature string              `json:"-" gorm:"type:text"`
 	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time          `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
 	CreatedAt time.Time           `json:"createdAt"`
 	UpdatedAt time.Time           `json:"updatedAt"`
 }
+
+// IsActive 授权是否仍然有效 (未吊销且未过期，过期但尚未被清理的也视为无效)
+func (a *Authorization) IsActive() bool {
+	if a.Status != AuthActive {
+		return false
+	}
+	return a.ExpiresAt == nil || time.Now().Before(*a.ExpiresAt)
+}
+
+// Scopes 返回授权的 scope 列表
+func (a *Authorization) Scopes() []string {
+	return strings.Fields(a.Scope)
+}
+
+// HasScopes 授权是否包含全部指定的 scope
+func (a *Authorization) HasScopes(scopes ...string) bool {
+	granted := a.Scopes()
+	for _, scope := range scopes {
+		found := false
+		for _, g := range granted {
+			if g == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TableName 指定表名
+func (Authorization) TableName() string {
+	return "authorizations"
+}
+
+// ConsentChallenge 授予平台数据访问权限的同意挑战 (存储于 Redis)
+// 由已登录会话发起，需主钱包签名 Message 完成授权
+type ConsentChallenge struct {
+	Nonce          string     `json:"nonce"`
+	UserID         string     `json:"userId"`
+	SessionID      string     `json:"sessionId"`
+	Account        string     `json:"account"` // 签名钱包的 CAIP-10 标识
+	Platform       string     `json:"platform"`
+	Scope          string     `json:"scope"`
+	GrantExpiresAt *time.Time `json:"grantExpiresAt,omitempty"` // 授权的过期时间
+	Message        string     `json:"message"`                  // 待签名原文
+	IssuedAt       time.Time  `json:"issuedAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (c *ConsentChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
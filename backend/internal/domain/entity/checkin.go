@@ -35,6 +35,11 @@ type CheckIn struct {
 	FailureReason *string `json:"failureReason,omitempty" gorm:"type:text"`         // 失败原因
 	RetryCount    int     `json:"retryCount" gorm:"default:0"`                      // 重试次数
 
+	// 每日签到名额：支付验证成功时占用，(user_id, checkin_day, day_slot) 唯一
+	CheckInDay *time.Time `json:"checkinDay,omitempty" gorm:"column:checkin_day;type:date"` // 计入的签到日
+	DaySlot    *int       `json:"-" gorm:"column:day_slot"`                                 // 当天的第几个名额，从 1 开始
+	StreakDays int        `json:"streakDays" gorm:"column:streak_days;default:0"`           // 计入本次签到后的连续签到天数，决定奖励规则
+
 	CreatedAt time.Time  `json:"createdAt" gorm:"index"`
 	UpdatedAt time.Time  `json:"updatedAt"`
 	IssuedAt  *time.Time `json:"issuedAt,omitempty"` // token 发放完成时间
@@ -65,6 +70,31 @@ func (c *CheckIn) CanRetry() bool {
 	return c.Status == CheckInPaymentFailed || c.Status == CheckInIssueFailed
 }
 
+// StreakRule 连续签到奖励规则，连续签到达到 MinDays 天时奖励为 reward_amount * Multiplier + Bonus
+// 同时满足多条规则时使用 MinDays 最大的一条
+type StreakRule struct {
+	MinDays    int       `json:"minDays" gorm:"primaryKey;autoIncrement:false"`  // 连续签到天数下限
+	Multiplier string    `json:"multiplier" gorm:"type:varchar(78);default:'1'"` // 基础奖励倍数
+	Bonus      string    `json:"bonus" gorm:"type:varchar(78);default:'0'"`      // 额外奖励的 token 数量
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (StreakRule) TableName() string {
+	return "checkin_streak_rules"
+}
+
+// MatchStreakRule 返回连续签到 streakDays 天适用的奖励规则 (MinDays 最大且不超过 streakDays)，没有时返回 nil
+func MatchStreakRule(rules []*StreakRule, streakDays int) *StreakRule {
+	var matched *StreakRule
+	for _, rule := range rules {
+		if rule.MinDays <= streakDays && (matched == nil || rule.MinDays > matched.MinDays) {
+			matched = rule
+		}
+	}
+	return matched
+}
+
 // TokenPayout 保留原有的 Payout 结构(可能用于其他场景)
 type PayoutStatus string
 
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type CredentialType string
+
+const (
+	CredentialCheckInCount  CredentialType = "CheckInCountCredential"  // 成功签到次数
+	CredentialCheckInStreak CredentialType = "CheckInStreakCredential" // 连续签到天数
+	CredentialRewards       CredentialType = "RewardsCredential"       // 累积奖励
+)
+
+// IsValid 是否为已定义的 credential 类型
+func (t CredentialType) IsValid() bool {
+	return t == CredentialCheckInCount || t == CredentialCheckInStreak || t == CredentialRewards
+}
+
+// Credential 已签发的 Verifiable Credential (VC-JWT)
+// StatusIndex 为全局吊销状态序号，对应状态列表 StatusIndex / 131072 中的第 StatusIndex % 131072 位
+type Credential struct {
+	ID          string         `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string         `json:"userId" gorm:"column:user_id;index;not null"`
+	SubjectDID  string         `json:"subjectDid" gorm:"column:subject_did;not null"`
+	Type        CredentialType `json:"type" gorm:"type:varchar(64);not null"`
+	Subject     string         `json:"subject" gorm:"type:jsonb;not null"` // credentialSubject JSON
+	Token       string         `json:"credential" gorm:"type:text;not null"`
+	StatusIndex int64          `json:"statusIndex" gorm:"column:status_index;uniqueIndex;not null"`
+	IssuedAt    time.Time      `json:"issuedAt" gorm:"column:issued_at;not null"`
+	ExpiresAt   time.Time      `json:"expiresAt" gorm:"column:expires_at;not null"`
+	RevokedAt   *time.Time     `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	CreatedAt   time.Time      `json:"createdAt" gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (Credential) TableName() string {
+	return "verifiable_credentials"
+}
+
+// IsRevoked 是否已吊销
+func (c *Credential) IsRevoked() bool {
+	return c.RevokedAt != nil
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord 带 Idempotency-Key 的请求记录
+// 请求处理中时 StatusCode 为 0，处理完成后保存首次响应，重复的 key 直接重放
+type IdempotencyRecord struct {
+	Fingerprint string    `json:"fingerprint"` // 请求体摘要，同一个 key 不能用于不同的请求
+	StatusCode  int       `json:"statusCode,omitempty"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// IsCompleted 首次请求是否已处理完成
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// LockoutScope 登录失败的计数维度
+type LockoutScope string
+
+const (
+	LockoutScopeAddress LockoutScope = "address" // 钱包地址 (EVM 地址统一小写)
+	LockoutScopeIP      LockoutScope = "ip"      // 客户端 IP
+)
+
+// Lockout 登录锁定状态 (存储于 Redis，到期自动解除)
+type Lockout struct {
+	Scope       LockoutScope `json:"scope"`
+	Key         string       `json:"key"`
+	Level       int          `json:"level"` // 连续第几次锁定，锁定时长按等级指数增长
+	LockedUntil time.Time    `json:"lockedUntil"`
+}
+
+// IsActive 锁定是否仍然生效
+func (l *Lockout) IsActive() bool {
+	return time.Now().Before(l.LockedUntil)
+}
@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+type MigrationMode string
+type MigrationStatus string
+
+const (
+	MigrationHandover MigrationMode = "HANDOVER" // 旧钱包签名移交，立即生效
+	MigrationRecovery MigrationMode = "RECOVERY" // 旧钱包丢失，仅新钱包签名，时间锁到期后由管理员批准
+)
+
+const (
+	MigrationPending   MigrationStatus = "PENDING"
+	MigrationCompleted MigrationStatus = "COMPLETED"
+	MigrationCancelled MigrationStatus = "CANCELLED" // 用户在时间锁内取消
+	MigrationRejected  MigrationStatus = "REJECTED"  // 管理员拒绝
+)
+
+// WalletMigration 账户迁移到新钱包地址的记录
+// DID、TotalRewards、签到记录和 Profile 均挂在 UserID 上，迁移只替换主钱包地址
+type WalletMigration struct {
+	ID          string          `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      string          `json:"userId" gorm:"column:user_id;index;not null"`
+	FromAddress string          `json:"fromAddress" gorm:"column:from_address;not null"`
+	ToAddress   string          `json:"toAddress" gorm:"column:to_address;not null"`
+	ChainID     int64           `json:"chainId" gorm:"column:chain_id;not null"`
+	Mode        MigrationMode   `json:"mode" gorm:"type:varchar(20);not null"`
+	Status      MigrationStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	KeyVerified bool            `json:"-" gorm:"column:key_verified;default:false"`       // 新钱包签名通过 ecrecover 恢复出地址 (EOA)，执行时写入 user_wallets.key_verified
+	UnlocksAt   *time.Time      `json:"unlocksAt,omitempty" gorm:"column:unlocks_at"`     // RECOVERY 模式的时间锁到期时间
+	ReviewedBy  *string         `json:"reviewedBy,omitempty" gorm:"column:reviewed_by"`   // 批准或拒绝的管理员
+	Reason      *string         `json:"reason,omitempty" gorm:"type:text"`                // 拒绝或取消原因
+	CompletedAt *time.Time      `json:"completedAt,omitempty" gorm:"column:completed_at"` // 迁移生效时间
+	CreatedAt   time.Time       `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt   time.Time       `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (WalletMigration) TableName() string {
+	return "wallet_migrations"
+}
+
+// IsPending 是否等待批准
+func (m *WalletMigration) IsPending() bool {
+	return m.Status == MigrationPending
+}
+
+// IsUnlocked 时间锁是否已到期
+func (m *WalletMigration) IsUnlocked() bool {
+	return m.UnlocksAt == nil || !time.Now().Before(*m.UnlocksAt)
+}
+
+// MigrationChallenge 迁移挑战 (存储于 Redis)
+// HANDOVER 由已登录会话发起并绑定到该会话，新旧钱包都需签名 Message；RECOVERY 只需新钱包签名
+type MigrationChallenge struct {
+	Nonce       string        `json:"nonce"`
+	Mode        MigrationMode `json:"mode"`
+	UserID      string        `json:"userId"`
+	SessionID   string        `json:"sessionId,omitempty"`
+	DID         string        `json:"did"`
+	FromAddress string        `json:"fromAddress"`
+	FromChainID int64         `json:"fromChainId"` // 旧钱包所在链，旧钱包的签名在该链上验证
+	ToAddress   string        `json:"toAddress"`
+	ChainID     int64         `json:"chainId"` // 新钱包所在链
+	Message     string        `json:"message"` // personal_sign 待签名原文
+	IssuedAt    time.Time     `json:"issuedAt"`
+	ExpiresAt   time.Time     `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (c *MigrationChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// ScopeOpenID OAuth 客户端请求 ID token 的 scope (OpenID Connect)
+const ScopeOpenID = "openid"
+
+// OAuthClient 接入 "Log in with DeData" 的第三方应用，ID 即 client_id
+// 没有 SecretHash 的是公共客户端 (SPA / 移动端)，只依赖 PKCE
+type OAuthClient struct {
+	ID           string    `json:"clientId" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null"`
+	SecretHash   string    `json:"-" gorm:"column:secret_hash;type:varchar(64)"`
+	RedirectURIs string    `json:"redirectUris" gorm:"column:redirect_uris;type:text;not null"` // 空格分隔，回调地址必须完全匹配
+	Scope        string    `json:"scope" gorm:"type:varchar(200);not null"`                     // 允许申请的 scope，空格分隔
+	CreatedBy    string    `json:"createdBy" gorm:"column:created_by;type:uuid"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// IsPublic 是否为公共客户端
+func (c *OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+// RedirectURIList 返回已登记的回调地址
+func (c *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(c.RedirectURIs)
+}
+
+// Scopes 返回允许申请的 scope
+func (c *OAuthClient) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// AllowsRedirectURI 回调地址是否已登记 (完全匹配)
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	for _, u := range c.RedirectURIList() {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScope 客户端是否可以申请该 scope
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	for _, s := range c.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthCode 授权码 (存储于 Redis，只保存摘要，只能兑换一次)
+type OAuthCode struct {
+	CodeHash      string    `json:"codeHash"`
+	ClientID      string    `json:"clientId"`
+	UserID        string    `json:"userId"`
+	RedirectURI   string    `json:"redirectUri"`
+	Scope         string    `json:"scope"`
+	CodeChallenge string    `json:"codeChallenge"` // PKCE S256
+	Nonce         string    `json:"nonce,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (c *OAuthCode) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// OAuthAccessToken 签发给第三方应用的 access token (存储于 Redis，只保存摘要)
+// 只能调用 userinfo，不能作为登录会话的 access token 使用
+type OAuthAccessToken struct {
+	TokenHash string    `json:"tokenHash"`
+	ClientID  string    `json:"clientId"`
+	UserID    string    `json:"userId"`
+	Scope     string    `json:"scope"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (t *OAuthAccessToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// Scopes 返回 token 的 scope 列表
+func (t *OAuthAccessToken) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// HasScope token 是否包含该 scope
+func (t *OAuthAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
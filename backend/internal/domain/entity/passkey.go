@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Passkey 用户注册的 WebAuthn 凭证，用于免签名登录 (只读会话)
+type Passkey struct {
+	ID             string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID         string     `json:"userId" gorm:"column:user_id;type:uuid;index;not null"`
+	Name           string     `json:"name" gorm:"type:varchar(100)"`
+	CredentialID   string     `json:"credentialId" gorm:"column:credential_id;type:varchar(1400);uniqueIndex;not null"` // base64url
+	PublicKey      []byte     `json:"-" gorm:"column:public_key;type:bytea;not null"`                                   // COSE_Key
+	Algorithm      int        `json:"algorithm" gorm:"column:algorithm;not null"`                                       // COSE 算法，例如 -7 (ES256)
+	SignCount      int64      `json:"-" gorm:"column:sign_count;not null;default:0"`
+	AAGUID         string     `json:"aaguid" gorm:"column:aaguid;type:varchar(32)"` // 认证器型号 (hex)，"none" 证明下可能全为 0
+	Transports     string     `json:"transports" gorm:"type:varchar(100)"`          // 空格分隔，例如 "internal hybrid"
+	BackupEligible bool       `json:"backupEligible" gorm:"column:backup_eligible;default:false"`
+	BackedUp       bool       `json:"backedUp" gorm:"column:backed_up;default:false"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty" gorm:"column:last_used_at"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Passkey) TableName() string {
+	return "passkeys"
+}
+
+// TransportList 返回 transports 列表
+func (p *Passkey) TransportList() []string {
+	return strings.Fields(p.Transports)
+}
+
+// passkey 挑战的用途
+const (
+	PasskeyChallengeRegistration = "registration"
+	PasskeyChallengeLogin        = "login"
+)
+
+// PasskeyChallenge WebAuthn 注册或登录挑战 (存储于 Redis)，以浏览器回传的 challenge 查找
+type PasskeyChallenge struct {
+	Challenge string    `json:"challenge"` // base64url
+	Purpose   string    `json:"purpose"`   // registration | login
+	UserID    string    `json:"userId,omitempty"`
+	SessionID string    `json:"sessionId,omitempty"` // 注册挑战绑定发起的钱包登录会话
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (c *PasskeyChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
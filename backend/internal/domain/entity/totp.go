@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// TOTPEnrollment 管理员的 TOTP 二次验证绑定，密钥以 AES-GCM 加密保存
+type TOTPEnrollment struct {
+	UserID          string     `json:"userId" gorm:"column:user_id;primaryKey;type:uuid"`
+	SecretEncrypted string     `json:"-" gorm:"column:secret_encrypted;type:text;not null"`
+	ConfirmedAt     *time.Time `json:"confirmedAt,omitempty" gorm:"column:confirmed_at"`  // 为空表示尚未用验证码确认，不生效
+	LastUsedStep    int64      `json:"-" gorm:"column:last_used_step;not null;default:0"` // 最近一次通过验证的时间步，防止验证码重放
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (TOTPEnrollment) TableName() string {
+	return "user_totp"
+}
+
+// IsConfirmed 是否已确认绑定
+func (e *TOTPEnrollment) IsConfirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+// TOTPRecoveryCode 一次性恢复码，只保存摘要
+type TOTPRecoveryCode struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string     `json:"userId" gorm:"column:user_id;type:uuid;index;not null"`
+	CodeHash  string     `json:"-" gorm:"column:code_hash;type:varchar(64);not null"`
+	UsedAt    *time.Time `json:"usedAt,omitempty" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (TOTPRecoveryCode) TableName() string {
+	return "user_totp_recovery_codes"
+}
@@ -20,13 +20,18 @@ const (
 type User struct {
 	ID               string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	DID              string     `json:"did" gorm:"column:did;uniqueIndex;not null"`
+	Account          string     `json:"account" gorm:"column:account;uniqueIndex"` // 主钱包的 CAIP-10 标识，例如 eip155:137:0xAbC...
 	WalletAddress    string     `json:"walletAddress" gorm:"column:wallet_address;uniqueIndex;not null"`
-	ChainID          int        `json:"chainId" gorm:"column:chain_id;not null"`
+	ChainID          int        `json:"chainId" gorm:"column:chain_id;not null"`                                        // 主钱包的 EVM 链 ID，非 EVM 钱包为 0
+	Namespace        string     `json:"chainNamespace" gorm:"column:chain_namespace;type:varchar(16);default:'eip155'"` // 主钱包所属链家族 (CAIP-2 namespace)
 	Role             UserRole   `json:"role" gorm:"type:varchar(20);default:'USER'"`
 	Status           UserStatus `json:"status" gorm:"type:varchar(20);default:'ACTIVE'"`
 	ProfileCompleted bool       `json:"profileCompleted" gorm:"column:profile_completed;default:false"`
 	TotalRewards     string     `json:"totalRewards" gorm:"column:total_rewards;type:decimal(36,18);default:0"` // 累积奖励
 	LastCheckinAt    *time.Time `json:"lastCheckinAt,omitempty" gorm:"column:last_checkin_at;index"`            // 最后签到时间
+	CurrentStreak    int        `json:"-" gorm:"column:current_streak;default:0"`                               // 截至 LastStreakDay 的连续签到天数，是否中断见 StreakAt
+	LongestStreak    int        `json:"longestStreak" gorm:"column:longest_streak;default:0"`                   // 历史最长连续签到天数
+	LastStreakDay    *time.Time `json:"-" gorm:"column:last_streak_day;type:date"`                              // 最近一次计入连续签到的签到日
 	CreatedAt        time.Time  `json:"createdAt" gorm:"column:created_at"`
 	UpdatedAt        time.Time  `json:"updatedAt" gorm:"column:updated_at"`
 
@@ -34,6 +39,40 @@ type User struct {
 	Profile *Profile `json:"profile,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// IsActive 用户是否处于正常状态 (未被暂停或拉黑)
+func (u *User) IsActive() bool {
+	return u.Status == StatusActive
+}
+
+// StreakAt 返回签到日 today 时仍有效的连续签到天数
+// 距最近一次签到超过 1 + graceDays 天视为已中断，返回 0
+func (u *User) StreakAt(today time.Time, graceDays int) int {
+	if u.LastStreakDay == nil {
+		return 0
+	}
+	if daysBetween(*u.LastStreakDay, today) > 1+graceDays {
+		return 0
+	}
+	return u.CurrentStreak
+}
+
+// daysBetween 按日期部分计算 from 到 to 相差的天数
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// IsValid 是否为已定义的角色
+func (r UserRole) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
+// IsValid 是否为已定义的状态
+func (s UserStatus) IsValid() bool {
+	return s == StatusActive || s == StatusSuspended || s == StatusBlacklisted
+}
+
 // Profile represents user profile information
 type Profile struct {
 	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
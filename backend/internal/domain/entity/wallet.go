@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// UserWallet 用户绑定的钱包，任一钱包登录都解析到同一用户和 DID
+// 钱包以 CAIP-10 账户标识区分，同一地址在不同链上是不同的钱包，但只能属于同一个用户
+type UserWallet struct {
+	ID        string `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string `json:"userId" gorm:"column:user_id;index;not null"`
+	Account   string `json:"account" gorm:"column:account;uniqueIndex"`    // CAIP-10 标识，例如 eip155:137:0xAbC...
+	Address   string `json:"address" gorm:"column:address;index;not null"` // EVM 为 EIP-55 checksum 地址，Solana 为 base58 公钥
+	ChainID   int64  `json:"chainId" gorm:"column:chain_id;not null"`      // EVM 链 ID，Solana 钱包为 0
+	Namespace string `json:"chainNamespace" gorm:"column:chain_namespace;type:varchar(16);default:'eip155'"`
+	IsPrimary bool   `json:"isPrimary" gorm:"column:is_primary;default:false"` // 与 User.WalletAddress 一致的主钱包
+	// KeyVerified 签名证明持有地址私钥 (EVM 为 ecrecover 恢复出地址，Solana 为 ed25519)，该地址在同一链家族所有链上都由用户控制；
+	// EIP-1271 合约钱包为 false，合约在其他链上可能不存在或由其他人部署
+	KeyVerified bool      `json:"-" gorm:"column:key_verified;default:false"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (UserWallet) TableName() string {
+	return "user_wallets"
+}
+
+// WalletLinkChallenge 绑定新钱包的挑战 (存储于 Redis)
+// 由已登录会话发起，绑定到该会话，需新钱包签名 Message 完成绑定
+type WalletLinkChallenge struct {
+	Nonce     string    `json:"nonce"`
+	UserID    string    `json:"userId"`
+	SessionID string    `json:"sessionId"`
+	DID       string    `json:"did"`
+	Address   string    `json:"address"`        // 待绑定的钱包地址
+	Namespace string    `json:"chainNamespace"` // 钱包所属链家族 (CAIP-2 namespace)
+	ChainID   string    `json:"chainId"`        // CAIP-2 reference，EVM 为十进制链 ID
+	Message   string    `json:"message"`        // personal_sign 待签名原文
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IsExpired 检查是否过期
+func (c *WalletLinkChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/dedata/dedata-backend/internal/domain/entity"
@@ -12,6 +13,9 @@ type UserRepository interface {
 	// FindByAddress 通过地址查找用户
 	FindByAddress(ctx context.Context, address string) (*entity.User, error)
 
+	// FindByAccount 通过主钱包的 CAIP-10 标识查找用户
+	FindByAccount(ctx context.Context, account string) (*entity.User, error)
+
 	// FindByDID 通过 DID 查找用户
 	FindByDID(ctx context.Context, did string) (*entity.User, error)
 
@@ -32,23 +36,401 @@ type UserRepository interface {
 
 	// GetUserRankByRewards 获取用户根据 total_rewards 的排名
 	GetUserRankByRewards(ctx context.Context, userID string) (int, error)
+
+	// Search 按条件搜索用户（分页，管理后台使用）
+	Search(ctx context.Context, filter UserFilter, page, limit int) ([]*entity.User, int64, error)
+
+	// UpdateStatus 更新用户状态
+	UpdateStatus(ctx context.Context, userID string, status entity.UserStatus) error
+
+	// UpdateRole 更新用户角色
+	UpdateRole(ctx context.Context, userID string, role entity.UserRole) error
 }
 
-// AuthRepository 认证仓储接口
-type AuthRepository interface {
-	// CreateChallenge 创建登录挑战
-	CreateChallenge(ctx context.Context, challenge *entity.LoginChallenge) error
+// UserFilter 用户搜索条件，空值表示不过滤
+type UserFilter struct {
+	Query  string            // 模糊匹配钱包地址、DID 或昵称
+	Status entity.UserStatus // 用户状态
+	Role   entity.UserRole   // 用户角色
+}
+
+var (
+	// ErrChallengeNotFound 挑战 (登录、绑定、迁移、同意或 passkey) 不存在、已过期或已被使用
+	ErrChallengeNotFound = errors.New("challenge not found")
+
+	// ErrTokenNotFound refresh token、OAuth 授权码或 access token 不存在、已过期或已被使用
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTooManyChallenges 同一地址或 IP 未使用的登录挑战达到上限
+	ErrTooManyChallenges = errors.New("too many outstanding challenges")
+)
 
-	// FindChallengeByNonce 通过 nonce 查找挑战
-	FindChallengeByNonce(ctx context.Context, nonce string) (*entity.LoginChallenge, error)
+// ChallengeStore 登录挑战存储接口
+type ChallengeStore interface {
+	// SaveChallenge 保存登录挑战，同一地址或 IP 未过期、未使用的挑战达到上限时返回 ErrTooManyChallenges
+	SaveChallenge(ctx context.Context, challenge *entity.LoginChallenge) error
 
-	// MarkChallengeAsUsed 标记挑战为已使用
-	MarkChallengeAsUsed(ctx context.Context, nonce string) error
+	// ConsumeChallenge 原子地取出挑战并使其失效，保证只能使用一次；不存在或已过期时返回 ErrChallengeNotFound
+	ConsumeChallenge(ctx context.Context, nonce string) (*entity.LoginChallenge, error)
 
 	// DeleteExpiredChallenges 删除过期的挑战
 	DeleteExpiredChallenges(ctx context.Context) error
 }
 
+// ChallengeLimits 每个地址 / IP 同时未使用的登录挑战上限，0 表示不限制
+type ChallengeLimits struct {
+	PerAddress int
+	PerIP      int
+}
+
+// LoginAttemptRepository 登录失败计数、锁定状态与限流计数仓储接口
+type LoginAttemptRepository interface {
+	// FindLockout 查找生效中的锁定，未锁定时返回 nil
+	FindLockout(ctx context.Context, scope entity.LockoutScope, key string) (*entity.Lockout, error)
+
+	// RecordFailure 原子地记录一次失败，窗口内失败次数达到阈值时按指数退避锁定并返回锁定状态，否则返回 nil
+	RecordFailure(ctx context.Context, scope entity.LockoutScope, key string, policy LockoutPolicy) (*entity.Lockout, error)
+
+	// ResetFailures 清除窗口内的失败计数 (不影响退避等级)
+	ResetFailures(ctx context.Context, scope entity.LockoutScope, key string) error
+
+	// ListLockouts 列出所有生效中的锁定
+	ListLockouts(ctx context.Context) ([]*entity.Lockout, error)
+
+	// DeleteLockout 解除锁定并清除失败计数与退避等级
+	DeleteLockout(ctx context.Context, scope entity.LockoutScope, key string) error
+}
+
+// LockoutPolicy 登录失败锁定策略
+type LockoutPolicy struct {
+	MaxFailures int           // 窗口内允许的失败次数，达到后锁定
+	Window      time.Duration // 失败计数窗口
+	BaseLockout time.Duration // 首次锁定时长，之后每次翻倍
+	MaxLockout  time.Duration // 锁定时长上限
+	Decay       time.Duration // 退避等级保留时间，期间没有新的锁定则从首次锁定重新计算
+}
+
+// TokenRepository 令牌仓储接口 (refresh token 轮换与 access token 吊销)
+type TokenRepository interface {
+	// SaveRefreshToken 保存 refresh token
+	SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+
+	// FindRefreshToken 通过摘要查找 refresh token，不存在或已过期时返回 ErrTokenNotFound
+	FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// ConsumeRefreshToken 原子地标记 refresh token 已使用，返回 false 表示此前已被使用
+	ConsumeRefreshToken(ctx context.Context, tokenHash string, ttl time.Duration) (bool, error)
+
+	// RevokeSession 吊销整个 token family
+	RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error
+
+	// IsSessionRevoked 检查 token family 是否已吊销
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+
+	// DenylistAccessToken 将 access token 的 jti 加入黑名单直到其过期
+	DenylistAccessToken(ctx context.Context, jti string, ttl time.Duration) error
+
+	// IsAccessTokenDenylisted 检查 access token 是否在黑名单中
+	IsAccessTokenDenylisted(ctx context.Context, jti string) (bool, error)
+}
+
+// SessionRepository 登录会话仓储接口
+type SessionRepository interface {
+	// Create 创建会话
+	Create(ctx context.Context, session *entity.Session) error
+
+	// FindByID 通过 ID 查找会话
+	FindByID(ctx context.Context, id string) (*entity.Session, error)
+
+	// FindActiveByUserID 查询用户所有未吊销且未过期的会话
+	FindActiveByUserID(ctx context.Context, userID string) ([]*entity.Session, error)
+
+	// Touch 更新会话最后活跃时间、IP、UA 及过期时间 (refresh 时调用)
+	Touch(ctx context.Context, id string, ip, userAgent string, expiresAt time.Time) error
+
+	// Revoke 标记会话已吊销
+	Revoke(ctx context.Context, id string) error
+}
+
+// WalletRepository 用户钱包仓储接口
+type WalletRepository interface {
+	// Create 绑定钱包
+	Create(ctx context.Context, wallet *entity.UserWallet) error
+
+	// FindByAddress 通过地址查找钱包 (任意链)
+	FindByAddress(ctx context.Context, address string) (*entity.UserWallet, error)
+
+	// FindByAccount 通过 CAIP-10 标识查找钱包
+	FindByAccount(ctx context.Context, account string) (*entity.UserWallet, error)
+
+	// UpdateAccount 回填或规范化钱包的 CAIP-10 标识
+	UpdateAccount(ctx context.Context, id, account string) error
+
+	// MarkKeyVerified 记录钱包的签名证明持有私钥 (EOA / Solana)
+	MarkKeyVerified(ctx context.Context, id string) error
+
+	// FindByUserID 查询用户绑定的所有钱包
+	FindByUserID(ctx context.Context, userID string) ([]*entity.UserWallet, error)
+
+	// Delete 解绑钱包
+	Delete(ctx context.Context, id string) error
+}
+
+// WalletLinkRepository 钱包绑定挑战仓储接口
+type WalletLinkRepository interface {
+	// SaveLinkChallenge 保存绑定挑战
+	SaveLinkChallenge(ctx context.Context, challenge *entity.WalletLinkChallenge) error
+
+	// ConsumeLinkChallenge 原子地取出并删除绑定挑战，保证只能使用一次；不存在或已过期时返回 ErrChallengeNotFound
+	ConsumeLinkChallenge(ctx context.Context, nonce string) (*entity.WalletLinkChallenge, error)
+}
+
+// MigrationRepository 账户迁移仓储接口
+type MigrationRepository interface {
+	// Create 创建迁移记录
+	Create(ctx context.Context, migration *entity.WalletMigration) error
+
+	// FindByID 通过 ID 查找迁移记录
+	FindByID(ctx context.Context, id string) (*entity.WalletMigration, error)
+
+	// FindByUserID 查询用户的迁移历史，最新的在前
+	FindByUserID(ctx context.Context, userID string) ([]*entity.WalletMigration, error)
+
+	// FindPendingByUserID 查找用户等待批准的迁移
+	FindPendingByUserID(ctx context.Context, userID string) (*entity.WalletMigration, error)
+
+	// FindByStatus 按状态查询迁移记录（分页，管理后台使用），空状态表示不过滤
+	FindByStatus(ctx context.Context, status entity.MigrationStatus, page, limit int) ([]*entity.WalletMigration, int64, error)
+
+	// Close 将等待中的迁移标记为取消或拒绝
+	Close(ctx context.Context, id string, status entity.MigrationStatus, reviewedBy, reason string) error
+
+	// Execute 在同一事务中替换用户主钱包、更新 user_wallets 并标记迁移完成
+	Execute(ctx context.Context, migration *entity.WalletMigration, reviewedBy string) error
+}
+
+// MigrationChallengeRepository 迁移挑战仓储接口
+type MigrationChallengeRepository interface {
+	// SaveMigrationChallenge 保存迁移挑战
+	SaveMigrationChallenge(ctx context.Context, challenge *entity.MigrationChallenge) error
+
+	// ConsumeMigrationChallenge 原子地取出并删除迁移挑战，保证只能使用一次；不存在或已过期时返回 ErrChallengeNotFound
+	ConsumeMigrationChallenge(ctx context.Context, nonce string) (*entity.MigrationChallenge, error)
+}
+
+// RateLimitRepository 固定窗口计数仓储接口，用于各类请求限流
+type RateLimitRepository interface {
+	// Allow 计数加一，窗口内超过 limit 次返回 false
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+
+	// Increment 计数加一并返回窗口内的累计次数
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// AuthorizationRepository 平台授权仓储接口
+type AuthorizationRepository interface {
+	// Grant 在同一事务中吊销用户对该平台的现有授权并创建新授权
+	Grant(ctx context.Context, authorization *entity.Authorization) error
+
+	// FindByID 通过 ID 查找授权
+	FindByID(ctx context.Context, id string) (*entity.Authorization, error)
+
+	// FindByUserID 查询用户的所有授权，最新的在前
+	FindByUserID(ctx context.Context, userID string) ([]*entity.Authorization, error)
+
+	// FindActive 查找用户对平台的 ACTIVE 授权
+	FindActive(ctx context.Context, userID, platform string) (*entity.Authorization, error)
+
+	// Revoke 吊销 ACTIVE 授权
+	Revoke(ctx context.Context, id string) error
+
+	// RevokeByPlatform 吊销平台的全部 ACTIVE 授权 (删除 OAuth 客户端时使用)
+	RevokeByPlatform(ctx context.Context, platform string) error
+
+	// ExpireDue 将已过 ExpiresAt 的 ACTIVE 授权标记为 EXPIRED，返回更新数量
+	ExpireDue(ctx context.Context) (int64, error)
+}
+
+// ConsentChallengeRepository 授权同意挑战仓储接口
+type ConsentChallengeRepository interface {
+	// SaveConsentChallenge 保存同意挑战
+	SaveConsentChallenge(ctx context.Context, challenge *entity.ConsentChallenge) error
+
+	// ConsumeConsentChallenge 原子地取出并删除同意挑战，保证只能使用一次；不存在或已过期时返回 ErrChallengeNotFound
+	ConsumeConsentChallenge(ctx context.Context, nonce string) (*entity.ConsentChallenge, error)
+}
+
+// OAuthClientRepository OAuth 客户端仓储接口
+type OAuthClientRepository interface {
+	// Create 注册客户端
+	Create(ctx context.Context, client *entity.OAuthClient) error
+
+	// FindByID 通过 client_id 查找客户端
+	FindByID(ctx context.Context, id string) (*entity.OAuthClient, error)
+
+	// FindAll 查询所有客户端，最新的在前
+	FindAll(ctx context.Context) ([]*entity.OAuthClient, error)
+
+	// Delete 删除客户端
+	Delete(ctx context.Context, id string) error
+}
+
+// OAuthTokenRepository OAuth 授权码与 access token 仓储接口
+type OAuthTokenRepository interface {
+	// SaveCode 保存授权码
+	SaveCode(ctx context.Context, code *entity.OAuthCode) error
+
+	// ConsumeCode 原子地取出并删除授权码，保证只能兑换一次；不存在或已过期时返回 ErrTokenNotFound
+	ConsumeCode(ctx context.Context, codeHash string) (*entity.OAuthCode, error)
+
+	// SaveAccessToken 保存 access token
+	SaveAccessToken(ctx context.Context, token *entity.OAuthAccessToken) error
+
+	// FindAccessToken 通过摘要查找 access token，不存在或已过期时返回 ErrTokenNotFound
+	FindAccessToken(ctx context.Context, tokenHash string) (*entity.OAuthAccessToken, error)
+}
+
+// APIKeyRepository API key 仓储接口
+type APIKeyRepository interface {
+	// Create 创建 API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// FindByID 通过 ID 查找 API key
+	FindByID(ctx context.Context, id string) (*entity.APIKey, error)
+
+	// FindByPrefix 通过前缀查找 API key
+	FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+
+	// FindAll 查询所有 API key，最新的在前
+	FindAll(ctx context.Context) ([]*entity.APIKey, error)
+
+	// Revoke 吊销 API key
+	Revoke(ctx context.Context, id string) error
+
+	// TouchLastUsed 记录最后使用时间与 IP
+	TouchLastUsed(ctx context.Context, id, ip string, usedAt time.Time) error
+}
+
+// TOTPRepository 管理员 TOTP 绑定与恢复码仓储接口
+type TOTPRepository interface {
+	// Save 创建或覆盖 TOTP 绑定 (重新发起未确认的绑定时覆盖旧密钥)
+	Save(ctx context.Context, enrollment *entity.TOTPEnrollment) error
+
+	// FindByUserID 查找用户的 TOTP 绑定
+	FindByUserID(ctx context.Context, userID string) (*entity.TOTPEnrollment, error)
+
+	// Confirm 确认绑定并记录通过验证的时间步
+	Confirm(ctx context.Context, userID string, step int64, confirmedAt time.Time) error
+
+	// AdvanceStep 仅当 step 大于上次使用的时间步时更新，返回是否更新 (false 表示验证码已被使用)
+	AdvanceStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	// Delete 删除 TOTP 绑定及其恢复码
+	Delete(ctx context.Context, userID string) error
+
+	// ReplaceRecoveryCodes 删除旧恢复码并保存新的恢复码摘要
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+
+	// ConsumeRecoveryCode 原子地将未使用的恢复码标记为已使用，返回是否成功
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+
+	// CountRecoveryCodes 统计剩余可用的恢复码
+	CountRecoveryCodes(ctx context.Context, userID string) (int64, error)
+}
+
+// StepUpRepository 二次验证放行状态仓储接口，按会话记录
+type StepUpRepository interface {
+	// Grant 标记会话已通过二次验证，ttl 后失效
+	Grant(ctx context.Context, sessionID string, ttl time.Duration) error
+
+	// ExpiresAt 返回会话二次验证的失效时间，未验证或已失效时返回 nil
+	ExpiresAt(ctx context.Context, sessionID string) (*time.Time, error)
+
+	// Revoke 清除会话的二次验证状态
+	Revoke(ctx context.Context, sessionID string) error
+}
+
+// PasskeyRepository passkey 仓储接口
+type PasskeyRepository interface {
+	// Create 保存新注册的 passkey
+	Create(ctx context.Context, passkey *entity.Passkey) error
+
+	// FindByID 通过 ID 查找 passkey
+	FindByID(ctx context.Context, id string) (*entity.Passkey, error)
+
+	// FindByCredentialID 通过 WebAuthn 凭证 ID 查找 passkey
+	FindByCredentialID(ctx context.Context, credentialID string) (*entity.Passkey, error)
+
+	// FindByUserID 查询用户的所有 passkey，最新的在前
+	FindByUserID(ctx context.Context, userID string) ([]*entity.Passkey, error)
+
+	// CountByUserID 统计用户的 passkey 数量
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+
+	// RecordUse 登录成功后更新签名计数器、备份状态与最后使用时间
+	RecordUse(ctx context.Context, id string, signCount int64, backedUp bool, usedAt time.Time) error
+
+	// Delete 删除 passkey
+	Delete(ctx context.Context, id string) error
+}
+
+// PasskeyChallengeRepository passkey 注册 / 登录挑战仓储接口
+type PasskeyChallengeRepository interface {
+	// SavePasskeyChallenge 保存挑战，到期自动删除
+	SavePasskeyChallenge(ctx context.Context, challenge *entity.PasskeyChallenge) error
+
+	// ConsumePasskeyChallenge 原子地取出并删除挑战，保证只能使用一次；不存在或已过期时返回 ErrChallengeNotFound
+	ConsumePasskeyChallenge(ctx context.Context, challenge string) (*entity.PasskeyChallenge, error)
+}
+
+// IdempotencyRepository Idempotency-Key 请求记录仓储接口
+type IdempotencyRepository interface {
+	// Reserve 原子地为 key 写入处理中的记录，key 已存在时返回已有记录与 false
+	Reserve(ctx context.Context, key string, record *entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, bool, error)
+
+	// Extend 延长处理中记录的有效期，记录已完成、已过期或请求体摘要不同时返回 false
+	Extend(ctx context.Context, key, fingerprint string, ttl time.Duration) (bool, error)
+
+	// Complete 保存首次响应，覆盖处理中的记录
+	Complete(ctx context.Context, key string, record *entity.IdempotencyRecord, ttl time.Duration) error
+
+	// Release 删除记录，允许使用同一个 key 重试
+	Release(ctx context.Context, key string) error
+}
+
+// LockRepository 分布式互斥锁仓储接口
+type LockRepository interface {
+	// Acquire 尝试加锁，已被占用时返回 false；返回的 token 用于解锁
+	Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+
+	// Release 仅当锁仍由 token 持有时解锁
+	Release(ctx context.Context, key, token string) error
+}
+
+// CredentialRepository Verifiable Credential 仓储接口
+type CredentialRepository interface {
+	// NextStatusIndex 分配新的吊销状态序号
+	NextStatusIndex(ctx context.Context) (int64, error)
+
+	// CurrentStatusIndex 返回最近分配的吊销状态序号，尚未分配时返回 0
+	CurrentStatusIndex(ctx context.Context) (int64, error)
+
+	// Create 保存已签发的 credential
+	Create(ctx context.Context, credential *entity.Credential) error
+
+	// FindByID 通过 ID 查找 credential
+	FindByID(ctx context.Context, id string) (*entity.Credential, error)
+
+	// FindByUserID 查询用户的 credential，最新的在前
+	FindByUserID(ctx context.Context, userID string) ([]*entity.Credential, error)
+
+	// Revoke 吊销 credential
+	Revoke(ctx context.Context, id string) error
+
+	// FindRevokedStatusIndexes 查询 [from, to) 区间内已吊销的状态序号
+	FindRevokedStatusIndexes(ctx context.Context, from, to int64) ([]int64, error)
+}
+
 // ProfileRepository Profile 仓储接口
 type ProfileRepository interface {
 	// FindByUserID 通过用户 ID 查找 Profile
@@ -99,18 +481,40 @@ type CheckInRepository interface {
 	// MarkSuccess 标记为成功
 	MarkSuccess(ctx context.Context, id string, txHash string, amount string) error
 
-	// MarkFailed 标记为失败
-	MarkFailed(ctx context.Context, id string, reason string) error
+	// MarkFailed 标记为发放失败，发放失败的签到日不计入连续签到，同时按 graceDays 重新计算该用户的连续签到
+	MarkFailed(ctx context.Context, id string, reason string, graceDays int) error
 
 	// FindByUserID 查询用户签到记录
 	FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*entity.CheckIn, int64, error)
 
-	// GetDailyStats 获取用户每日统计
+	// GetDailyStats 获取用户每日统计 (按签到日分组)
 	GetDailyStats(ctx context.Context, userID string) ([]map[string]interface{}, error)
 
-	// CheckTodayCheckin 检查用户今天是否已签到
-	CheckTodayCheckin(ctx context.Context, userID string) (bool, error)
+	// CountDayCheckins 统计用户某天已占用名额的签到数 (已支付的签到)
+	CountDayCheckins(ctx context.Context, userID string, day time.Time) (int64, error)
+
+	// ClaimDaySlot 待支付的签到支付成功时占用当天的下一个名额并改为 payment_success，
+	// 同时推进用户的连续签到 (允许中断 graceDays 天) 并记录到签到上
+	// 名额已满或状态已不是 pending_payment 时返回 false
+	ClaimDaySlot(ctx context.Context, checkIn *entity.CheckIn, day time.Time, limit, graceDays int) (bool, error)
+
+	// RecomputeCheckinDays 签到日上次按其他时区计算时，按 timezone 重新计算所有已支付签到的签到日和名额，
+	// 并将连续签到标记为待重新计算 (见 RecomputeStreaks)；时区未变化时不做任何事并返回 false
+	RecomputeCheckinDays(ctx context.Context, timezone string) (bool, error)
+
+	// RecomputeStreaks 连续签到上次按其他宽限天数计算 (或尚未计算) 时，按 graceDays 重新计算所有用户的连续签到
+	// 宽限天数未变化时不做任何事并返回 false
+	RecomputeStreaks(ctx context.Context, graceDays int) (bool, error)
 
 	// CountSuccessCheckinsByUserID 获取用户成功签到的总次数
 	CountSuccessCheckinsByUserID(ctx context.Context, userID string) (int64, error)
 }
+
+// StreakRuleRepository 连续签到奖励规则仓储
+type StreakRuleRepository interface {
+	// List 按 min_days 升序返回所有规则
+	List(ctx context.Context) ([]*entity.StreakRule, error)
+
+	// Replace 在同一事务中用 rules 替换所有规则
+	Replace(ctx context.Context, rules []*entity.StreakRule) error
+}
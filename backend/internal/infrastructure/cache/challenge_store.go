@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginChallengeKeyPrefix = "auth:login:"         // 挑战内容，TTL 与挑战有效期一致
+	loginAddressKeyPrefix   = "auth:login:address:" // 地址下未使用的 nonce (ZSET，score 为过期时间)
+	loginIPKeyPrefix        = "auth:login:ip:"      // IP 下未使用的 nonce
+)
+
+// saveChallengeScript 清理已过期的 nonce 后检查上限，未超限时写入挑战并登记到地址 / IP 集合
+// KEYS: 挑战, 地址集合, [IP 集合]
+// ARGV: 挑战内容, TTL (ms), 当前时间 (ms), 过期时间 (ms), nonce, 地址上限, IP 上限
+// 返回 0 成功，1 超出上限
+var saveChallengeScript = redis.NewScript(`
+local now = tonumber(ARGV[3])
+for i = 2, #KEYS do
+  redis.call('ZREMRANGEBYSCORE', KEYS[i], '-inf', now)
+  local limit = tonumber(ARGV[4 + i])
+  if limit > 0 and redis.call('ZCARD', KEYS[i]) >= limit then
+    return 1
+  end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+for i = 2, #KEYS do
+  redis.call('ZADD', KEYS[i], ARGV[4], ARGV[5])
+  redis.call('PEXPIRE', KEYS[i], ARGV[2])
+end
+return 0
+`)
+
+// RedisChallengeStore 登录挑战存储的 Redis 实现，过期挑战由 TTL 自动清理
+type RedisChallengeStore struct {
+	rdb    *redis.Client
+	limits repository.ChallengeLimits
+}
+
+func NewRedisChallengeStore(rdb *redis.Client, limits repository.ChallengeLimits) *RedisChallengeStore {
+	return &RedisChallengeStore{rdb: rdb, limits: limits}
+}
+
+// SaveChallenge 使用 Lua 脚本原子地检查未使用挑战数并保存挑战
+func (r *RedisChallengeStore) SaveChallenge(ctx context.Context, challenge *entity.LoginChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(challenge.ExpiresAt)
+	keys := []string{loginChallengeKeyPrefix + challenge.Nonce, loginAddressKeyPrefix + challenge.Address}
+	if challenge.IPAddress != "" {
+		keys = append(keys, loginIPKeyPrefix+challenge.IPAddress)
+	}
+
+	result, err := saveChallengeScript.Run(ctx, r.rdb, keys,
+		data,
+		ttl.Milliseconds(),
+		time.Now().UnixMilli(),
+		challenge.ExpiresAt.UnixMilli(),
+		challenge.Nonce,
+		r.limits.PerAddress,
+		r.limits.PerIP,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if result != 0 {
+		return repository.ErrTooManyChallenges
+	}
+	return nil
+}
+
+// ConsumeChallenge 使用 GETDEL 原子地取出并删除挑战，并发请求中只有一个能取到
+func (r *RedisChallengeStore) ConsumeChallenge(ctx context.Context, nonce string) (*entity.LoginChallenge, error) {
+	data, err := r.rdb.GetDel(ctx, loginChallengeKeyPrefix+nonce).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+
+	var challenge entity.LoginChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+
+	// 已使用的 nonce 不再占用地址 / IP 的配额；失败时等到过期时间后由下次 SaveChallenge 清理
+	pipe := r.rdb.Pipeline()
+	pipe.ZRem(ctx, loginAddressKeyPrefix+challenge.Address, nonce)
+	if challenge.IPAddress != "" {
+		pipe.ZRem(ctx, loginIPKeyPrefix+challenge.IPAddress, nonce)
+	}
+	_, _ = pipe.Exec(ctx)
+
+	return &challenge, nil
+}
+
+// DeleteExpiredChallenges 过期挑战由 Redis TTL 清理，无需处理
+func (r *RedisChallengeStore) DeleteExpiredChallenges(ctx context.Context) error {
+	return nil
+}
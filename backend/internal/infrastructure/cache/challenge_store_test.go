@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/redis/go-redis/v9"
+)
+
+func newChallengeStore(t *testing.T, limits repository.ChallengeLimits) *RedisChallengeStore {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewRedisChallengeStore(rdb, limits)
+}
+
+func newLoginChallenge(t *testing.T, address, ip string, ttl time.Duration) *entity.LoginChallenge {
+	t.Helper()
+	nonce, err := crypto.GenerateNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	return &entity.LoginChallenge{
+		Address:   address,
+		Nonce:     nonce,
+		ChainID:   "1",
+		IPAddress: ip,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func TestConsumeChallengeConcurrent(t *testing.T) {
+	store := newChallengeStore(t, repository.ChallengeLimits{})
+	ctx := context.Background()
+
+	challenge := newLoginChallenge(t, "0xabc", "203.0.113.7", time.Minute)
+	if err := store.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("SaveChallenge: %v", err)
+	}
+
+	const workers = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := store.ConsumeChallenge(ctx, challenge.Nonce)
+			if err != nil && !errors.Is(err, repository.ErrChallengeNotFound) {
+				t.Errorf("ConsumeChallenge: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("%d concurrent ConsumeChallenge calls succeeded, want 1", succeeded)
+	}
+}
+
+func TestSaveChallengeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  repository.ChallengeLimits
+		address func(i int) string
+		ip      func(i int) string
+	}{
+		{
+			name:    "per address",
+			limits:  repository.ChallengeLimits{PerAddress: 3, PerIP: 100},
+			address: func(int) string { return "0xabc" },
+			ip:      func(i int) string { return fmt.Sprintf("203.0.113.%d", i+1) },
+		},
+		{
+			name:    "per IP",
+			limits:  repository.ChallengeLimits{PerAddress: 100, PerIP: 3},
+			address: func(i int) string { return fmt.Sprintf("0xabc%d", i) },
+			ip:      func(int) string { return "203.0.113.7" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newChallengeStore(t, tt.limits)
+			ctx := context.Background()
+
+			var first *entity.LoginChallenge
+			for i := 0; i < 3; i++ {
+				challenge := newLoginChallenge(t, tt.address(i), tt.ip(i), time.Minute)
+				if err := store.SaveChallenge(ctx, challenge); err != nil {
+					t.Fatalf("SaveChallenge #%d: %v", i+1, err)
+				}
+				if first == nil {
+					first = challenge
+				}
+			}
+			if err := store.SaveChallenge(ctx, newLoginChallenge(t, tt.address(3), tt.ip(3), time.Minute)); !errors.Is(err, repository.ErrTooManyChallenges) {
+				t.Fatalf("SaveChallenge over the limit = %v, want ErrTooManyChallenges", err)
+			}
+
+			// 已使用的 nonce 不再占用配额
+			if _, err := store.ConsumeChallenge(ctx, first.Nonce); err != nil {
+				t.Fatalf("ConsumeChallenge: %v", err)
+			}
+			if err := store.SaveChallenge(ctx, newLoginChallenge(t, tt.address(3), tt.ip(3), time.Minute)); err != nil {
+				t.Errorf("SaveChallenge after consuming a nonce: %v", err)
+			}
+		})
+	}
+}
+
+func TestSaveChallengeIgnoresExpiredNonces(t *testing.T) {
+	store := newChallengeStore(t, repository.ChallengeLimits{PerAddress: 1, PerIP: 1})
+	ctx := context.Background()
+
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, "0xabc", "203.0.113.7", 100*time.Millisecond)); err != nil {
+		t.Fatalf("SaveChallenge: %v", err)
+	}
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, "0xabc", "203.0.113.7", time.Minute)); !errors.Is(err, repository.ErrTooManyChallenges) {
+		t.Fatalf("SaveChallenge before expiry = %v, want ErrTooManyChallenges", err)
+	}
+
+	// 集合按过期时间 (score) 清理，与 Redis 的 key TTL 无关
+	time.Sleep(150 * time.Millisecond)
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, "0xabc", "203.0.113.7", time.Minute)); err != nil {
+		t.Errorf("SaveChallenge after the outstanding nonce expired: %v", err)
+	}
+}
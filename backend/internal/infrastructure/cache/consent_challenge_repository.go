@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const consentChallengeKeyPrefix = "auth:consent:"
+
+type RedisConsentChallengeRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisConsentChallengeRepository(rdb *redis.Client) *RedisConsentChallengeRepository {
+	return &RedisConsentChallengeRepository{rdb: rdb}
+}
+
+// SaveConsentChallenge 保存同意挑战，TTL 与挑战有效期一致
+func (r *RedisConsentChallengeRepository) SaveConsentChallenge(ctx context.Context, challenge *entity.ConsentChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, consentChallengeKeyPrefix+challenge.Nonce, data, time.Until(challenge.ExpiresAt)).Err()
+}
+
+// ConsumeConsentChallenge 使用 GETDEL 原子地取出并删除挑战，不存在时返回 repository.ErrChallengeNotFound
+func (r *RedisConsentChallengeRepository) ConsumeConsentChallenge(ctx context.Context, nonce string) (*entity.ConsentChallenge, error) {
+	data, err := r.rdb.GetDel(ctx, consentChallengeKeyPrefix+nonce).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+
+	var challenge entity.ConsentChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
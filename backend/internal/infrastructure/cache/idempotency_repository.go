@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// extendIdempotencyScript 仅当记录仍在处理中 (没有 statusCode) 且请求体摘要相同时延长有效期
+// KEYS: 记录
+// ARGV: fingerprint, ttl (毫秒)
+var extendIdempotencyScript = redis.NewScript(`
+local data = redis.call('GET', KEYS[1])
+if not data then
+  return 0
+end
+local record = cjson.decode(data)
+if record.fingerprint ~= ARGV[1] or record.statusCode then
+  return 0
+end
+return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+`)
+
+type RedisIdempotencyRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisIdempotencyRepository(rdb *redis.Client) *RedisIdempotencyRepository {
+	return &RedisIdempotencyRepository{rdb: rdb}
+}
+
+// Reserve 使用 SET NX 写入处理中的记录，key 已存在时读取已有记录
+// 已有记录恰好在两次调用之间过期时返回 nil 与 false，由调用方按处理中处理
+func (r *RedisIdempotencyRepository) Reserve(ctx context.Context, key string, record *entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok, err := r.rdb.SetNX(ctx, idempotencyKeyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		return record, true, nil
+	}
+
+	existing, err := r.rdb.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result entity.IdempotencyRecord
+	if err := json.Unmarshal(existing, &result); err != nil {
+		return nil, false, err
+	}
+	return &result, false, nil
+}
+
+// Extend 使用 Lua 脚本原子地检查记录并延长有效期
+func (r *RedisIdempotencyRepository) Extend(ctx context.Context, key, fingerprint string, ttl time.Duration) (bool, error) {
+	n, err := extendIdempotencyScript.Run(ctx, r.rdb, []string{idempotencyKeyPrefix + key}, fingerprint, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Complete 保存首次响应，TTL 为重放有效期
+func (r *RedisIdempotencyRepository) Complete(ctx context.Context, key string, record *entity.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, idempotencyKeyPrefix+key, data, ttl).Err()
+}
+
+// Release 删除记录
+func (r *RedisIdempotencyRepository) Release(ctx context.Context, key string) error {
+	return r.rdb.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/redis/go-redis/v9"
+)
+
+const lockKeyPrefix = "lock:"
+
+// releaseLockScript 仅当锁的值仍为加锁时的 token 才删除，避免误删超时后被他人持有的锁
+// KEYS: 锁
+// ARGV: token
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type RedisLockRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisLockRepository(rdb *redis.Client) *RedisLockRepository {
+	return &RedisLockRepository{rdb: rdb}
+}
+
+// Acquire 使用 SET NX 加锁，值为随机 token，TTL 到期后自动释放
+func (r *RedisLockRepository) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token, err := crypto.RandomHex(16)
+	if err != nil {
+		return "", false, err
+	}
+
+	ok, err := r.rdb.SetNX(ctx, lockKeyPrefix+key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// Release 使用 Lua 脚本原子地比较 token 并解锁
+func (r *RedisLockRepository) Release(ctx context.Context, key, token string) error {
+	return releaseLockScript.Run(ctx, r.rdb, []string{lockKeyPrefix + key}, token).Err()
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginFailureKeyPrefix = "auth:guard:fail:"  // 窗口内的失败次数
+	loginLevelKeyPrefix   = "auth:guard:level:" // 退避等级，Decay 内没有新的锁定则过期
+	loginLockKeyPrefix    = "auth:guard:lock:"  // 生效中的锁定 (HASH: level, until)，TTL 为锁定时长
+)
+
+// recordFailureScript 累加失败次数，达到阈值时清零计数、提升退避等级并写入锁定
+// KEYS: 失败计数, 退避等级, 锁定
+// ARGV: 阈值, 窗口 (ms), 首次锁定时长 (ms), 锁定时长上限 (ms), 退避等级保留时间 (ms), 当前时间 (ms)
+// 返回 {level, until}，未锁定时 level 为 0
+var recordFailureScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if failures < tonumber(ARGV[1]) then
+  return {0, 0}
+end
+redis.call('DEL', KEYS[1])
+local level = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+local duration = math.min(tonumber(ARGV[3]) * 2 ^ (level - 1), tonumber(ARGV[4]))
+duration = math.floor(duration)
+local untilMs = tonumber(ARGV[6]) + duration
+redis.call('HSET', KEYS[3], 'level', level, 'until', untilMs)
+redis.call('PEXPIRE', KEYS[3], duration)
+return {level, untilMs}
+`)
+
+type RedisLoginAttemptRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisLoginAttemptRepository(rdb *redis.Client) *RedisLoginAttemptRepository {
+	return &RedisLoginAttemptRepository{rdb: rdb}
+}
+
+// FindLockout 查找生效中的锁定，未锁定时返回 nil
+func (r *RedisLoginAttemptRepository) FindLockout(ctx context.Context, scope entity.LockoutScope, key string) (*entity.Lockout, error) {
+	fields, err := r.rdb.HGetAll(ctx, lockKey(loginLockKeyPrefix, scope, key)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseLockout(scope, key, fields)
+}
+
+// RecordFailure 使用 Lua 脚本原子地记录失败并在达到阈值时锁定
+func (r *RedisLoginAttemptRepository) RecordFailure(ctx context.Context, scope entity.LockoutScope, key string, policy repository.LockoutPolicy) (*entity.Lockout, error) {
+	keys := []string{
+		lockKey(loginFailureKeyPrefix, scope, key),
+		lockKey(loginLevelKeyPrefix, scope, key),
+		lockKey(loginLockKeyPrefix, scope, key),
+	}
+	result, err := recordFailureScript.Run(ctx, r.rdb, keys,
+		policy.MaxFailures,
+		policy.Window.Milliseconds(),
+		policy.BaseLockout.Milliseconds(),
+		policy.MaxLockout.Milliseconds(),
+		policy.Decay.Milliseconds(),
+		time.Now().UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 2 || result[0] == 0 {
+		return nil, nil
+	}
+
+	return &entity.Lockout{
+		Scope:       scope,
+		Key:         key,
+		Level:       int(result[0]),
+		LockedUntil: time.UnixMilli(result[1]),
+	}, nil
+}
+
+// ResetFailures 清除窗口内的失败计数
+func (r *RedisLoginAttemptRepository) ResetFailures(ctx context.Context, scope entity.LockoutScope, key string) error {
+	return r.rdb.Del(ctx, lockKey(loginFailureKeyPrefix, scope, key)).Err()
+}
+
+// ListLockouts 通过 SCAN 列出所有生效中的锁定
+func (r *RedisLoginAttemptRepository) ListLockouts(ctx context.Context) ([]*entity.Lockout, error) {
+	var lockouts []*entity.Lockout
+	iter := r.rdb.Scan(ctx, 0, loginLockKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		scope, key, ok := strings.Cut(strings.TrimPrefix(iter.Val(), loginLockKeyPrefix), ":")
+		if !ok {
+			continue
+		}
+
+		fields, err := r.rdb.HGetAll(ctx, iter.Val()).Result()
+		if err != nil {
+			return nil, err
+		}
+		lockout, err := parseLockout(entity.LockoutScope(scope), key, fields)
+		if err != nil {
+			return nil, err
+		}
+		if lockout != nil {
+			lockouts = append(lockouts, lockout)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return lockouts, nil
+}
+
+// DeleteLockout 解除锁定并清除失败计数与退避等级
+func (r *RedisLoginAttemptRepository) DeleteLockout(ctx context.Context, scope entity.LockoutScope, key string) error {
+	return r.rdb.Del(ctx,
+		lockKey(loginFailureKeyPrefix, scope, key),
+		lockKey(loginLevelKeyPrefix, scope, key),
+		lockKey(loginLockKeyPrefix, scope, key),
+	).Err()
+}
+
+func lockKey(prefix string, scope entity.LockoutScope, key string) string {
+	return prefix + string(scope) + ":" + key
+}
+
+// parseLockout 解析锁定 HASH，不存在或已到期时返回 nil
+func parseLockout(scope entity.LockoutScope, key string, fields map[string]string) (*entity.Lockout, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	level, err := strconv.Atoi(fields["level"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout level: %w", err)
+	}
+	until, err := strconv.ParseInt(fields["until"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lockout expiry: %w", err)
+	}
+
+	lockout := &entity.Lockout{
+		Scope:       scope,
+		Key:         key,
+		Level:       level,
+		LockedUntil: time.UnixMilli(until),
+	}
+	if !lockout.IsActive() {
+		return nil, nil
+	}
+	return lockout, nil
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+var testLockoutPolicy = repository.LockoutPolicy{
+	MaxFailures: 3,
+	Window:      15 * time.Minute,
+	BaseLockout: time.Minute,
+	MaxLockout:  5 * time.Minute,
+	Decay:       10 * time.Minute,
+}
+
+func newLoginAttemptRepo(t *testing.T) (*RedisLoginAttemptRepository, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewRedisLoginAttemptRepository(rdb), mr
+}
+
+// fail 记录 n 次失败，返回最后一次的锁定状态
+func fail(t *testing.T, repo *RedisLoginAttemptRepository, key string, n int) *entity.Lockout {
+	t.Helper()
+	var lockout *entity.Lockout
+	for i := 0; i < n; i++ {
+		var err error
+		lockout, err = repo.RecordFailure(context.Background(), entity.LockoutScopeIP, key, testLockoutPolicy)
+		if err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+	return lockout
+}
+
+func TestRecordFailureLocksAtThreshold(t *testing.T) {
+	repo, _ := newLoginAttemptRepo(t)
+	ctx := context.Background()
+
+	if lockout := fail(t, repo, "203.0.113.7", testLockoutPolicy.MaxFailures-1); lockout != nil {
+		t.Fatalf("locked out after %d failures, want no lockout", testLockoutPolicy.MaxFailures-1)
+	}
+	if lockout, _ := repo.FindLockout(ctx, entity.LockoutScopeIP, "203.0.113.7"); lockout != nil {
+		t.Fatalf("FindLockout = %+v before threshold", lockout)
+	}
+
+	lockout := fail(t, repo, "203.0.113.7", 1)
+	if lockout == nil || lockout.Level != 1 {
+		t.Fatalf("lockout at threshold = %+v, want level 1", lockout)
+	}
+	if d := time.Until(lockout.LockedUntil); d <= 0 || d > testLockoutPolicy.BaseLockout {
+		t.Errorf("first lockout lasts %v, want up to %v", d, testLockoutPolicy.BaseLockout)
+	}
+
+	found, err := repo.FindLockout(ctx, entity.LockoutScopeIP, "203.0.113.7")
+	if err != nil || found == nil || found.Level != 1 {
+		t.Fatalf("FindLockout = %+v, %v, want level 1", found, err)
+	}
+	// 其他 key 不受影响
+	if other, _ := repo.FindLockout(ctx, entity.LockoutScopeIP, "198.51.100.1"); other != nil {
+		t.Errorf("unrelated key locked out: %+v", other)
+	}
+}
+
+func TestRecordFailureBacksOffExponentially(t *testing.T) {
+	repo, mr := newLoginAttemptRepo(t)
+	const key = "203.0.113.7"
+
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute} // 上限 MaxLockout
+	for i, duration := range want {
+		lockout := fail(t, repo, key, testLockoutPolicy.MaxFailures)
+		if lockout == nil || lockout.Level != i+1 {
+			t.Fatalf("lockout %d = %+v, want level %d", i+1, lockout, i+1)
+		}
+		if ttl := mr.TTL(loginLockKeyPrefix + "ip:" + key); ttl != duration {
+			t.Errorf("lockout %d lasts %v, want %v", i+1, ttl, duration)
+		}
+		mr.FastForward(duration)
+	}
+
+	// Decay 内没有新的锁定时退避等级过期，下次锁定从首次时长重新开始
+	mr.FastForward(testLockoutPolicy.Decay)
+	if lockout := fail(t, repo, key, testLockoutPolicy.MaxFailures); lockout == nil || lockout.Level != 1 {
+		t.Fatalf("lockout after decay = %+v, want level 1", lockout)
+	}
+}
+
+func TestLockoutExpires(t *testing.T) {
+	repo, mr := newLoginAttemptRepo(t)
+	ctx := context.Background()
+
+	fail(t, repo, "203.0.113.7", testLockoutPolicy.MaxFailures)
+	mr.FastForward(testLockoutPolicy.BaseLockout)
+
+	lockout, err := repo.FindLockout(ctx, entity.LockoutScopeIP, "203.0.113.7")
+	if err != nil || lockout != nil {
+		t.Fatalf("FindLockout after expiry = %+v, %v, want nil", lockout, err)
+	}
+}
+
+func TestResetFailuresKeepsLockout(t *testing.T) {
+	repo, _ := newLoginAttemptRepo(t)
+	ctx := context.Background()
+	const key = "203.0.113.7"
+
+	fail(t, repo, key, testLockoutPolicy.MaxFailures-1)
+	if err := repo.ResetFailures(ctx, entity.LockoutScopeIP, key); err != nil {
+		t.Fatalf("ResetFailures: %v", err)
+	}
+	// 计数已清零，再失败 MaxFailures-1 次仍未锁定
+	if lockout := fail(t, repo, key, testLockoutPolicy.MaxFailures-1); lockout != nil {
+		t.Fatalf("locked out after reset: %+v", lockout)
+	}
+}
+
+func TestDeleteLockoutUnlocksAndClearsLevel(t *testing.T) {
+	repo, _ := newLoginAttemptRepo(t)
+	ctx := context.Background()
+	const key = "203.0.113.7"
+
+	fail(t, repo, key, 2*testLockoutPolicy.MaxFailures)
+	lockouts, err := repo.ListLockouts(ctx)
+	if err != nil || len(lockouts) != 1 || lockouts[0].Key != key || lockouts[0].Level != 2 {
+		t.Fatalf("ListLockouts = %+v, %v, want one level 2 lockout", lockouts, err)
+	}
+
+	if err := repo.DeleteLockout(ctx, entity.LockoutScopeIP, key); err != nil {
+		t.Fatalf("DeleteLockout: %v", err)
+	}
+	if lockout, _ := repo.FindLockout(ctx, entity.LockoutScopeIP, key); lockout != nil {
+		t.Fatalf("still locked out after DeleteLockout: %+v", lockout)
+	}
+	if lockouts, _ := repo.ListLockouts(ctx); len(lockouts) != 0 {
+		t.Fatalf("ListLockouts after unlock = %+v", lockouts)
+	}
+
+	// 退避等级已清除，下次锁定从 1 开始
+	if lockout := fail(t, repo, key, testLockoutPolicy.MaxFailures); lockout == nil || lockout.Level != 1 {
+		t.Fatalf("lockout after unlock = %+v, want level 1", lockout)
+	}
+}
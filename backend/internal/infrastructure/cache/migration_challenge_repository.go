@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const migrationChallengeKeyPrefix = "auth:migration:"
+
+type RedisMigrationChallengeRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisMigrationChallengeRepository(rdb *redis.Client) *RedisMigrationChallengeRepository {
+	return &RedisMigrationChallengeRepository{rdb: rdb}
+}
+
+// SaveMigrationChallenge 保存迁移挑战，TTL 与挑战有效期一致
+func (r *RedisMigrationChallengeRepository) SaveMigrationChallenge(ctx context.Context, challenge *entity.MigrationChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, migrationChallengeKeyPrefix+challenge.Nonce, data, time.Until(challenge.ExpiresAt)).Err()
+}
+
+// ConsumeMigrationChallenge 使用 GETDEL 原子地取出并删除挑战，不存在时返回 repository.ErrChallengeNotFound
+func (r *RedisMigrationChallengeRepository) ConsumeMigrationChallenge(ctx context.Context, nonce string) (*entity.MigrationChallenge, error) {
+	data, err := r.rdb.GetDel(ctx, migrationChallengeKeyPrefix+nonce).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+
+	var challenge entity.MigrationChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key 前缀
+const (
+	oauthCodeKeyPrefix  = "oauth:code:"
+	oauthTokenKeyPrefix = "oauth:token:"
+)
+
+type RedisOAuthTokenRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisOAuthTokenRepository(rdb *redis.Client) *RedisOAuthTokenRepository {
+	return &RedisOAuthTokenRepository{rdb: rdb}
+}
+
+// SaveCode 保存授权码，TTL 与授权码有效期一致
+func (r *RedisOAuthTokenRepository) SaveCode(ctx context.Context, code *entity.OAuthCode) error {
+	data, err := json.Marshal(code)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, oauthCodeKeyPrefix+code.CodeHash, data, time.Until(code.ExpiresAt)).Err()
+}
+
+// ConsumeCode 使用 GETDEL 原子地取出并删除授权码，不存在时返回 repository.ErrTokenNotFound
+func (r *RedisOAuthTokenRepository) ConsumeCode(ctx context.Context, codeHash string) (*entity.OAuthCode, error) {
+	data, err := r.rdb.GetDel(ctx, oauthCodeKeyPrefix+codeHash).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	var code entity.OAuthCode
+	if err := json.Unmarshal(data, &code); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// SaveAccessToken 保存 access token，TTL 与令牌有效期一致
+func (r *RedisOAuthTokenRepository) SaveAccessToken(ctx context.Context, token *entity.OAuthAccessToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, oauthTokenKeyPrefix+token.TokenHash, data, time.Until(token.ExpiresAt)).Err()
+}
+
+// FindAccessToken 通过摘要查找 access token，不存在时返回 repository.ErrTokenNotFound
+func (r *RedisOAuthTokenRepository) FindAccessToken(ctx context.Context, tokenHash string) (*entity.OAuthAccessToken, error) {
+	data, err := r.rdb.Get(ctx, oauthTokenKeyPrefix+tokenHash).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	var token entity.OAuthAccessToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const passkeyChallengeKeyPrefix = "auth:passkey:"
+
+type RedisPasskeyChallengeRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisPasskeyChallengeRepository(rdb *redis.Client) *RedisPasskeyChallengeRepository {
+	return &RedisPasskeyChallengeRepository{rdb: rdb}
+}
+
+// SavePasskeyChallenge 保存 passkey 挑战，TTL 与挑战有效期一致
+func (r *RedisPasskeyChallengeRepository) SavePasskeyChallenge(ctx context.Context, challenge *entity.PasskeyChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, passkeyChallengeKeyPrefix+challenge.Challenge, data, time.Until(challenge.ExpiresAt)).Err()
+}
+
+// ConsumePasskeyChallenge 使用 GETDEL 原子地取出并删除挑战，不存在时返回 repository.ErrChallengeNotFound
+func (r *RedisPasskeyChallengeRepository) ConsumePasskeyChallenge(ctx context.Context, challenge string) (*entity.PasskeyChallenge, error) {
+	data, err := r.rdb.GetDel(ctx, passkeyChallengeKeyPrefix+challenge).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+
+	var result entity.PasskeyChallenge
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
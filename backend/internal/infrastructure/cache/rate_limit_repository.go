@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// incrementScript 固定窗口计数，窗口内首次请求时设置过期时间
+// KEYS: 计数
+// ARGV: 窗口 (ms)
+var incrementScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+type RedisRateLimitRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisRateLimitRepository(rdb *redis.Client) *RedisRateLimitRepository {
+	return &RedisRateLimitRepository{rdb: rdb}
+}
+
+// Allow 固定窗口限流，窗口内超过 limit 次返回 false
+func (r *RedisRateLimitRepository) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	count, err := r.Increment(ctx, key, window)
+	if err != nil {
+		return false, err
+	}
+	return count <= int64(limit), nil
+}
+
+// Increment 使用 Lua 脚本原子地累加计数，返回窗口内的累计次数
+func (r *RedisRateLimitRepository) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, r.rdb, []string{rateLimitKeyPrefix + key}, window.Milliseconds()).Int64()
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const stepUpKeyPrefix = "auth:stepup:"
+
+type RedisStepUpRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisStepUpRepository(rdb *redis.Client) *RedisStepUpRepository {
+	return &RedisStepUpRepository{rdb: rdb}
+}
+
+// Grant 标记会话已通过二次验证，值为失效时间 (unix 秒)
+func (r *RedisStepUpRepository) Grant(ctx context.Context, sessionID string, ttl time.Duration) error {
+	return r.rdb.Set(ctx, stepUpKeyPrefix+sessionID, time.Now().Add(ttl).Unix(), ttl).Err()
+}
+
+// ExpiresAt 返回会话二次验证的失效时间，未验证或已失效时返回 nil
+func (r *RedisStepUpRepository) ExpiresAt(ctx context.Context, sessionID string) (*time.Time, error) {
+	unix, err := r.rdb.Get(ctx, stepUpKeyPrefix+sessionID).Int64()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Unix(unix, 0)
+	return &expiresAt, nil
+}
+
+// Revoke 清除会话的二次验证状态
+func (r *RedisStepUpRepository) Revoke(ctx context.Context, sessionID string) error {
+	return r.rdb.Del(ctx, stepUpKeyPrefix+sessionID).Err()
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key 前缀
+const (
+	refreshTokenKeyPrefix   = "auth:refresh:"
+	refreshUsedKeyPrefix    = "auth:refresh:used:"
+	revokedSessionKeyPrefix = "auth:session:revoked:"
+	denylistKeyPrefix       = "auth:denylist:"
+)
+
+type RedisTokenRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisTokenRepository(rdb *redis.Client) *RedisTokenRepository {
+	return &RedisTokenRepository{rdb: rdb}
+}
+
+// SaveRefreshToken 保存 refresh token，TTL 与令牌有效期一致
+func (r *RedisTokenRepository) SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, refreshTokenKeyPrefix+token.TokenHash, data, time.Until(token.ExpiresAt)).Err()
+}
+
+// FindRefreshToken 通过摘要查找 refresh token，不存在时返回 repository.ErrTokenNotFound
+func (r *RedisTokenRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	data, err := r.rdb.Get(ctx, refreshTokenKeyPrefix+tokenHash).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	var token entity.RefreshToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ConsumeRefreshToken 使用 SETNX 原子地标记已使用，并发请求中只有一个能成功
+func (r *RedisTokenRepository) ConsumeRefreshToken(ctx context.Context, tokenHash string, ttl time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, refreshUsedKeyPrefix+tokenHash, 1, ttl).Result()
+}
+
+// RevokeSession 吊销整个 token family
+func (r *RedisTokenRepository) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	return r.rdb.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, ttl).Err()
+}
+
+// IsSessionRevoked 检查 token family 是否已吊销
+func (r *RedisTokenRepository) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, revokedSessionKeyPrefix+sessionID).Result()
+	return n > 0, err
+}
+
+// DenylistAccessToken 将 access token 的 jti 加入黑名单
+func (r *RedisTokenRepository) DenylistAccessToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil // 已过期的 token 无需加入黑名单
+	}
+	return r.rdb.Set(ctx, denylistKeyPrefix+jti, 1, ttl).Err()
+}
+
+// IsAccessTokenDenylisted 检查 access token 是否在黑名单中
+func (r *RedisTokenRepository) IsAccessTokenDenylisted(ctx context.Context, jti string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, denylistKeyPrefix+jti).Result()
+	return n > 0, err
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const walletLinkKeyPrefix = "auth:wallet-link:"
+
+type RedisWalletLinkRepository struct {
+	rdb *redis.Client
+}
+
+func NewRedisWalletLinkRepository(rdb *redis.Client) *RedisWalletLinkRepository {
+	return &RedisWalletLinkRepository{rdb: rdb}
+}
+
+// SaveLinkChallenge 保存绑定挑战，TTL 与挑战有效期一致
+func (r *RedisWalletLinkRepository) SaveLinkChallenge(ctx context.Context, challenge *entity.WalletLinkChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return r.rdb.Set(ctx, walletLinkKeyPrefix+challenge.Nonce, data, time.Until(challenge.ExpiresAt)).Err()
+}
+
+// ConsumeLinkChallenge 使用 GETDEL 原子地取出并删除挑战，不存在时返回 repository.ErrChallengeNotFound
+func (r *RedisWalletLinkRepository) ConsumeLinkChallenge(ctx context.Context, nonce string) (*entity.WalletLinkChallenge, error) {
+	data, err := r.rdb.GetDel(ctx, walletLinkKeyPrefix+nonce).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, repository.ErrChallengeNotFound
+		}
+		return nil, err
+	}
+
+	var challenge entity.WalletLinkChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
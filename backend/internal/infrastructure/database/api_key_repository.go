@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+// Create 创建 API key
+func (r *GormAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// FindByID 通过 ID 查找 API key
+func (r *GormAPIKeyRepository) FindByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByPrefix 通过前缀查找 API key
+func (r *GormAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindAll 查询所有 API key，最新的在前
+func (r *GormAPIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke 吊销 API key
+func (r *GormAPIKeyRepository) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchLastUsed 记录最后使用时间与 IP
+func (r *GormAPIKeyRepository) TouchLastUsed(ctx context.Context, id, ip string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": usedAt,
+			"last_used_ip": ip,
+		}).Error
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormAuthorizationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuthorizationRepository(db *gorm.DB) *GormAuthorizationRepository {
+	return &GormAuthorizationRepository{db: db}
+}
+
+// Grant 在同一事务中吊销用户对该平台的现有授权并创建新授权
+func (r *GormAuthorizationRepository) Grant(ctx context.Context, authorization *entity.Authorization) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Authorization{}).
+			Where("user_id = ? AND platform = ? AND status = ?", authorization.UserID, authorization.Platform, entity.AuthActive).
+			Updates(map[string]interface{}{
+				"status":     entity.AuthRevoked,
+				"revoked_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(authorization).Error
+	})
+}
+
+// FindByID 通过 ID 查找授权
+func (r *GormAuthorizationRepository) FindByID(ctx context.Context, id string) (*entity.Authorization, error) {
+	var authorization entity.Authorization
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&authorization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// FindByUserID 查询用户的所有授权，最新的在前
+func (r *GormAuthorizationRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Authorization, error) {
+	var authorizations []*entity.Authorization
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&authorizations).Error
+	return authorizations, err
+}
+
+// FindActive 查找用户对平台的 ACTIVE 授权
+func (r *GormAuthorizationRepository) FindActive(ctx context.Context, userID, platform string) (*entity.Authorization, error) {
+	var authorization entity.Authorization
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND platform = ? AND status = ?", userID, platform, entity.AuthActive).
+		First(&authorization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// Revoke 吊销 ACTIVE 授权
+func (r *GormAuthorizationRepository) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Authorization{}).
+		Where("id = ? AND status = ?", id, entity.AuthActive).
+		Updates(map[string]interface{}{
+			"status":     entity.AuthRevoked,
+			"revoked_at": time.Now(),
+		}).Error
+}
+
+// RevokeByPlatform 吊销平台的全部 ACTIVE 授权
+func (r *GormAuthorizationRepository) RevokeByPlatform(ctx context.Context, platform string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Authorization{}).
+		Where("platform = ? AND status = ?", platform, entity.AuthActive).
+		Updates(map[string]interface{}{
+			"status":     entity.AuthRevoked,
+			"revoked_at": time.Now(),
+		}).Error
+}
+
+// ExpireDue 将已过 ExpiresAt 的 ACTIVE 授权标记为 EXPIRED
+func (r *GormAuthorizationRepository) ExpireDue(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Authorization{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", entity.AuthActive, time.Now()).
+		Update("status", entity.AuthExpired)
+	return result.RowsAffected, result.Error
+}
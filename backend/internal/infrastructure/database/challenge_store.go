@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormChallengeStore 登录挑战存储的 PostgreSQL 实现，Redis 不可用时作为后备
+// 过期记录需要定期调用 DeleteExpiredChallenges 清理
+type GormChallengeStore struct {
+	db     *gorm.DB
+	limits repository.ChallengeLimits
+}
+
+func NewGormChallengeStore(db *gorm.DB, limits repository.ChallengeLimits) *GormChallengeStore {
+	return &GormChallengeStore{db: db, limits: limits}
+}
+
+// SaveChallenge 在事务中检查未使用挑战数并创建挑战
+// 同一地址的请求通过 advisory lock 串行化；IP 上限在并发时可能被少量超出
+func (r *GormChallengeStore) SaveChallenge(ctx context.Context, challenge *entity.LoginChallenge) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "login_challenges:"+challenge.Address).Error; err != nil {
+			return err
+		}
+
+		if err := r.checkLimit(tx, "wallet_address = ?", challenge.Address, r.limits.PerAddress); err != nil {
+			return err
+		}
+		if challenge.IPAddress != "" {
+			if err := r.checkLimit(tx, "ip_address = ?", challenge.IPAddress, r.limits.PerIP); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(challenge).Error
+	})
+}
+
+// checkLimit 统计满足条件的未使用、未过期挑战数
+func (r *GormChallengeStore) checkLimit(tx *gorm.DB, query string, arg string, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	var count int64
+	err := tx.Model(&entity.LoginChallenge{}).
+		Where(query, arg).
+		Where("used = ? AND expires_at > ?", false, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count >= int64(limit) {
+		return repository.ErrTooManyChallenges
+	}
+	return nil
+}
+
+// ConsumeChallenge 使用 UPDATE ... RETURNING 原子地将挑战标记为已使用，并发请求中只有一个能成功
+func (r *GormChallengeStore) ConsumeChallenge(ctx context.Context, nonce string) (*entity.LoginChallenge, error) {
+	var challenges []entity.LoginChallenge
+	result := r.db.WithContext(ctx).
+		Model(&challenges).
+		Clauses(clause.Returning{}).
+		Where("nonce = ? AND used = ? AND expires_at > ?", nonce, false, time.Now()).
+		Update("used", true)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || len(challenges) == 0 {
+		return nil, repository.ErrChallengeNotFound
+	}
+	return &challenges[0], nil
+}
+
+// DeleteExpiredChallenges 删除过期或已使用的挑战
+func (r *GormChallengeStore) DeleteExpiredChallenges(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ? OR used = ?", time.Now(), true).
+		Delete(&entity.LoginChallenge{}).Error
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"gorm.io/gorm"
+)
+
+// randomKey 返回测试独占的地址或 IP，测试结束时删除其挑战
+func randomKey(t *testing.T, db *gorm.DB, prefix string) string {
+	t.Helper()
+	suffix, err := crypto.RandomHex(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := prefix + suffix
+	t.Cleanup(func() {
+		db.Where("wallet_address LIKE ? OR ip_address LIKE ?", key+"%", key+"%").Delete(&entity.LoginChallenge{})
+	})
+	return key
+}
+
+func newLoginChallenge(t *testing.T, address, ip string, expiresAt time.Time) *entity.LoginChallenge {
+	t.Helper()
+	nonce, err := crypto.GenerateNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &entity.LoginChallenge{
+		Address:   address,
+		Nonce:     nonce,
+		ChainID:   "1",
+		Domain:    "localhost",
+		URI:       "http://localhost",
+		IPAddress: ip,
+		IssuedAt:  time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestGormConsumeChallengeConcurrent(t *testing.T) {
+	db := testDB(t)
+	store := NewGormChallengeStore(db, repository.ChallengeLimits{})
+	ctx := context.Background()
+
+	challenge := newLoginChallenge(t, randomKey(t, db, "0x"), "", time.Now().Add(time.Minute))
+	if err := store.SaveChallenge(ctx, challenge); err != nil {
+		t.Fatalf("SaveChallenge: %v", err)
+	}
+
+	const workers = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := store.ConsumeChallenge(ctx, challenge.Nonce)
+			if err != nil && !errors.Is(err, repository.ErrChallengeNotFound) {
+				t.Errorf("ConsumeChallenge: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Errorf("%d concurrent ConsumeChallenge calls succeeded, want 1", succeeded)
+	}
+}
+
+func TestGormSaveChallengeLimits(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	address, ip := randomKey(t, db, "0x"), randomKey(t, db, "ip-")
+	tests := []struct {
+		name    string
+		limits  repository.ChallengeLimits
+		address func(i int) string
+		ip      func(i int) string
+	}{
+		{
+			name:    "per address",
+			limits:  repository.ChallengeLimits{PerAddress: 3, PerIP: 100},
+			address: func(int) string { return address },
+			ip:      func(i int) string { return fmt.Sprintf("%s-%d", ip, i) },
+		},
+		{
+			name:    "per IP",
+			limits:  repository.ChallengeLimits{PerAddress: 100, PerIP: 3},
+			address: func(i int) string { return fmt.Sprintf("%s-%d", address, i) },
+			ip:      func(int) string { return ip },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewGormChallengeStore(db, tt.limits)
+			expiresAt := time.Now().Add(time.Minute)
+
+			var first *entity.LoginChallenge
+			for i := 0; i < 3; i++ {
+				challenge := newLoginChallenge(t, tt.address(i), tt.ip(i), expiresAt)
+				if err := store.SaveChallenge(ctx, challenge); err != nil {
+					t.Fatalf("SaveChallenge #%d: %v", i+1, err)
+				}
+				if first == nil {
+					first = challenge
+				}
+			}
+			if err := store.SaveChallenge(ctx, newLoginChallenge(t, tt.address(3), tt.ip(3), expiresAt)); !errors.Is(err, repository.ErrTooManyChallenges) {
+				t.Fatalf("SaveChallenge over the limit = %v, want ErrTooManyChallenges", err)
+			}
+
+			// 已使用的 nonce 不再占用配额
+			if _, err := store.ConsumeChallenge(ctx, first.Nonce); err != nil {
+				t.Fatalf("ConsumeChallenge: %v", err)
+			}
+			if err := store.SaveChallenge(ctx, newLoginChallenge(t, tt.address(3), tt.ip(3), expiresAt)); err != nil {
+				t.Errorf("SaveChallenge after consuming a nonce: %v", err)
+			}
+
+			// 清理本用例的挑战，避免影响下一个用例的计数
+			db.Where("wallet_address LIKE ? OR ip_address LIKE ?", address+"%", ip+"%").Delete(&entity.LoginChallenge{})
+		})
+	}
+}
+
+func TestGormSaveChallengeIgnoresExpiredNonces(t *testing.T) {
+	db := testDB(t)
+	store := NewGormChallengeStore(db, repository.ChallengeLimits{PerAddress: 1, PerIP: 1})
+	ctx := context.Background()
+
+	address, ip := randomKey(t, db, "0x"), randomKey(t, db, "ip-")
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, address, ip, time.Now().Add(-time.Second))); err != nil {
+		t.Fatalf("SaveChallenge: %v", err)
+	}
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, address, ip, time.Now().Add(time.Minute))); err != nil {
+		t.Fatalf("SaveChallenge with only an expired nonce outstanding: %v", err)
+	}
+	if err := store.SaveChallenge(ctx, newLoginChallenge(t, address, ip, time.Now().Add(time.Minute))); !errors.Is(err, repository.ErrTooManyChallenges) {
+		t.Errorf("SaveChallenge over the limit = %v, want ErrTooManyChallenges", err)
+	}
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/dedata/dedata-backend/internal/domain/entity"
@@ -113,15 +115,24 @@ func (r *GormCheckInRepository) MarkSuccess(ctx context.Context, id string, txHa
 		}).Error
 }
 
-// MarkFailed 标记为失败
-func (r *GormCheckInRepository) MarkFailed(ctx context.Context, id string, reason string) error {
-	return r.db.WithContext(ctx).
-		Model(&entity.CheckIn{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":         entity.CheckInIssueFailed,
-			"failure_reason": reason,
-		}).Error
+// MarkFailed 标记为发放失败，并在同一事务中按 graceDays 重新计算该用户的连续签到 (发放失败的签到日不再计入)
+func (r *GormCheckInRepository) MarkFailed(ctx context.Context, id string, reason string, graceDays int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var checkIn entity.CheckIn
+		if err := tx.Select("user_id").Where("id = ?", id).First(&checkIn).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&entity.CheckIn{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":         entity.CheckInIssueFailed,
+				"failure_reason": reason,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return recomputeStreaks(tx, graceDays, checkIn.UserID)
+	})
 }
 
 // FindByUserID 查询用户签到记录
@@ -145,33 +156,280 @@ func (r *GormCheckInRepository) FindByUserID(ctx context.Context, userID string,
 	return checkIns, total, err
 }
 
-// GetDailyStats 获取用户每日统计
+// GetDailyStats 获取用户每日统计，按签到日 (checkin_day) 分组，日期格式为 YYYY-MM-DD
 func (r *GormCheckInRepository) GetDailyStats(ctx context.Context, userID string) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
 	err := r.db.WithContext(ctx).
 		Model(&entity.CheckIn{}).
-		Select("DATE(issued_at) as date, SUM(CAST(token_amount AS DECIMAL)) as token_amount").
-		Where("user_id = ? AND status = ?", userID, entity.CheckInSuccess).
-		Group("DATE(issued_at)").
-		Order("date ASC").
+		Select("TO_CHAR(checkin_day, 'YYYY-MM-DD') as date, SUM(CAST(token_amount AS DECIMAL)) as token_amount").
+		Where("user_id = ? AND status = ? AND checkin_day IS NOT NULL", userID, entity.CheckInSuccess).
+		Group("checkin_day").
+		Order("checkin_day ASC").
 		Find(&results).Error
 
 	return results, err
 }
 
-// CheckTodayCheckin 检查用户今天是否已签到
-func (r *GormCheckInRepository) CheckTodayCheckin(ctx context.Context, userID string) (bool, error) {
+// CountDayCheckins 统计用户某天已占用名额的签到数
+func (r *GormCheckInRepository) CountDayCheckins(ctx context.Context, userID string, day time.Time) (int64, error) {
 	var count int64
-	today := time.Now().Format("2006-01-02")
-
 	err := r.db.WithContext(ctx).
 		Model(&entity.CheckIn{}).
-		Where("user_id = ? AND status = ? AND DATE(created_at) = ?",
-			userID, entity.CheckInSuccess, today).
+		Where("user_id = ? AND checkin_day = ?", userID, day.Format("2006-01-02")).
 		Count(&count).Error
+	return count, err
+}
+
+// advanceStreakSQL 签到日 @day 计入用户的连续签到：距上次超过 1 + @grace 天重新从 1 开始，
+// 晚于上次签到日时加 1，同一天 (或更早的签到日) 不变；返回计入后的连续签到天数
+const advanceStreakSQL = `
+	UPDATE users SET
+		current_streak = CASE
+			WHEN last_streak_day IS NULL OR CAST(@day AS DATE) - last_streak_day > 1 + @grace THEN 1
+			WHEN CAST(@day AS DATE) > last_streak_day THEN current_streak + 1
+			ELSE current_streak END,
+		longest_streak = GREATEST(longest_streak, CASE
+			WHEN last_streak_day IS NULL OR CAST(@day AS DATE) - last_streak_day > 1 + @grace THEN 1
+			WHEN CAST(@day AS DATE) > last_streak_day THEN current_streak + 1
+			ELSE current_streak END),
+		last_streak_day = GREATEST(COALESCE(last_streak_day, CAST(@day AS DATE)), CAST(@day AS DATE))
+	WHERE id = @user
+	RETURNING current_streak`
+
+// ClaimDaySlot 占用当天的下一个名额并推进用户的连续签到
+// 并发占用同一名额时唯一索引 idx_check_ins_user_day_slot 拒绝后写入的一方，按已提交的名额重新计算后重试，名额用完时返回 false
+func (r *GormCheckInRepository) ClaimDaySlot(ctx context.Context, checkIn *entity.CheckIn, day time.Time, limit, graceDays int) (bool, error) {
+	date := day.Format("2006-01-02")
+	// 每次冲突都意味着另一笔签到占用了一个名额，最多重试 limit 次
+	for attempt := 0; attempt < limit; attempt++ {
+		claimed, err := r.claimDaySlot(ctx, checkIn, date, limit, graceDays)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			continue
+		}
+		return claimed, err
+	}
+	return false, nil
+}
+
+// claimDaySlot 在同一事务中:
+// 1. 单条 UPDATE 计算下一个名额并在未超过 limit 时写入
+// 2. 推进用户的连续签到并记录到签到的 streak_days
+func (r *GormCheckInRepository) claimDaySlot(ctx context.Context, checkIn *entity.CheckIn, date string, limit, graceDays int) (bool, error) {
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE check_ins SET status = ?, checkin_day = ?, day_slot = s.slot
+			FROM (SELECT COUNT(*) + 1 AS slot FROM check_ins WHERE user_id = ? AND checkin_day = ?) s
+			WHERE check_ins.id = ? AND check_ins.status = ? AND s.slot <= ?`,
+			entity.CheckInPaymentSuccess, date,
+			checkIn.UserID, date,
+			checkIn.ID, entity.CheckInPendingPayment, limit,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var streak int
+		err := tx.Raw(advanceStreakSQL, map[string]interface{}{
+			"day":   date,
+			"grace": graceDays,
+			"user":  checkIn.UserID,
+		}).Scan(&streak).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.CheckIn{}).Where("id = ?", checkIn.ID).Update("streak_days", streak).Error; err != nil {
+			return err
+		}
+
+		claimed = true
+		return tx.Where("id = ?", checkIn.ID).First(checkIn).Error
+	})
+	return claimed, err
+}
+
+// recomputeCheckinDaysSQL 按 @tz 重新计算已占用名额的签到日和名额 (同一天按创建时间依次编号)
+// 调用前名额已取反，新名额不会与尚未更新的行冲突
+const recomputeCheckinDaysSQL = `
+	UPDATE check_ins c
+	SET checkin_day = s.checkin_day,
+		day_slot = s.day_slot
+	FROM (
+		SELECT id,
+			(created_at AT TIME ZONE @tz)::DATE AS checkin_day,
+			ROW_NUMBER() OVER (PARTITION BY user_id, (created_at AT TIME ZONE @tz)::DATE ORDER BY created_at) AS day_slot
+		FROM check_ins
+		WHERE checkin_day IS NOT NULL
+	) s
+	WHERE c.id = s.id`
+
+// resetStreaksSQL 清空连续签到，没有计入连续签到的签到日的用户保持为 0
+// @user 为空时处理所有用户
+const resetStreaksSQL = `
+	UPDATE users SET current_streak = 0, longest_streak = 0, last_streak_day = NULL
+	WHERE last_streak_day IS NOT NULL AND (CAST(@user AS TEXT) = '' OR id::TEXT = @user)`
+
+// recomputeStreaksSQL 按签到日重新计算用户的连续签到：与前一个签到日相隔超过 1 + @grace 天时开始新的一段
+// 发放失败 (@failed) 的签到日不计入；@user 为空时处理所有用户
+const recomputeStreaksSQL = `
+	WITH days AS (
+		SELECT DISTINCT user_id, checkin_day
+		FROM check_ins
+		WHERE checkin_day IS NOT NULL AND status <> @failed AND (CAST(@user AS TEXT) = '' OR user_id::TEXT = @user)
+	), breaks AS (
+		SELECT user_id,
+			checkin_day,
+			CASE WHEN checkin_day - LAG(checkin_day) OVER (PARTITION BY user_id ORDER BY checkin_day) <= 1 + @grace
+				THEN 0 ELSE 1 END AS new_run
+		FROM days
+	), islands AS (
+		SELECT user_id,
+			checkin_day,
+			SUM(new_run) OVER (PARTITION BY user_id ORDER BY checkin_day) AS grp
+		FROM breaks
+	), runs AS (
+		SELECT user_id, COUNT(*) AS len, MAX(checkin_day) AS last_day
+		FROM islands
+		GROUP BY user_id, grp
+	), streaks AS (
+		SELECT DISTINCT ON (user_id)
+			user_id,
+			len AS current_streak,
+			last_day,
+			MAX(len) OVER (PARTITION BY user_id) AS longest_streak
+		FROM runs
+		ORDER BY user_id, last_day DESC
+	)
+	UPDATE users u
+	SET current_streak = s.current_streak,
+		longest_streak = s.longest_streak,
+		last_streak_day = s.last_day
+	FROM streaks s
+	WHERE u.id = s.user_id`
+
+// recomputeStreaks 按 graceDays 重新计算 userID (为空时为所有用户) 的连续签到
+func recomputeStreaks(tx *gorm.DB, graceDays int, userID string) error {
+	if err := tx.Exec(resetStreaksSQL, map[string]interface{}{"user": userID}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(recomputeStreaksSQL, map[string]interface{}{
+		"grace":  graceDays,
+		"failed": entity.CheckInIssueFailed,
+		"user":   userID,
+	}).Error
+}
+
+// recomputeCheckinDaysLockKey 重新计算签到日与连续签到使用的 advisory lock，多个实例同时启动时只有一个执行重新计算
+const recomputeCheckinDaysLockKey = 0x636b696e64617973 // "ckindays"
+
+// RecomputeCheckinDays 比较 checkin_settings 记录的时区，相同时直接返回，不同时在同一事务中:
+// 1. 获取 advisory lock 并锁定 check_ins 阻止并发占用名额，重新读取时区 (其他实例可能已完成重新计算)
+// 2. 将已有名额取反以避开唯一索引，按新时区重新计算签到日和名额
+// 3. 记录新时区，并清除连续签到的宽限天数，使 RecomputeStreaks 按新的签到日重新计算
+func (r *GormCheckInRepository) RecomputeCheckinDays(ctx context.Context, timezone string) (bool, error) {
+	// 时区未变化是常态，不加锁检查，避免每次启动都锁表
+	current, err := r.dayTimezone(r.db.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	if current.Valid && current.String == timezone {
+		return false, nil
+	}
+
+	recomputed := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockForRecompute(tx); err != nil {
+			return err
+		}
+
+		current, err := r.dayTimezone(tx)
+		if err != nil {
+			return err
+		}
+		if current.Valid && current.String == timezone {
+			return nil
+		}
+
+		if err := tx.Exec("UPDATE check_ins SET day_slot = -day_slot WHERE checkin_day IS NOT NULL").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(recomputeCheckinDaysSQL, map[string]interface{}{"tz": timezone}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE checkin_settings SET day_timezone = ?, streak_grace_days = NULL WHERE id = 1", timezone).Error; err != nil {
+			return err
+		}
+
+		recomputed = true
+		return nil
+	})
+	return recomputed, err
+}
+
+// RecomputeStreaks 比较 checkin_settings 记录的宽限天数，相同时直接返回，不同 (或为 NULL) 时在同一事务中
+// 加锁后重新读取，按 graceDays 重新计算所有用户的连续签到并记录宽限天数
+// 已签到记录的 streak_days 与已发放的奖励不变
+func (r *GormCheckInRepository) RecomputeStreaks(ctx context.Context, graceDays int) (bool, error) {
+	current, err := r.streakGraceDays(r.db.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	if current.Valid && current.Int64 == int64(graceDays) {
+		return false, nil
+	}
+
+	recomputed := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockForRecompute(tx); err != nil {
+			return err
+		}
+
+		current, err := r.streakGraceDays(tx)
+		if err != nil {
+			return err
+		}
+		if current.Valid && current.Int64 == int64(graceDays) {
+			return nil
+		}
+
+		if err := recomputeStreaks(tx, graceDays, ""); err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE checkin_settings SET streak_grace_days = ? WHERE id = 1", graceDays).Error; err != nil {
+			return err
+		}
+
+		recomputed = true
+		return nil
+	})
+	return recomputed, err
+}
+
+// lockForRecompute 获取 advisory lock 并锁定 check_ins：多个实例同时启动时只有一个执行重新计算，
+// 重新计算期间不会有新的签到占用名额或推进连续签到
+func lockForRecompute(tx *gorm.DB) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", recomputeCheckinDaysLockKey).Error; err != nil {
+		return err
+	}
+	return tx.Exec("LOCK TABLE check_ins IN SHARE ROW EXCLUSIVE MODE").Error
+}
+
+// dayTimezone 读取计算已有签到日所用的时区，未知时为 NULL
+func (r *GormCheckInRepository) dayTimezone(db *gorm.DB) (sql.NullString, error) {
+	var current sql.NullString
+	err := db.Raw("SELECT day_timezone FROM checkin_settings WHERE id = 1").Scan(&current).Error
+	return current, err
+}
 
-	return count > 0, err
+// streakGraceDays 读取计算已有连续签到所用的宽限天数，未知时为 NULL
+func (r *GormCheckInRepository) streakGraceDays(db *gorm.DB) (sql.NullInt64, error) {
+	var current sql.NullInt64
+	err := db.Raw("SELECT streak_grace_days FROM checkin_settings WHERE id = 1").Scan(&current).Error
+	return current, err
 }
 
 // CountSuccessCheckinsByUserID 获取用户成功签到的总次数
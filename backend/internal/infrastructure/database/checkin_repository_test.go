@@ -0,0 +1,297 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB 连接 TEST_DATABASE_URL 指向的已执行迁移的 PostgreSQL，未设置时跳过
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+	return db
+}
+
+// createTestUser 创建测试用户，测试结束时删除 (签到记录级联删除)
+func createTestUser(t *testing.T, db *gorm.DB) *entity.User {
+	t.Helper()
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	address := "0x" + hex.EncodeToString(b)
+	user := &entity.User{
+		DID:           "did:pkh:eip155:1:" + address,
+		Account:       "eip155:1:" + address,
+		WalletAddress: address,
+		ChainID:       1,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() { db.Delete(&entity.User{}, "id = ?", user.ID) })
+	return user
+}
+
+func TestClaimDaySlotConcurrent(t *testing.T) {
+	db := testDB(t)
+	repo := NewGormCheckInRepository(db)
+	user := createTestUser(t, db)
+	ctx := context.Background()
+
+	const workers, limit = 10, 3
+	checkIns := make([]*entity.CheckIn, workers)
+	for i := range checkIns {
+		checkIns[i] = &entity.CheckIn{UserID: user.ID, Status: entity.CheckInPendingPayment}
+		if err := repo.Create(ctx, checkIns[i]); err != nil {
+			t.Fatalf("create check-in: %v", err)
+		}
+	}
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed int
+	)
+	start := make(chan struct{})
+	for _, c := range checkIns {
+		wg.Add(1)
+		go func(c *entity.CheckIn) {
+			defer wg.Done()
+			<-start
+			ok, err := repo.ClaimDaySlot(ctx, c, day, limit, 0)
+			if err != nil {
+				t.Errorf("ClaimDaySlot: %v", err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}(c)
+	}
+	close(start)
+	wg.Wait()
+
+	// 冲突的一方重试而不是报错或直接放弃，名额恰好用完
+	if claimed != limit {
+		t.Errorf("claimed %d slots, want %d", claimed, limit)
+	}
+
+	var slots []int
+	err := db.Model(&entity.CheckIn{}).
+		Where("user_id = ? AND checkin_day = ?", user.ID, day.Format("2006-01-02")).
+		Pluck("day_slot", &slots).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(slots)
+	if len(slots) != limit || slots[0] != 1 || slots[limit-1] != limit {
+		t.Errorf("day slots = %v, want 1..%d", slots, limit)
+	}
+
+	var pending int64
+	db.Model(&entity.CheckIn{}).Where("user_id = ? AND status = ?", user.ID, entity.CheckInPendingPayment).Count(&pending)
+	if pending != workers-limit {
+		t.Errorf("%d check-ins still pending, want %d", pending, workers-limit)
+	}
+}
+
+func TestClaimDaySlotStreak(t *testing.T) {
+	db := testDB(t)
+	repo := NewGormCheckInRepository(db)
+	user := createTestUser(t, db)
+	ctx := context.Background()
+
+	const graceDays = 1
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		day        time.Time
+		wantStreak int
+	}{
+		{day(1), 1},
+		{day(2), 2},
+		{day(2), 2}, // 同一天再次签到不增加
+		{day(4), 3}, // 中断 1 天在 grace 内
+		{day(7), 1}, // 中断 2 天重新开始
+	}
+	for i, tc := range cases {
+		c := &entity.CheckIn{UserID: user.ID, Status: entity.CheckInPendingPayment}
+		if err := repo.Create(ctx, c); err != nil {
+			t.Fatalf("create check-in: %v", err)
+		}
+		ok, err := repo.ClaimDaySlot(ctx, c, tc.day, 2, graceDays)
+		if err != nil || !ok {
+			t.Fatalf("case %d: ClaimDaySlot = %v, %v", i, ok, err)
+		}
+		if c.StreakDays != tc.wantStreak {
+			t.Errorf("case %d: streak days = %d, want %d", i, c.StreakDays, tc.wantStreak)
+		}
+	}
+
+	var got entity.User
+	if err := db.First(&got, "id = ?", user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.CurrentStreak != 1 || got.LongestStreak != 3 {
+		t.Errorf("current/longest streak = %d/%d, want 1/3", got.CurrentStreak, got.LongestStreak)
+	}
+}
+
+func TestMarkFailedRemovesDayFromStreak(t *testing.T) {
+	db := testDB(t)
+	repo := NewGormCheckInRepository(db)
+	user := createTestUser(t, db)
+	ctx := context.Background()
+
+	checkIns := make([]*entity.CheckIn, 3)
+	for i := range checkIns {
+		checkIns[i] = &entity.CheckIn{UserID: user.ID, Status: entity.CheckInPendingPayment}
+		if err := repo.Create(ctx, checkIns[i]); err != nil {
+			t.Fatalf("create check-in: %v", err)
+		}
+		day := time.Date(2026, 3, 1+i, 0, 0, 0, 0, time.UTC)
+		if ok, err := repo.ClaimDaySlot(ctx, checkIns[i], day, 1, 0); err != nil || !ok {
+			t.Fatalf("ClaimDaySlot day %d = %v, %v", i+1, ok, err)
+		}
+	}
+
+	// 第 2 天发放失败，不再计入：剩下第 1 天与第 3 天两段
+	if err := repo.MarkFailed(ctx, checkIns[1].ID, "issue failed", 0); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	var got entity.User
+	if err := db.First(&got, "id = ?", user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.CurrentStreak != 1 || got.LongestStreak != 1 {
+		t.Errorf("current/longest streak = %d/%d, want 1/1", got.CurrentStreak, got.LongestStreak)
+	}
+
+	// 所有签到日都发放失败时连续签到清零
+	for _, c := range []*entity.CheckIn{checkIns[0], checkIns[2]} {
+		if err := repo.MarkFailed(ctx, c.ID, "issue failed", 0); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+	}
+	if err := db.First(&got, "id = ?", user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.CurrentStreak != 0 || got.LongestStreak != 0 || got.LastStreakDay != nil {
+		t.Errorf("streak after all days failed = %d/%d/%v, want 0/0/nil", got.CurrentStreak, got.LongestStreak, got.LastStreakDay)
+	}
+}
+
+// 修改签到日时区后，按新时区重新计算已占用名额的签到日和名额
+func TestRecomputeCheckinDays(t *testing.T) {
+	db := testDB(t)
+	repo := NewGormCheckInRepository(db)
+	user := createTestUser(t, db)
+	ctx := context.Background()
+
+	// 测试结束时恢复原来的时区与宽限天数
+	timezone, err := repo.dayTimezone(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graceDays, err := repo.streakGraceDays(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if timezone.Valid {
+			if _, err := repo.RecomputeCheckinDays(ctx, timezone.String); err != nil {
+				t.Errorf("restore timezone: %v", err)
+			}
+		}
+		if graceDays.Valid {
+			if _, err := repo.RecomputeStreaks(ctx, int(graceDays.Int64)); err != nil {
+				t.Errorf("restore streak grace days: %v", err)
+			}
+		}
+	})
+	if _, err := repo.RecomputeCheckinDays(ctx, "UTC"); err != nil {
+		t.Fatalf("RecomputeCheckinDays(UTC): %v", err)
+	}
+
+	// UTC 下分属 3 月 1 日和 2 日；UTC+8 下都在 2 日，UTC-5 下都在 1 日
+	day := func(d int) *time.Time {
+		v := time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	slot := func(n int) *int { return &n }
+	checkIns := []*entity.CheckIn{
+		{CreatedAt: time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), CheckInDay: day(1), DaySlot: slot(1)},
+		{CreatedAt: time.Date(2026, 3, 2, 0, 30, 0, 0, time.UTC), CheckInDay: day(2), DaySlot: slot(1)},
+		{CreatedAt: time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC), CheckInDay: day(2), DaySlot: slot(2)},
+	}
+	for _, c := range checkIns {
+		c.UserID = user.ID
+		c.Status = entity.CheckInSuccess
+		if err := db.Create(c).Error; err != nil {
+			t.Fatalf("create check-in: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		timezone string
+		wantDays []string
+	}{
+		{"Asia/Shanghai", []string{"2026-03-02", "2026-03-02", "2026-03-02"}},
+		{"America/New_York", []string{"2026-03-01", "2026-03-01", "2026-03-01"}},
+		{"UTC", []string{"2026-03-01", "2026-03-02", "2026-03-02"}},
+	} {
+		recomputed, err := repo.RecomputeCheckinDays(ctx, tt.timezone)
+		if err != nil || !recomputed {
+			t.Fatalf("RecomputeCheckinDays(%s) = %v, %v", tt.timezone, recomputed, err)
+		}
+
+		var got []*entity.CheckIn
+		if err := db.Where("user_id = ?", user.ID).Order("created_at").Find(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+		// 同一天按创建时间从 1 开始编号
+		wantSlot := 0
+		for i, c := range got {
+			if i > 0 && tt.wantDays[i] == tt.wantDays[i-1] {
+				wantSlot++
+			} else {
+				wantSlot = 1
+			}
+			if c.CheckInDay == nil || c.DaySlot == nil {
+				t.Fatalf("%s: check-in %d lost its day slot", tt.timezone, i)
+			}
+			if day := c.CheckInDay.Format("2006-01-02"); day != tt.wantDays[i] || *c.DaySlot != wantSlot {
+				t.Errorf("%s: check-in %d = %s slot %d, want %s slot %d", tt.timezone, i, day, *c.DaySlot, tt.wantDays[i], wantSlot)
+			}
+		}
+	}
+
+	// 时区未变化时不重新计算
+	if recomputed, err := repo.RecomputeCheckinDays(ctx, "UTC"); err != nil || recomputed {
+		t.Errorf("RecomputeCheckinDays with unchanged timezone = %v, %v; want false", recomputed, err)
+	}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCredentialRepository(db *gorm.DB) *GormCredentialRepository {
+	return &GormCredentialRepository{db: db}
+}
+
+// NextStatusIndex 从序列中分配新的吊销状态序号
+func (r *GormCredentialRepository) NextStatusIndex(ctx context.Context) (int64, error) {
+	var index int64
+	err := r.db.WithContext(ctx).Raw("SELECT nextval('credential_status_index_seq')").Scan(&index).Error
+	return index, err
+}
+
+// CurrentStatusIndex 读取序列最近分配的值，尚未调用 nextval 时返回 0
+func (r *GormCredentialRepository) CurrentStatusIndex(ctx context.Context) (int64, error) {
+	var index int64
+	err := r.db.WithContext(ctx).
+		Raw("SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM credential_status_index_seq").
+		Scan(&index).Error
+	return index, err
+}
+
+// Create 保存已签发的 credential
+func (r *GormCredentialRepository) Create(ctx context.Context, credential *entity.Credential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+// FindByID 通过 ID 查找 credential
+func (r *GormCredentialRepository) FindByID(ctx context.Context, id string) (*entity.Credential, error) {
+	var credential entity.Credential
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// FindByUserID 查询用户的 credential，最新的在前
+func (r *GormCredentialRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Credential, error) {
+	var credentials []*entity.Credential
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("issued_at DESC").
+		Find(&credentials).Error
+	return credentials, err
+}
+
+// Revoke 吊销 credential (已吊销的保持原吊销时间)
+func (r *GormCredentialRepository) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Credential{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// FindRevokedStatusIndexes 查询 [from, to) 区间内已吊销的状态序号
+func (r *GormCredentialRepository) FindRevokedStatusIndexes(ctx context.Context, from, to int64) ([]int64, error) {
+	var indexes []int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.Credential{}).
+		Where("status_index >= ? AND status_index < ? AND revoked_at IS NOT NULL", from, to).
+		Pluck("status_index", &indexes).Error
+	return indexes, err
+}
@@ -22,8 +22,10 @@ func Connect(cfg *config.DatabaseConfig, log *zap.Logger) error {
 	}
 
 	// 连接数据库
+	// TranslateError: 唯一索引冲突等数据库错误转换为 gorm.ErrDuplicatedKey 等通用错误
 	db, err := gorm.Open(postgres.Open(cfg.GetDSN()), &gorm.Config{
-		Logger: gormLogger,
+		Logger:         gormLogger,
+		TranslateError: true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect database: %w", err)
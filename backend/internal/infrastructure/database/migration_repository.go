@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"gorm.io/gorm"
+)
+
+// ErrMigrationConflict 迁移执行时数据已被并发修改 (主钱包已变更或迁移已处理)
+var ErrMigrationConflict = errors.New("migration conflicts with a concurrent change")
+
+type GormMigrationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormMigrationRepository(db *gorm.DB) *GormMigrationRepository {
+	return &GormMigrationRepository{db: db}
+}
+
+// Create 创建迁移记录
+func (r *GormMigrationRepository) Create(ctx context.Context, migration *entity.WalletMigration) error {
+	return r.db.WithContext(ctx).Create(migration).Error
+}
+
+// FindByID 通过 ID 查找迁移记录
+func (r *GormMigrationRepository) FindByID(ctx context.Context, id string) (*entity.WalletMigration, error) {
+	var migration entity.WalletMigration
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&migration).Error
+	if err != nil {
+		return nil, err
+	}
+	return &migration, nil
+}
+
+// FindByUserID 查询用户的迁移历史，最新的在前
+func (r *GormMigrationRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.WalletMigration, error) {
+	var migrations []*entity.WalletMigration
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&migrations).Error
+	return migrations, err
+}
+
+// FindPendingByUserID 查找用户等待批准的迁移
+func (r *GormMigrationRepository) FindPendingByUserID(ctx context.Context, userID string) (*entity.WalletMigration, error) {
+	var migration entity.WalletMigration
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, entity.MigrationPending).
+		Order("created_at DESC").
+		First(&migration).Error
+	if err != nil {
+		return nil, err
+	}
+	return &migration, nil
+}
+
+// FindByStatus 按状态查询迁移记录（分页）
+func (r *GormMigrationRepository) FindByStatus(ctx context.Context, status entity.MigrationStatus, page, limit int) ([]*entity.WalletMigration, int64, error) {
+	var migrations []*entity.WalletMigration
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.WalletMigration{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&migrations).Error
+
+	return migrations, total, err
+}
+
+// Close 将等待中的迁移标记为取消或拒绝，迁移已不是 PENDING 时返回 ErrMigrationConflict
+func (r *GormMigrationRepository) Close(ctx context.Context, id string, status entity.MigrationStatus, reviewedBy, reason string) error {
+	updates := map[string]interface{}{
+		"status": status,
+	}
+	if reviewedBy != "" {
+		updates["reviewed_by"] = reviewedBy
+	}
+	if reason != "" {
+		updates["reason"] = reason
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&entity.WalletMigration{}).
+		Where("id = ? AND status = ?", id, entity.MigrationPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMigrationConflict
+	}
+	return nil
+}
+
+// Execute 在同一事务中完成迁移 (仅支持 EVM 主钱包，新旧钱包都属于 eip155):
+// 1. 将 users.wallet_address 从 FromAddress 替换为 ToAddress，同时更新 CAIP-10 标识和链 ID；主钱包不是 EVM 钱包时返回 ErrMigrationConflict
+// 2. 删除旧钱包的绑定，将新钱包设为主钱包 (已绑定则提升为主钱包)；migration.KeyVerified 时同时标记新钱包已证明持有私钥
+// 3. 写入迁移记录 (ID 为空时新建，否则将 PENDING 记录标记为完成)
+func (r *GormMigrationRepository) Execute(ctx context.Context, migration *entity.WalletMigration, reviewedBy string) error {
+	account, err := caip.NewAccountID(string(chain.EIP155), strconv.FormatInt(migration.ChainID, 10), migration.ToAddress)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND chain_namespace = ? AND LOWER(wallet_address) = ?", migration.UserID, string(chain.EIP155), strings.ToLower(migration.FromAddress)).
+			Updates(map[string]interface{}{
+				"wallet_address":  migration.ToAddress,
+				"account":         account.String(),
+				"chain_id":        migration.ChainID,
+				"chain_namespace": string(chain.EIP155),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMigrationConflict
+		}
+
+		if err := tx.Where("user_id = ? AND LOWER(address) = ?", migration.UserID, strings.ToLower(migration.FromAddress)).
+			Delete(&entity.UserWallet{}).Error; err != nil {
+			return err
+		}
+
+		promote := map[string]interface{}{"is_primary": true}
+		if migration.KeyVerified {
+			promote["key_verified"] = true
+		}
+		result = tx.Model(&entity.UserWallet{}).
+			Where("user_id = ? AND account = ?", migration.UserID, account.String()).
+			Updates(promote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Create(&entity.UserWallet{
+				UserID:      migration.UserID,
+				Account:     account.String(),
+				Address:     migration.ToAddress,
+				ChainID:     migration.ChainID,
+				Namespace:   string(chain.EIP155),
+				IsPrimary:   true,
+				KeyVerified: migration.KeyVerified,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if migration.ID == "" {
+			migration.Status = entity.MigrationCompleted
+			migration.CompletedAt = &now
+			if reviewedBy != "" {
+				migration.ReviewedBy = &reviewedBy
+			}
+			return tx.Create(migration).Error
+		}
+
+		updates := map[string]interface{}{
+			"status":       entity.MigrationCompleted,
+			"completed_at": now,
+		}
+		if reviewedBy != "" {
+			updates["reviewed_by"] = reviewedBy
+		}
+		result = tx.Model(&entity.WalletMigration{}).
+			Where("id = ? AND status = ?", migration.ID, entity.MigrationPending).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMigrationConflict
+		}
+
+		migration.Status = entity.MigrationCompleted
+		migration.CompletedAt = &now
+		if reviewedBy != "" {
+			migration.ReviewedBy = &reviewedBy
+		}
+		return nil
+	})
+}
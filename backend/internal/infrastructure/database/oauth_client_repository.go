@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormOAuthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOAuthClientRepository(db *gorm.DB) *GormOAuthClientRepository {
+	return &GormOAuthClientRepository{db: db}
+}
+
+// Create 注册客户端
+func (r *GormOAuthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	return r.db.WithContext(ctx).Create(client).Error
+}
+
+// FindByID 通过 client_id 查找客户端
+func (r *GormOAuthClientRepository) FindByID(ctx context.Context, id string) (*entity.OAuthClient, error) {
+	var client entity.OAuthClient
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&client).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FindAll 查询所有客户端，最新的在前
+func (r *GormOAuthClientRepository) FindAll(ctx context.Context) ([]*entity.OAuthClient, error) {
+	var clients []*entity.OAuthClient
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&clients).Error
+	return clients, err
+}
+
+// Delete 删除客户端
+func (r *GormOAuthClientRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.OAuthClient{}).Error
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormPasskeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPasskeyRepository(db *gorm.DB) *GormPasskeyRepository {
+	return &GormPasskeyRepository{db: db}
+}
+
+// Create 保存新注册的 passkey
+func (r *GormPasskeyRepository) Create(ctx context.Context, passkey *entity.Passkey) error {
+	return r.db.WithContext(ctx).Create(passkey).Error
+}
+
+// FindByID 通过 ID 查找 passkey
+func (r *GormPasskeyRepository) FindByID(ctx context.Context, id string) (*entity.Passkey, error) {
+	var passkey entity.Passkey
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&passkey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+// FindByCredentialID 通过 WebAuthn 凭证 ID 查找 passkey
+func (r *GormPasskeyRepository) FindByCredentialID(ctx context.Context, credentialID string) (*entity.Passkey, error) {
+	var passkey entity.Passkey
+	err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&passkey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+// FindByUserID 查询用户的所有 passkey，最新的在前
+func (r *GormPasskeyRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Passkey, error) {
+	var passkeys []*entity.Passkey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&passkeys).Error
+	return passkeys, err
+}
+
+// CountByUserID 统计用户的 passkey 数量
+func (r *GormPasskeyRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Passkey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// RecordUse 登录成功后更新签名计数器、备份状态与最后使用时间
+func (r *GormPasskeyRepository) RecordUse(ctx context.Context, id string, signCount int64, backedUp bool, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Passkey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"sign_count":   signCount,
+			"backed_up":    backedUp,
+			"last_used_at": usedAt,
+		}).Error
+}
+
+// Delete 删除 passkey
+func (r *GormPasskeyRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.Passkey{}).Error
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSessionRepository(db *gorm.DB) *GormSessionRepository {
+	return &GormSessionRepository{db: db}
+}
+
+// Create 创建会话
+func (r *GormSessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindByID 通过 ID 查找会话
+func (r *GormSessionRepository) FindByID(ctx context.Context, id string) (*entity.Session, error) {
+	var session entity.Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID 查询用户所有未吊销且未过期的会话
+func (r *GormSessionRepository) FindActiveByUserID(ctx context.Context, userID string) ([]*entity.Session, error) {
+	var sessions []*entity.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch 更新会话最后活跃时间
+func (r *GormSessionRepository) Touch(ctx context.Context, id string, ip, userAgent string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_seen_at": time.Now(),
+			"ip_address":   ip,
+			"user_agent":   userAgent,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// Revoke 标记会话已吊销
+func (r *GormSessionRepository) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
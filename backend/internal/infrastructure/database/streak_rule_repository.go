@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormStreakRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStreakRuleRepository(db *gorm.DB) *GormStreakRuleRepository {
+	return &GormStreakRuleRepository{db: db}
+}
+
+// List 按 min_days 升序返回所有规则
+func (r *GormStreakRuleRepository) List(ctx context.Context) ([]*entity.StreakRule, error) {
+	var rules []*entity.StreakRule
+	err := r.db.WithContext(ctx).Order("min_days ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Replace 在同一事务中删除所有规则并写入 rules，Worker 不会读到只替换了一部分的规则
+func (r *GormStreakRuleRepository) Replace(ctx context.Context, rules []*entity.StreakRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entity.StreakRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(rules).Error
+	})
+}
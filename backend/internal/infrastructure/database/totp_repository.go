@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormTOTPRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTOTPRepository(db *gorm.DB) *GormTOTPRepository {
+	return &GormTOTPRepository{db: db}
+}
+
+// Save 创建或覆盖 TOTP 绑定
+func (r *GormTOTPRepository) Save(ctx context.Context, enrollment *entity.TOTPEnrollment) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"secret_encrypted", "confirmed_at", "last_used_step", "updated_at"}),
+		}).
+		Create(enrollment).Error
+}
+
+// FindByUserID 查找用户的 TOTP 绑定
+func (r *GormTOTPRepository) FindByUserID(ctx context.Context, userID string) (*entity.TOTPEnrollment, error) {
+	var enrollment entity.TOTPEnrollment
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&enrollment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// Confirm 确认绑定并记录通过验证的时间步
+func (r *GormTOTPRepository) Confirm(ctx context.Context, userID string, step int64, confirmedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.TOTPEnrollment{}).
+		Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"confirmed_at":   confirmedAt,
+			"last_used_step": step,
+		}).Error
+}
+
+// AdvanceStep 仅当 step 大于上次使用的时间步时更新
+func (r *GormTOTPRepository) AdvanceStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.TOTPEnrollment{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete 删除 TOTP 绑定及其恢复码
+func (r *GormTOTPRepository) Delete(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.TOTPRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&entity.TOTPEnrollment{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes 删除旧恢复码并保存新的恢复码摘要
+func (r *GormTOTPRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	codes := make([]*entity.TOTPRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &entity.TOTPRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.TOTPRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeRecoveryCode 原子地将未使用的恢复码标记为已使用
+func (r *GormTOTPRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.TOTPRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes 统计剩余可用的恢复码
+func (r *GormTOTPRepository) CountRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.TOTPRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -2,14 +2,18 @@ package database
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/pkg/did"
 	"gorm.io/gorm"
 )
 
+// likeEscaper 转义 LIKE 模式中的通配符，配合 ESCAPE '\' 使搜索词按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type GormUserRepository struct {
 	db *gorm.DB
 }
@@ -21,9 +25,19 @@ func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
 // FindByAddress 通过地址查找用户
 func (r *GormUserRepository) FindByAddress(ctx context.Context, address string) (*entity.User, error) {
 	var user entity.User
-	// 地址不区分大小写
-	address = strings.ToLower(address)
-	err := r.db.WithContext(ctx).Where("LOWER(wallet_address) = ?", address).First(&user).Error
+	// EVM 地址不区分大小写
+	query, arg := addressCondition("wallet_address", address)
+	err := r.db.WithContext(ctx).Where(query, arg).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByAccount 通过主钱包的 CAIP-10 标识查找用户
+func (r *GormUserRepository) FindByAccount(ctx context.Context, account string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).Where("account = ?", account).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +128,56 @@ func (r *GormUserRepository) GetUserRankByRewards(ctx context.Context, userID st
 	return int(rank) + 1, nil
 }
 
+// Search 按条件搜索用户（分页）
+func (r *GormUserRepository) Search(ctx context.Context, filter repository.UserFilter, page, limit int) ([]*entity.User, int64, error) {
+	var users []*entity.User
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.User{})
+	if filter.Query != "" {
+		like := "%" + likeEscaper.Replace(strings.ToLower(filter.Query)) + "%"
+		query = query.Where(
+			`(LOWER(wallet_address) LIKE ? ESCAPE '\' OR LOWER(did) LIKE ? ESCAPE '\' OR id IN (SELECT user_id FROM profiles WHERE LOWER(display_name) LIKE ? ESCAPE '\'))`,
+			like, like, like,
+		)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Profile").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&users).Error
+
+	return users, total, err
+}
+
+// UpdateStatus 更新用户状态
+func (r *GormUserRepository) UpdateStatus(ctx context.Context, userID string, status entity.UserStatus) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("status", status).Error
+}
+
+// UpdateRole 更新用户角色
+func (r *GormUserRepository) UpdateRole(ctx context.Context, userID string, role entity.UserRole) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", userID).
+		Update("role", role).Error
+}
+
 // FindByIDWithProfile 通过 ID 查找用户(包含 Profile)
 func (r *GormUserRepository) FindByIDWithProfile(ctx context.Context, id string) (*entity.User, error) {
 	var user entity.User
@@ -127,7 +191,7 @@ func (r *GormUserRepository) FindByIDWithProfile(ctx context.Context, id string)
 	return &user, nil
 }
 
-// GenerateDID 生成 DID (简化版: did:dedata:address)
+// GenerateDID 生成 DID (did:dedata:address)
 func GenerateDID(address string) string {
-	return fmt.Sprintf("did:dedata:%s", strings.ToLower(address))
+	return did.Format(address)
 }
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+)
+
+// 搜索词中的 % 和 _ 按字面匹配，不作为通配符
+func TestSearchEscapesWildcards(t *testing.T) {
+	db := testDB(t)
+	repo := NewGormUserRepository(db)
+	ctx := context.Background()
+
+	literal := createTestUser(t, db)
+	wildcard := createTestUser(t, db)
+	suffix := literal.ID[:8]
+	for user, name := range map[*entity.User]string{
+		literal:  "50%_off_" + suffix,
+		wildcard: "50% off " + suffix,
+	} {
+		displayName := name
+		if err := db.Create(&entity.Profile{UserID: user.ID, DisplayName: &displayName}).Error; err != nil {
+			t.Fatalf("create profile: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{"50%_off_" + suffix, 1},
+		{"50%" + suffix, 0},
+		{suffix, 2},
+	} {
+		users, total, err := repo.Search(ctx, repository.UserFilter{Query: tt.query}, 1, 10)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", tt.query, err)
+		}
+		if int(total) != tt.want || len(users) != tt.want {
+			t.Errorf("Search(%q) = %d users (total %d), want %d", tt.query, len(users), total, tt.want)
+		}
+		if tt.want == 1 && users[0].ID != literal.ID {
+			t.Errorf("Search(%q) matched %s, want %s", tt.query, users[0].ID, literal.ID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+type GormWalletRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWalletRepository(db *gorm.DB) *GormWalletRepository {
+	return &GormWalletRepository{db: db}
+}
+
+// Create 绑定钱包
+func (r *GormWalletRepository) Create(ctx context.Context, wallet *entity.UserWallet) error {
+	return r.db.WithContext(ctx).Create(wallet).Error
+}
+
+// FindByAddress 通过地址查找钱包 (EVM 地址不区分大小写)
+func (r *GormWalletRepository) FindByAddress(ctx context.Context, address string) (*entity.UserWallet, error) {
+	var wallet entity.UserWallet
+	query, arg := addressCondition("address", address)
+	err := r.db.WithContext(ctx).Where(query, arg).First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// FindByAccount 通过 CAIP-10 标识查找钱包 (标识已规范化，精确匹配)
+func (r *GormWalletRepository) FindByAccount(ctx context.Context, account string) (*entity.UserWallet, error) {
+	var wallet entity.UserWallet
+	err := r.db.WithContext(ctx).Where("account = ?", account).First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// UpdateAccount 回填或规范化钱包的 CAIP-10 标识
+func (r *GormWalletRepository) UpdateAccount(ctx context.Context, id, account string) error {
+	return r.db.WithContext(ctx).Model(&entity.UserWallet{}).Where("id = ?", id).Update("account", account).Error
+}
+
+// MarkKeyVerified 记录钱包的签名证明持有私钥
+func (r *GormWalletRepository) MarkKeyVerified(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&entity.UserWallet{}).Where("id = ?", id).Update("key_verified", true).Error
+}
+
+// FindByUserID 查询用户绑定的所有钱包，主钱包在前
+func (r *GormWalletRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.UserWallet, error) {
+	var wallets []*entity.UserWallet
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_primary DESC, created_at ASC").
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// Delete 解绑钱包
+func (r *GormWalletRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.UserWallet{}).Error
+}
+
+// addressCondition 地址查询条件: 0x 开头的 EVM 地址不区分大小写，base58 地址 (Solana) 区分大小写
+func addressCondition(column, address string) (string, string) {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return "LOWER(" + column + ") = ?", strings.ToLower(address)
+	}
+	return column + " = ?", address
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/pkg/reward"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
@@ -148,15 +149,12 @@ func (b *BlockchainTokenIssuer) IssueToken(ctx context.Context, toAddress string
 		return "", fmt.Errorf("invalid recipient address: %s", toAddress)
 	}
 
-	// Parse amount (assume amount is in tokens, need to convert to smallest unit)
-	// Example: "10" -> 10 * 10^18 (assuming 18 decimals)
-	amountBig, ok := new(big.Int).SetString(amount, 10)
-	if !ok {
-		return "", fmt.Errorf("invalid amount: %s", amount)
+	// Parse amount (in tokens, may be fractional with streak multipliers) and convert to smallest unit
+	// Example: "12.5" -> 12.5 * 10^18 (assuming 18 decimals)
+	amountInWei, err := reward.ParseBaseUnits(amount)
+	if err != nil {
+		return "", fmt.Errorf("invalid amount: %w", err)
 	}
-	// Convert to wei (assuming 18 decimals)
-	decimals := big.NewInt(1e18)
-	amountInWei := new(big.Int).Mul(amountBig, decimals)
 
 	b.logger.Info("Transfer details",
 		zap.String("to_address", to.Hex()),
@@ -695,6 +693,11 @@ func (b *BlockchainTokenIssuer) WaitForConfirmation(ctx context.Context, txHash
 	return receipt, nil
 }
 
+// Client returns the underlying RPC client, shared for read-only contract calls
+func (b *BlockchainTokenIssuer) Client() *ethclient.Client {
+	return b.client
+}
+
 // Close closes client connection
 func (b *BlockchainTokenIssuer) Close() {
 	if b.client != nil {
@@ -61,7 +61,7 @@ type X402Client interface {
 	// DailyCheckin performs daily check-in via x402
 	// POST /api/business/daily-checkin
 	// Returns 200 if already checked in, 402 with payment challenge otherwise
-	DailyCheckin(ctx context.Context, merchantUserID string) (statusCode int, responseBody []byte, err error)
+	DailyCheckin(ctx context.Context, merchantUserID, checkinDate string) (statusCode int, responseBody []byte, err error)
 
 	// CreatePaymentChallenge creates a payment challenge
 	// POST /v2/api/x402/payment
@@ -367,13 +367,10 @@ func (c *X402ClientImpl) GetNetworks(ctx context.Context) ([]byte, error) {
 // Request body:
 //   - merchant_id: merchant identifier
 //   - merchant_user_id: end user identifier
-//   - checkin_date: check-in date in YYYY-MM-DD format
-func (c *X402ClientImpl) DailyCheckin(ctx context.Context, merchantUserID string) (statusCode int, responseBody []byte, err error) {
+//   - checkin_date: check-in date in YYYY-MM-DD format, computed by the caller in the configured checkin timezone
+func (c *X402ClientImpl) DailyCheckin(ctx context.Context, merchantUserID, checkinDate string) (statusCode int, responseBody []byte, err error) {
 	url := fmt.Sprintf("%s/api/business/daily-checkin", c.baseURL)
 
-	// Get current date in YYYY-MM-DD format
-	checkinDate := time.Now().Format("2006-01-02")
-
 	payload := map[string]interface{}{
 		"merchant_id":      c.merchantID,
 		"merchant_user_id": merchantUserID,
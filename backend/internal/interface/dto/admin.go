@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/dedata/dedata-backend/internal/domain/entity"
+
+// UpdateUserStatusRequest 修改用户状态请求
+type UpdateUserStatusRequest struct {
+	Status string `json:"status" binding:"required"` // ACTIVE / SUSPENDED / BLACKLISTED
+	Reason string `json:"reason,omitempty"`          // 操作原因，记录在审计日志中
+}
+
+// UpdateUserRoleRequest 修改用户角色请求
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"` // USER / ADMIN
+}
+
+// AdminUserListResponse 管理后台用户列表响应
+type AdminUserListResponse struct {
+	Data       []*entity.User `json:"data"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// StreakRuleRequest 连续签到奖励规则，奖励为 reward_amount * multiplier + bonus
+type StreakRuleRequest struct {
+	MinDays    int    `json:"minDays" binding:"required,min=1"` // 连续签到天数下限
+	Multiplier string `json:"multiplier" binding:"max=78"`      // 十进制字符串，默认 "1"
+	Bonus      string `json:"bonus" binding:"max=78"`           // 十进制字符串，默认 "0"
+}
+
+// ReplaceStreakRulesRequest 替换全部连续签到奖励规则，空数组表示清空 (按 reward_amount 发放)
+type ReplaceStreakRulesRequest struct {
+	Rules []StreakRuleRequest `json:"rules" binding:"dive"`
+}
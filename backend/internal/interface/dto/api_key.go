@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/dedata/dedata-backend/internal/domain/entity"
+
+// CreateAPIKeyRequest 创建 API key 请求 (管理后台)
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"` // 例如 ["leaderboard:read", "checkins:read"]
+	Platform      string   `json:"platform"`                        // 合作平台 ID，authorizations:read 需要
+	RateLimit     int      `json:"rateLimit" binding:"min=0"`       // 每分钟请求数，0 使用默认值
+	ExpiresInDays int      `json:"expiresInDays" binding:"min=0"`   // 0 表示使用配置的上限 (未配置上限时永不过期)
+}
+
+// CreateAPIKeyResponse 创建 API key 响应，完整的 key 只返回一次
+type CreateAPIKeyResponse struct {
+	*entity.APIKey
+	Key string `json:"key"`
+}
+
+// PendingPayoutsResponse 待发放的签到奖励
+type PendingPayoutsResponse struct {
+	Queued  []*entity.CheckIn `json:"queued"`  // 支付成功，等待发放
+	Issuing []*entity.CheckIn `json:"issuing"` // 已提交链上交易，等待确认
+}
@@ -1,34 +1,88 @@
 package dto
 
-// NonceRequest 请求 nonce (简化版)
+import "github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+// 登录签名类型
+const (
+	SignatureTypePersonalSign = "personal_sign" // EIP-4361 消息 (默认)
+	SignatureTypeEIP712       = "eip712"        // EIP-712 typed data (eth_signTypedData_v4)
+)
+
+// NonceRequest 请求 nonce
 type NonceRequest struct {
-	Address string `json:"walletAddress" binding:"required"`
+	Address   string `json:"walletAddress" binding:"required"`
+	Namespace string `json:"chainNamespace,omitempty"` // 可选，eip155 | solana，默认按地址格式推断
+	ChainID   int64  `json:"chainId,omitempty"`        // 可选，仅 eip155，默认使用配置的 chain_id
 }
 
-// NonceResponse nonce 响应 (简化版，前端自己构造消息)
+// NonceResponse nonce 响应
+// message 为服务端构造的 EIP-4361 / CAIP-122 消息 (personal_sign / Solana signMessage)，
+// typedData 为等价的 EIP-712 结构 (仅 EVM 钱包)，前端任选其一原样签名
 type NonceResponse struct {
-	Nonce string `json:"nonce"`
+	Nonce     string              `json:"nonce"`
+	Message   string              `json:"message"`
+	TypedData *apitypes.TypedData `json:"typedData,omitempty"`
+	PoW       *PoWChallenge       `json:"pow,omitempty"` // 启用工作量证明时返回，verify 时需提交 solution
+	IssuedAt  string              `json:"issuedAt"`
+	ExpiresAt string              `json:"expiresAt"`
+}
+
+// PoWChallenge hashcash 工作量证明：寻找 solution 使 SHA-256(seed + ":" + solution) 的前 difficulty 位为 0
+type PoWChallenge struct {
+	Algorithm  string `json:"algorithm"`  // sha256
+	Seed       string `json:"seed"`       // 即登录 nonce
+	Difficulty int    `json:"difficulty"` // 要求的前导 0 位数
 }
 
 // VerifyRequest 验证签名请求
 type VerifyRequest struct {
-	Address   string `json:"walletAddress" binding:"required"`
-	Nonce     string `json:"nonce" binding:"required"`
-	Signature string `json:"signature" binding:"required"`
-	Message   string `json:"message,omitempty"` // 可选，用于验证
+	Address       string `json:"walletAddress" binding:"required"`
+	Nonce         string `json:"nonce,omitempty"`              // eip712 时必填；personal_sign 时可选，传入时必须与 message 中的 nonce 一致
+	Signature     string `json:"signature" binding:"required"` // EVM 为 0x hex，Solana 为 base58 或 base64
+	Message       string `json:"message,omitempty"`            // personal_sign 时必填，/auth/nonce 返回的登录消息
+	SignatureType string `json:"signatureType,omitempty"`      // personal_sign (默认) | eip712
+	PoWSolution   string `json:"powSolution,omitempty"`        // nonce 返回 pow 时必填
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
 }
 
 // AuthResponse 认证响应
 type AuthResponse struct {
-	Token string    `json:"token"`
-	User  *UserInfo `json:"user"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    string    `json:"expiresAt"` // access token 过期时间
+	Scope        string    `json:"scope"`     // 会话权限范围: full | read (passkey 登录)
+	User         *UserInfo `json:"user"`
 }
 
 // UserInfo 用户信息
 type UserInfo struct {
 	ID               string `json:"id"`
+	Account          string `json:"account"` // 主钱包的 CAIP-10 标识
 	Address          string `json:"walletAddress"`
+	Namespace        string `json:"chainNamespace"`
 	DID              string `json:"did"`
 	Role             string `json:"role"`
 	ProfileCompleted bool   `json:"profileCompleted"`
 }
+
+// ClientInfo 发起请求的客户端信息，用于记录登录会话
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionInfo 登录会话信息
+type SessionInfo struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"userAgent"`
+	IPAddress  string `json:"ipAddress"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	Scope      string `json:"scope"`   // full | read (passkey 登录)
+	Current    bool   `json:"current"` // 是否为当前请求所在的会话
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// PlatformInfo 可授权的合作平台
+type PlatformInfo struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"` // 平台可以申请的 scope
+}
+
+// GrantChallengeRequest 获取授权同意挑战请求
+type GrantChallengeRequest struct {
+	Platform      string `json:"platform" binding:"required"`
+	Scope         string `json:"scope" binding:"required"` // 空格分隔，例如 "profile:read checkins:read"
+	ExpiresInDays int    `json:"expiresInDays"`            // 可选，授权有效期（天），默认使用配置
+}
+
+// GrantChallengeResponse 授权同意挑战响应
+type GrantChallengeResponse struct {
+	Nonce          string `json:"nonce"`
+	Message        string `json:"message"` // 主钱包待签名的同意消息
+	IssuedAt       string `json:"issuedAt"`
+	ExpiresAt      string `json:"expiresAt"`
+	GrantExpiresAt string `json:"grantExpiresAt"` // 授权生效后的过期时间
+}
+
+// GrantRequest 提交主钱包对同意消息的签名
+type GrantRequest struct {
+	Nonce     string `json:"nonce" binding:"required"`
+	Signature string `json:"signature" binding:"required"` // EVM 为 personal_sign 签名，Solana 为 signMessage 签名
+}
+
+// AuthorizationStatusResponse 平台查询授权状态响应
+type AuthorizationStatusResponse struct {
+	Active    bool       `json:"active"`
+	DID       string     `json:"did"`
+	Platform  string     `json:"platform"`
+	Scope     string     `json:"scope,omitempty"` // 已授权的全部 scope
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// IssueCredentialRequest 申请签发 credential
+type IssueCredentialRequest struct {
+	Type string `json:"type" binding:"required"` // CheckInCountCredential | CheckInStreakCredential | RewardsCredential
+}
+
+// VerifyCredentialRequest 验证 credential
+type VerifyCredentialRequest struct {
+	Credential string `json:"credential" binding:"required"` // VC-JWT
+}
+
+// VerifyCredentialResponse credential 验证结果
+// valid 为 true 表示签名、有效期和吊销状态均通过
+type VerifyCredentialResponse struct {
+	Valid             bool                   `json:"valid"`
+	Revoked           bool                   `json:"revoked"`
+	Error             string                 `json:"error,omitempty"`
+	ID                string                 `json:"id,omitempty"`
+	Issuer            string                 `json:"issuer,omitempty"`
+	Subject           string                 `json:"subject,omitempty"`
+	Type              []string               `json:"type,omitempty"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject,omitempty"`
+	IssuedAt          string                 `json:"issuedAt,omitempty"`
+	ExpiresAt         string                 `json:"expiresAt,omitempty"`
+}
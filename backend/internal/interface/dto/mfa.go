@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// TOTPEnrollResponse 发起 TOTP 绑定响应，密钥只在此时返回
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`     // base32 密钥，无法扫码时手动输入
+	OTPAuthURI string `json:"otpauthUri"` // otpauth://totp/... 用于生成二维码
+}
+
+// TOTPCodeRequest 提交 TOTP 验证码
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// StepUpRequest 二次验证请求，验证码与恢复码二选一
+type StepUpRequest struct {
+	Code         string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recoveryCode" binding:"omitempty,max=32"`
+}
+
+// RecoveryCodesResponse 恢复码只在生成时返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// StepUpResponse 二次验证通过
+type StepUpResponse struct {
+	ExpiresAt time.Time `json:"expiresAt"` // 当前会话可执行敏感操作的截止时间
+}
+
+// MFAStatusResponse 当前管理员的二次验证状态
+type MFAStatusResponse struct {
+	Enrolled               bool       `json:"enrolled"`                  // 已确认绑定
+	Pending                bool       `json:"pending"`                   // 已发起但未确认
+	ConfirmedAt            *time.Time `json:"confirmedAt,omitempty"`     // 确认时间
+	RecoveryCodesRemaining int64      `json:"recoveryCodesRemaining"`    // 剩余恢复码数量
+	StepUpExpiresAt        *time.Time `json:"stepUpExpiresAt,omitempty"` // 当前会话二次验证的失效时间
+	Enforced               bool       `json:"enforced"`                  // 是否必须绑定后才能执行敏感操作
+}
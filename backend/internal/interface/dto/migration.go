@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// MigrationChallengeRequest 获取迁移挑战请求 (旧钱包仍可用，已登录)
+type MigrationChallengeRequest struct {
+	Address string `json:"walletAddress" binding:"required"` // 迁移到的新钱包
+	ChainID int64  `json:"chainId"`                          // 可选，默认使用配置的 chain_id
+}
+
+// RecoveryChallengeRequest 获取恢复挑战请求 (旧钱包已丢失，无需登录)
+type RecoveryChallengeRequest struct {
+	DID     string `json:"did" binding:"required"`           // 待恢复账户的 DID
+	Address string `json:"walletAddress" binding:"required"` // 迁移到的新钱包
+	ChainID int64  `json:"chainId"`                          // 可选，默认使用配置的 chain_id
+}
+
+// MigrationChallengeResponse 迁移挑战响应
+type MigrationChallengeResponse struct {
+	Nonce         string              `json:"nonce"`
+	Mode          string              `json:"mode"`        // HANDOVER | RECOVERY
+	FromAddress   string              `json:"fromAddress"` // 当前主钱包
+	FromChainID   int64               `json:"fromChainId"` // 当前主钱包所在链
+	ToAddress     string              `json:"toAddress"`
+	ChainID       int64               `json:"chainId"`
+	Message       string              `json:"message"`                 // personal_sign 待签名消息，新旧钱包签名同一消息
+	TypedData     *apitypes.TypedData `json:"typedData"`               // 新钱包 eth_signTypedData_v4 待签名数据
+	FromTypedData *apitypes.TypedData `json:"fromTypedData,omitempty"` // HANDOVER 模式下旧钱包待签名数据，domain 为旧钱包所在链
+	IssuedAt      string              `json:"issuedAt"`
+	ExpiresAt     string              `json:"expiresAt"`
+	UnlocksAt     string              `json:"unlocksAt,omitempty"` // RECOVERY 模式下时间锁的预计到期时间
+}
+
+// MigrateWalletRequest 提交新旧钱包签名完成迁移
+type MigrateWalletRequest struct {
+	Nonce         string `json:"nonce" binding:"required"`
+	OldSignature  string `json:"oldSignature" binding:"required"` // 当前主钱包的签名
+	NewSignature  string `json:"newSignature" binding:"required"` // 新钱包的签名
+	SignatureType string `json:"signatureType,omitempty"`         // personal_sign (默认) 或 eip712
+}
+
+// RecoverWalletRequest 提交新钱包签名发起恢复
+type RecoverWalletRequest struct {
+	Nonce         string `json:"nonce" binding:"required"`
+	Signature     string `json:"signature" binding:"required"` // 新钱包的签名
+	SignatureType string `json:"signatureType,omitempty"`      // personal_sign (默认) 或 eip712
+}
+
+// RejectMigrationRequest 管理员拒绝迁移请求
+type RejectMigrationRequest struct {
+	Reason string `json:"reason" binding:"required"` // 拒绝原因，记录在迁移记录中
+}
+
+// AdminMigrationListResponse 管理后台迁移列表响应
+type AdminMigrationListResponse struct {
+	Data       []*entity.WalletMigration `json:"data"`
+	Pagination Pagination                `json:"pagination"`
+}
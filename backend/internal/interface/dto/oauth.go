@@ -0,0 +1,110 @@
+package dto
+
+import "time"
+
+// RegisterOAuthClientRequest 注册 OAuth 客户端请求 (管理后台)
+type RegisterOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	RedirectURIs []string `json:"redirectUris" binding:"required,min=1"` // https 地址，本地开发可使用 http://localhost
+	Scopes       []string `json:"scopes" binding:"required,min=1"`       // 允许申请的 scope，配置了非对称 JWT 密钥时可包含 openid
+	Public       bool     `json:"public"`                                // 公共客户端 (SPA / 移动端) 不签发 client_secret
+}
+
+// OAuthClientResponse OAuth 客户端信息
+type OAuthClientResponse struct {
+	ClientID     string    `json:"clientId"`
+	ClientSecret string    `json:"clientSecret,omitempty"` // 仅在注册时返回一次
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirectUris"`
+	Scopes       []string  `json:"scopes"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// AuthorizeRequest 授权请求参数，与 OAuth2 authorization endpoint 的参数一致
+// 前端同意页从回调 URL 中原样读取后转发给 API
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope" json:"scope" binding:"required"`
+	State               string `form:"state" json:"state"`
+	Nonce               string `form:"nonce" json:"nonce"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// AuthorizeDecisionRequest 用户在同意页的决定
+type AuthorizeDecisionRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// OAuthConsentResponse 同意页展示的信息
+type OAuthConsentResponse struct {
+	ClientID        string   `json:"clientId"`
+	ClientName      string   `json:"clientName"`
+	Scopes          []string `json:"scopes"`
+	ConsentRequired bool     `json:"consentRequired"` // 已有覆盖全部 scope 的有效授权时为 false，前端可直接提交
+}
+
+// AuthorizeResponse 授权结果，前端跳转到 redirectUri (携带 code 或 error)
+type AuthorizeResponse struct {
+	RedirectURI string `json:"redirectUri"`
+}
+
+// TokenRequest token endpoint 参数 (application/x-www-form-urlencoded)
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// TokenResponse token endpoint 响应 (RFC 6749 格式，不使用统一响应包装)
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+	IDToken     string `json:"id_token,omitempty"` // 请求了 openid 时返回
+}
+
+// UserInfoResponse OpenID Connect userinfo，字段按授权的 scope 返回
+type UserInfoResponse struct {
+	Sub         string        `json:"sub"` // 用户 DID
+	DID         string        `json:"did"`
+	Name        *string       `json:"name,omitempty"`        // profile:read
+	Picture     *string       `json:"picture,omitempty"`     // profile:read
+	Bio         *string       `json:"bio,omitempty"`         // profile:read
+	CheckIns    *CheckInStats `json:"checkins,omitempty"`    // checkins:read
+	Wallets     []string      `json:"wallets,omitempty"`     // wallets:read，CAIP-10 账户
+	Credentials []string      `json:"credentials,omitempty"` // credentials:read，未吊销的 VC-JWT
+}
+
+// CheckInStats 签到统计
+type CheckInStats struct {
+	SuccessfulCheckIns int64      `json:"successfulCheckIns"`
+	StreakDays         int        `json:"streakDays"`
+	TotalRewards       string     `json:"totalRewards"`
+	LastCheckinAt      *time.Time `json:"lastCheckinAt,omitempty"`
+}
+
+// OpenIDConfiguration OpenID Connect Discovery 元数据
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+// 以下结构对应 WebAuthn 的 PublicKeyCredentialCreationOptions / PublicKeyCredentialRequestOptions，
+// 二进制字段 (challenge、user.id、credential id) 为 base64url，前端需解码为 ArrayBuffer 后传给 navigator.credentials
+
+// PasskeyRelyingParty 依赖方信息
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PasskeyUser 注册 passkey 的用户，id 为用户 ID 的 base64url (登录时作为 userHandle 返回)
+type PasskeyUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// PasskeyCredentialParam 可接受的凭证算法
+type PasskeyCredentialParam struct {
+	Type string `json:"type"` // public-key
+	Alg  int64  `json:"alg"`  // COSE 算法
+}
+
+// PasskeyCredentialDescriptor 已注册凭证
+type PasskeyCredentialDescriptor struct {
+	Type       string   `json:"type"` // public-key
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// PasskeyAuthenticatorSelection 要求可发现凭证 (resident key) 与用户验证
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PasskeyCreationOptions 注册 passkey 的参数 (navigator.credentials.create 的 publicKey)
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RP                     PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"` // 毫秒
+	Attestation            string                        `json:"attestation"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+}
+
+// PasskeyRequestOptions 登录参数 (navigator.credentials.get 的 publicKey)，allowCredentials 为空表示使用可发现凭证
+type PasskeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	RPID             string                        `json:"rpId"`
+	Timeout          int64                         `json:"timeout"` // 毫秒
+	UserVerification string                        `json:"userVerification"`
+	AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+}
+
+// PasskeyAttestationResponse navigator.credentials.create 返回的 response (base64url)
+type PasskeyAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject" binding:"required"`
+	Transports        []string `json:"transports,omitempty"` // getTransports() 的结果
+}
+
+// PasskeyRegisterRequest 注册 passkey 请求
+type PasskeyRegisterRequest struct {
+	Name     string                     `json:"name" binding:"max=100"` // 可选，便于用户区分设备
+	ID       string                     `json:"id" binding:"required"`  // 凭证 ID (base64url)
+	Response PasskeyAttestationResponse `json:"response" binding:"required"`
+}
+
+// PasskeyAssertionResponse navigator.credentials.get 返回的 response (base64url)
+type PasskeyAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+	AuthenticatorData string `json:"authenticatorData" binding:"required"`
+	Signature         string `json:"signature" binding:"required"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// PasskeyLoginRequest passkey 登录请求
+type PasskeyLoginRequest struct {
+	ID       string                   `json:"id" binding:"required"` // 凭证 ID (base64url)
+	Response PasskeyAssertionResponse `json:"response" binding:"required"`
+}
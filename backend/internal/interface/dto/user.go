@@ -27,11 +27,14 @@ type LeaderboardUser struct {
 	Rank             int     `json:"rank"`
 	ID               string  `json:"id"`
 	DID              string  `json:"did"`
+	Account          string  `json:"account"` // 主钱包的 CAIP-10 标识
 	WalletAddress    string  `json:"walletAddress"`
 	ChainID          int     `json:"chainId"`
 	ProfileCompleted bool    `json:"profileCompleted"`
 	TotalRewards     string  `json:"totalRewards"`
 	TotalCheckins    int64   `json:"totalCheckins"`
+	CurrentStreak    int     `json:"currentStreak"` // 当前连续签到天数
+	LongestStreak    int     `json:"longestStreak"` // 最长连续签到天数
 	DisplayName      *string `json:"displayName,omitempty"`
 	Avatar           *string `json:"avatar,omitempty"`
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+// LinkWalletChallengeRequest 获取钱包绑定挑战请求
+type LinkWalletChallengeRequest struct {
+	Address   string `json:"walletAddress" binding:"required"` // 待绑定的新钱包
+	ChainID   int64  `json:"chainId"`                          // 可选，EVM 钱包默认使用配置的 chain_id
+	Namespace string `json:"chainNamespace,omitempty"`         // 可选，eip155 | solana，默认按地址格式推断
+}
+
+// LinkWalletChallengeResponse 钱包绑定挑战响应
+type LinkWalletChallengeResponse struct {
+	Nonce     string              `json:"nonce"`
+	Message   string              `json:"message"`             // personal_sign 待签名消息
+	TypedData *apitypes.TypedData `json:"typedData,omitempty"` // eth_signTypedData_v4 待签名数据，仅 EVM 钱包
+	IssuedAt  string              `json:"issuedAt"`
+	ExpiresAt string              `json:"expiresAt"`
+}
+
+// LinkWalletRequest 提交新钱包签名完成绑定
+type LinkWalletRequest struct {
+	Nonce         string `json:"nonce" binding:"required"`
+	Signature     string `json:"signature" binding:"required"` // 新钱包的签名
+	SignatureType string `json:"signatureType,omitempty"`      // personal_sign (默认) 或 eip712
+}
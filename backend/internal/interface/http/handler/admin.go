@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 管理后台处理器
+type AdminHandler struct {
+	adminUC *usecase.AdminUseCase
+}
+
+// NewAdminHandler 创建管理后台处理器
+func NewAdminHandler(adminUC *usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		adminUC: adminUC,
+	}
+}
+
+// ListUsers 搜索用户
+// GET /api/admin/users?q=0x12&status=ACTIVE&role=USER&page=1&limit=20
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := repository.UserFilter{
+		Query:  c.Query("q"),
+		Status: entity.UserStatus(c.Query("status")),
+		Role:   entity.UserRole(c.Query("role")),
+	}
+
+	users, err := h.adminUC.ListUsers(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, users)
+}
+
+// GetUser 获取用户详情
+// GET /api/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.adminUC.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, user)
+}
+
+// UpdateUserStatus 修改用户状态
+// PUT /api/admin/users/:id/status
+func (h *AdminHandler) UpdateUserStatus(c *gin.Context) {
+	var req dto.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.adminUC.UpdateUserStatus(c.Request.Context(), c.GetString("userID"), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, user)
+}
+
+// UpdateUserRole 修改用户角色
+// PUT /api/admin/users/:id/role
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.adminUC.UpdateUserRole(c.Request.Context(), c.GetString("userID"), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, user)
+}
+
+// GetUserCheckIns 查看用户签到记录
+// GET /api/admin/users/:id/checkins?page=1&pageSize=10
+func (h *AdminHandler) GetUserCheckIns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	checkins, total, err := h.adminUC.GetUserCheckIns(c.Request.Context(), c.Param("id"), page, pageSize)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":     checkins,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// ListPendingPayouts 查看待发放的签到奖励
+// GET /api/admin/payouts
+func (h *AdminHandler) ListPendingPayouts(c *gin.Context) {
+	payouts, err := h.adminUC.ListPendingPayouts(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, payouts)
+}
+
+// ListStreakRules 查看连续签到奖励规则
+// GET /api/admin/checkin/streak-rules
+func (h *AdminHandler) ListStreakRules(c *gin.Context) {
+	rules, err := h.adminUC.ListStreakRules(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// ReplaceStreakRules 替换全部连续签到奖励规则
+// PUT /api/admin/checkin/streak-rules
+func (h *AdminHandler) ReplaceStreakRules(c *gin.Context) {
+	var req dto.ReplaceStreakRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	rules, err := h.adminUC.ReplaceStreakRules(c.Request.Context(), c.GetString("userID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *AdminHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrInvalidStatus),
+		errors.Is(err, usecase.ErrInvalidRole),
+		errors.Is(err, usecase.ErrCannotModifySelf),
+		errors.Is(err, usecase.ErrInvalidStreakRule):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
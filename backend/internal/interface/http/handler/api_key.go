@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler API key 管理处理器
+type APIKeyHandler struct {
+	apiKeyUC *usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler 创建 API key 处理器
+func NewAPIKeyHandler(apiKeyUC *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUC: apiKeyUC,
+	}
+}
+
+// List 获取所有 API key
+// GET /api/admin/api-keys
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.apiKeyUC.List(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, keys)
+}
+
+// Create 创建 API key，完整的 key 只返回一次
+// POST /api/admin/api-keys
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.apiKeyUC.Create(c.Request.Context(), c.GetString("userID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Revoke 吊销 API key
+// DELETE /api/admin/api-keys/:id
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	if err := h.apiKeyUC.Revoke(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrAPIKeyNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrScopeNotAllowed), errors.Is(err, usecase.ErrPlatformRequired):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
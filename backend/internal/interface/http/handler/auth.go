@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/domain/repository"
 	"github.com/dedata/dedata-backend/internal/interface/dto"
 	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
 	"github.com/dedata/dedata-backend/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -32,8 +37,16 @@ func (h *AuthHandler) GetNonce(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authUseCase.GenerateNonce(c.Request.Context(), &req)
+	resp, err := h.authUseCase.GenerateNonce(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, chain.ErrInvalidAddress) || errors.Is(err, chain.ErrUnsupportedNamespace) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrTooManyChallenges) || errors.Is(err, usecase.ErrTooManyAttempts) {
+			response.TooManyRequests(c, err.Error())
+			return
+		}
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -56,8 +69,20 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authUseCase.VerifySignature(c.Request.Context(), &req)
+	resp, err := h.authUseCase.VerifySignature(c.Request.Context(), &req, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, usecase.ErrAccountDisabled) {
+			response.Forbidden(c, err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrTooManyAttempts) {
+			response.TooManyRequests(c, err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrAccountOnOtherChain) {
+			response.Conflict(c, err.Error())
+			return
+		}
 		response.BadRequest(c, err.Error())
 		return
 	}
@@ -65,11 +90,99 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// Refresh handles access token refresh
+// @Summary Rotate refresh token and issue a new access token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RefreshRequest true "Refresh Request"
+// @Success 200 {object} dto.AuthResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.authUseCase.Refresh(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, usecase.ErrAccountDisabled) {
+			response.Forbidden(c, err.Error())
+			return
+		}
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
 // Logout handles user logout
+// 吊销当前会话的 refresh token family，并将当前 access token 加入黑名单
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// JWT 是无状态的,logout 主要由前端清除 token
-	// 如果需要黑名单机制,可以在这里添加
+	claims, exists := c.Get("claims")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	if err := h.authUseCase.Logout(c.Request.Context(), claims.(*pkgJWT.Claims)); err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// ListSessions 获取我的登录会话
+// GET /api/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	sessions, err := h.authUseCase.ListSessions(c.Request.Context(), userID.(string), c.GetString("sessionID"))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 吊销指定登录会话
+// DELETE /api/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	err := h.authUseCase.RevokeUserSession(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
 	response.Success(c, gin.H{
 		"success": true,
 	})
 }
+
+// clientInfo 提取请求的客户端信息
+func clientInfo(c *gin.Context) *dto.ClientInfo {
+	return &dto.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
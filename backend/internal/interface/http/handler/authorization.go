@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorizationHandler 平台授权处理器
+type AuthorizationHandler struct {
+	authorizationUC *usecase.AuthorizationUseCase
+}
+
+// NewAuthorizationHandler 创建平台授权处理器
+func NewAuthorizationHandler(authorizationUC *usecase.AuthorizationUseCase) *AuthorizationHandler {
+	return &AuthorizationHandler{
+		authorizationUC: authorizationUC,
+	}
+}
+
+// ListPlatforms 获取可授权的平台
+// GET /api/platforms
+func (h *AuthorizationHandler) ListPlatforms(c *gin.Context) {
+	response.Success(c, h.authorizationUC.ListPlatforms())
+}
+
+// ListAuthorizations 获取我的授权
+// GET /api/authorizations
+func (h *AuthorizationHandler) ListAuthorizations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	authorizations, err := h.authorizationUC.ListAuthorizations(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, authorizations)
+}
+
+// CreateGrantChallenge 获取授权同意消息
+// POST /api/authorizations/challenge
+func (h *AuthorizationHandler) CreateGrantChallenge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.GrantChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.authorizationUC.CreateGrantChallenge(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Grant 提交主钱包签名完成授权
+// POST /api/authorizations
+func (h *AuthorizationHandler) Grant(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.GrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	authorization, err := h.authorizationUC.Grant(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, authorization)
+}
+
+// Revoke 吊销授权
+// DELETE /api/authorizations/:id
+func (h *AuthorizationHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	if err := h.authorizationUC.Revoke(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// CheckAuthorization 平台查询用户授权状态
+// GET /api/platform/authorizations?did=...&scope=...
+func (h *AuthorizationHandler) CheckAuthorization(c *gin.Context) {
+	platformID := c.GetString("platformID")
+	if platformID == "" {
+		response.Forbidden(c, "API key is not bound to a platform")
+		return
+	}
+
+	did := c.Query("did")
+	if did == "" {
+		response.BadRequest(c, "did is required")
+		return
+	}
+
+	resp, err := h.authorizationUC.Check(c.Request.Context(), platformID, did, strings.Fields(c.Query("scope")))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *AuthorizationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrAuthorizationNotFound), errors.Is(err, usecase.ErrPlatformNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -35,7 +36,7 @@ func (h *CheckInHandler) CheckIn(c *gin.Context) {
 	// 调用 UseCase
 	checkin, challenge, err := h.checkinUC.CheckIn(c.Request.Context(), userID.(string))
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, err.Error())
+		h.handleError(c, err)
 		return
 	}
 
@@ -80,7 +81,7 @@ func (h *CheckInHandler) VerifyCheckIn(c *gin.Context) {
 	// 调用 UseCase
 	success, message, err := h.checkinUC.VerifyCheckin(c.Request.Context(), req.OrderID, userID.(string))
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, err.Error())
+		h.handleError(c, err)
 		return
 	}
 
@@ -145,3 +146,15 @@ func (h *CheckInHandler) GetCheckInSummary(c *gin.Context) {
 
 	response.Success(c, summary)
 }
+
+// handleError 今日名额用完返回 409，data 中带 dailyLimitReached 与下次可签到时间；其他错误保持原有的业务错误响应
+func (h *CheckInHandler) handleError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrDailyLimitReached) {
+		response.ConflictWithData(c, err.Error(), gin.H{
+			"dailyLimitReached": true,
+			"nextCheckinAt":     h.checkinUC.NextCheckInAt(),
+		})
+		return
+	}
+	response.Error(c, http.StatusBadRequest, err.Error())
+}
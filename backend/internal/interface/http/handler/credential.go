@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// contentTypeJWT VC-JWT 的媒体类型
+const contentTypeJWT = "application/jwt"
+
+// CredentialHandler Verifiable Credential 处理器
+type CredentialHandler struct {
+	credentialUC *usecase.CredentialUseCase
+}
+
+// NewCredentialHandler 创建 credential 处理器
+func NewCredentialHandler(credentialUC *usecase.CredentialUseCase) *CredentialHandler {
+	return &CredentialHandler{
+		credentialUC: credentialUC,
+	}
+}
+
+// ListCredentials 获取我的 credential
+// GET /api/credentials
+func (h *CredentialHandler) ListCredentials(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	credentials, err := h.credentialUC.ListCredentials(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, credentials)
+}
+
+// Issue 为当前用户签发 credential
+// POST /api/credentials
+func (h *CredentialHandler) Issue(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.IssueCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	credential, err := h.credentialUC.Issue(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, credential)
+}
+
+// Revoke 吊销自己的 credential
+// DELETE /api/credentials/:id
+func (h *CredentialHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	if err := h.credentialUC.Revoke(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// Verify 验证 VC-JWT (公开)
+// POST /api/credentials/verify
+func (h *CredentialHandler) Verify(c *gin.Context) {
+	var req dto.VerifyCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.credentialUC.Verify(c.Request.Context(), req.Credential)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// StatusList 返回签名的 StatusList2021Credential (公开)
+// GET /api/credentials/status/:list
+func (h *CredentialHandler) StatusList(c *gin.Context) {
+	list, err := strconv.ParseInt(c.Param("list"), 10, 64)
+	if err != nil || list < 0 {
+		response.BadRequest(c, "invalid status list")
+		return
+	}
+
+	token, err := h.credentialUC.StatusList(c.Request.Context(), list)
+	if err != nil {
+		if errors.Is(err, usecase.ErrStatusListNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, contentTypeJWT, []byte(token))
+}
+
+// AdminRevoke 管理员吊销 credential
+// POST /api/admin/credentials/:id/revoke
+func (h *CredentialHandler) AdminRevoke(c *gin.Context) {
+	if err := h.credentialUC.AdminRevoke(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *CredentialHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrCredentialNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrInvalidCredentialType),
+		errors.Is(err, usecase.ErrNothingToAttest):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/did"
+	"github.com/gin-gonic/gin"
+)
+
+// DIDHandler DID 解析处理器
+// 按 DID Resolution HTTP(S) 绑定原样返回，不使用统一响应包装，便于标准 DID 解析器直接消费
+type DIDHandler struct {
+	didUC *usecase.DIDUseCase
+}
+
+// NewDIDHandler 创建 DID 处理器
+func NewDIDHandler(didUC *usecase.DIDUseCase) *DIDHandler {
+	return &DIDHandler{
+		didUC: didUC,
+	}
+}
+
+// Resolve 解析 did:dedata
+// GET /api/did/:did
+// Accept 为 application/did+ld+json 时只返回 DID Document，否则返回完整的解析结果
+func (h *DIDHandler) Resolve(c *gin.Context) {
+	result, err := h.didUC.Resolve(c.Request.Context(), c.Param("did"))
+	if err != nil {
+		code := did.ErrorCode(err)
+		if code == "" {
+			h.render(c, http.StatusInternalServerError, did.MediaTypeResolution, did.NewResolutionError("internalError"))
+			return
+		}
+		h.render(c, errorStatus(code), did.MediaTypeResolution, did.NewResolutionError(code))
+		return
+	}
+
+	status := http.StatusOK
+	if result.DIDDocumentMetadata.Deactivated {
+		status = http.StatusGone
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	if wantsDocument(c) {
+		h.render(c, status, did.MediaTypeDIDLDJSON, result.DIDDocument)
+		return
+	}
+	h.render(c, status, did.MediaTypeResolution, result)
+}
+
+// PlatformDocument 平台 did:web 文档
+// GET /.well-known/did.json
+func (h *DIDHandler) PlatformDocument(c *gin.Context) {
+	doc, err := h.didUC.PlatformDocument()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	h.render(c, http.StatusOK, did.MediaTypeDIDLDJSON, doc)
+}
+
+func (h *DIDHandler) render(c *gin.Context, status int, contentType string, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(status, contentType, data)
+}
+
+// wantsDocument 客户端是否只需要 DID Document
+func wantsDocument(c *gin.Context) bool {
+	accept := c.GetHeader("Accept")
+	return strings.Contains(accept, "application/did+ld+json") || strings.Contains(accept, "application/did+json")
+}
+
+// errorStatus 将解析错误码映射为 HTTP 状态码
+func errorStatus(code string) int {
+	switch code {
+	case did.ErrorInvalidDID:
+		return http.StatusBadRequest
+	case did.ErrorNotFound:
+		return http.StatusNotFound
+	case did.ErrorMethodNotSupported:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler 管理员 TOTP 二次验证处理器
+type MFAHandler struct {
+	mfaUC *usecase.MFAUseCase
+}
+
+// NewMFAHandler 创建二次验证处理器
+func NewMFAHandler(mfaUC *usecase.MFAUseCase) *MFAHandler {
+	return &MFAHandler{
+		mfaUC: mfaUC,
+	}
+}
+
+// Status 获取绑定状态与当前会话的二次验证状态
+// GET /api/admin/mfa
+func (h *MFAHandler) Status(c *gin.Context) {
+	resp, err := h.mfaUC.Status(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Enroll 发起 TOTP 绑定，返回密钥与 otpauth URI
+// POST /api/admin/mfa/totp
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	resp, err := h.mfaUC.Enroll(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Confirm 提交验证码确认绑定，返回恢复码
+// POST /api/admin/mfa/totp/confirm
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	var req dto.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.mfaUC.Confirm(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Disable 解绑自己的 TOTP
+// DELETE /api/admin/mfa/totp
+func (h *MFAHandler) Disable(c *gin.Context) {
+	req, ok := bindStepUpRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaUC.Disable(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// Verify 使用验证码或恢复码完成二次验证
+// POST /api/admin/mfa/verify
+func (h *MFAHandler) Verify(c *gin.Context) {
+	req, ok := bindStepUpRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.mfaUC.VerifyStepUp(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// POST /api/admin/mfa/recovery-codes
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.mfaUC.RegenerateRecoveryCodes(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Reset 重置另一名管理员的 TOTP (需要二次验证)
+// DELETE /api/admin/mfa/users/:id
+func (h *MFAHandler) Reset(c *gin.Context) {
+	if err := h.mfaUC.Reset(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// bindStepUpRequest 解析验证码或恢复码，两者都为空时返回 400
+func bindStepUpRequest(c *gin.Context) (*dto.StepUpRequest, bool) {
+	var req dto.StepUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return nil, false
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		response.BadRequest(c, "code or recoveryCode is required")
+		return nil, false
+	}
+	return &req, true
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *MFAHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidTOTPCode):
+		response.Unauthorized(c, err.Error())
+	case errors.Is(err, usecase.ErrTooManyAttempts):
+		response.TooManyRequests(c, err.Error())
+	case errors.Is(err, usecase.ErrTOTPNotEnrolled), errors.Is(err, usecase.ErrUserNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrTOTPAlreadyEnrolled):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, usecase.ErrStepUpRequired):
+		response.ForbiddenWithData(c, err.Error(), gin.H{"stepUpRequired": true})
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// MigrationHandler 账户迁移处理器
+type MigrationHandler struct {
+	migrationUC *usecase.MigrationUseCase
+}
+
+// NewMigrationHandler 创建账户迁移处理器
+func NewMigrationHandler(migrationUC *usecase.MigrationUseCase) *MigrationHandler {
+	return &MigrationHandler{
+		migrationUC: migrationUC,
+	}
+}
+
+// ListMigrations 获取我的迁移历史
+// GET /api/user/migrations
+func (h *MigrationHandler) ListMigrations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	migrations, err := h.migrationUC.ListMigrations(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, migrations)
+}
+
+// CreateMigrationChallenge 获取迁移到新钱包的挑战
+// POST /api/user/migrations/challenge
+func (h *MigrationHandler) CreateMigrationChallenge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.MigrationChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.migrationUC.CreateMigrationChallenge(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Migrate 提交新旧钱包签名完成迁移
+// POST /api/user/migrations
+func (h *MigrationHandler) Migrate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.MigrateWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	migration, err := h.migrationUC.Migrate(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, migration)
+}
+
+// CancelMigration 取消等待中的迁移
+// DELETE /api/user/migrations/:id
+func (h *MigrationHandler) CancelMigration(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	if err := h.migrationUC.CancelMigration(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// CreateRecoveryChallenge 旧钱包丢失时获取恢复挑战
+// POST /api/auth/recovery/challenge
+func (h *MigrationHandler) CreateRecoveryChallenge(c *gin.Context) {
+	var req dto.RecoveryChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.migrationUC.CreateRecoveryChallenge(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Recover 提交新钱包签名发起恢复，等待时间锁到期和管理员批准
+// POST /api/auth/recovery
+func (h *MigrationHandler) Recover(c *gin.Context) {
+	var req dto.RecoverWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	migration, err := h.migrationUC.Recover(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, migration)
+}
+
+// AdminListMigrations 按状态查询迁移
+// GET /api/admin/migrations?status=PENDING&page=1&limit=20
+func (h *MigrationHandler) AdminListMigrations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	migrations, err := h.migrationUC.ListMigrationsByStatus(c.Request.Context(), entity.MigrationStatus(c.Query("status")), page, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, migrations)
+}
+
+// AdminApproveMigration 批准时间锁已到期的迁移
+// POST /api/admin/migrations/:id/approve
+func (h *MigrationHandler) AdminApproveMigration(c *gin.Context) {
+	migration, err := h.migrationUC.ApproveMigration(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, migration)
+}
+
+// AdminRejectMigration 拒绝等待中的迁移
+// POST /api/admin/migrations/:id/reject
+func (h *MigrationHandler) AdminRejectMigration(c *gin.Context) {
+	var req dto.RejectMigrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	migration, err := h.migrationUC.RejectMigration(c.Request.Context(), c.GetString("userID"), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, migration)
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *MigrationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrMigrationNotFound),
+		errors.Is(err, usecase.ErrUserNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrMigrationPending),
+		errors.Is(err, usecase.ErrMigrationNotPending),
+		errors.Is(err, usecase.ErrMigrationLocked),
+		errors.Is(err, usecase.ErrWalletAlreadyLinked):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, usecase.ErrRecoveryRateLimited):
+		response.TooManyRequests(c, err.Error())
+	case errors.Is(err, usecase.ErrMigrationUnsupportedChain):
+		response.UnprocessableEntity(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
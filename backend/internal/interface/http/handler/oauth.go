@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthHandler OAuth2 / OpenID Connect 授权服务器处理器
+type OAuthHandler struct {
+	oauthUC *usecase.OAuthUseCase
+}
+
+// NewOAuthHandler 创建 OAuth 处理器
+func NewOAuthHandler(oauthUC *usecase.OAuthUseCase) *OAuthHandler {
+	return &OAuthHandler{
+		oauthUC: oauthUC,
+	}
+}
+
+// OpenIDConfiguration OpenID Connect Discovery 元数据
+// GET /.well-known/openid-configuration
+// 按规范原样返回，不使用统一响应包装
+func (h *OAuthHandler) OpenIDConfiguration(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.oauthUC.OpenIDConfiguration())
+}
+
+// GetConsent 校验授权请求并返回同意页信息
+// GET /api/oauth/authorize?response_type=code&client_id=...&redirect_uri=...&scope=...&code_challenge=...
+func (h *OAuthHandler) GetConsent(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.oauthUC.GetConsent(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Authorize 提交用户的同意或拒绝，返回需要跳转的回调地址
+// POST /api/oauth/authorize
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.AuthorizeDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.oauthUC.Authorize(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Token 用授权码兑换 access token 与 ID token
+// POST /api/oauth/token (application/x-www-form-urlencoded)
+// 按 RFC 6749 返回，错误格式为 {"error": "...", "error_description": "..."}
+func (h *OAuthHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req dto.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	// client_secret_basic 优先于表单参数
+	if clientID, clientSecret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID = clientID
+		req.ClientSecret = clientSecret
+	}
+
+	resp, err := h.oauthUC.Exchange(c.Request.Context(), &req)
+	if err != nil {
+		status, code := http.StatusBadRequest, "invalid_request"
+		switch {
+		case errors.Is(err, usecase.ErrInvalidClient):
+			status, code = http.StatusUnauthorized, "invalid_client"
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		case errors.Is(err, usecase.ErrInvalidGrant):
+			code = "invalid_grant"
+		case errors.Is(err, usecase.ErrUnsupportedGrantType):
+			code = "unsupported_grant_type"
+		case errors.Is(err, usecase.ErrInvalidOAuthRequest):
+			code = "invalid_request"
+		default:
+			status, code = http.StatusInternalServerError, "server_error"
+		}
+		c.JSON(status, gin.H{"error": code, "error_description": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UserInfo OpenID Connect userinfo，使用 OAuth access token 认证
+// GET /api/oauth/userinfo
+func (h *OAuthHandler) UserInfo(c *gin.Context) {
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_request", "error_description": "missing bearer token"})
+		return
+	}
+
+	resp, err := h.oauthUC.UserInfo(c.Request.Context(), parts[1])
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAccessToken) {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token", "error_description": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error", "error_description": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RegisterClient 注册 OAuth 客户端 (管理后台)
+// POST /api/admin/oauth/clients
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var req dto.RegisterOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.oauthUC.RegisterClient(c.Request.Context(), c.GetString("userID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ListClients 获取所有 OAuth 客户端 (管理后台)
+// GET /api/admin/oauth/clients
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	clients, err := h.oauthUC.ListClients(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, clients)
+}
+
+// DeleteClient 删除 OAuth 客户端 (管理后台)
+// DELETE /api/admin/oauth/clients/:id
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	if err := h.oauthUC.DeleteClient(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *OAuthHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrOAuthClientNotFound):
+		response.NotFound(c, err.Error())
+	default:
+		response.BadRequest(c, err.Error())
+	}
+}
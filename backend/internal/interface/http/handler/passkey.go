@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyHandler passkey 注册与登录处理器
+type PasskeyHandler struct {
+	passkeyUC *usecase.PasskeyUseCase
+}
+
+// NewPasskeyHandler 创建 passkey 处理器
+func NewPasskeyHandler(passkeyUC *usecase.PasskeyUseCase) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyUC: passkeyUC,
+	}
+}
+
+// RegistrationOptions 获取注册 passkey 的参数 (需要钱包登录会话)
+// POST /api/user/passkeys/options
+func (h *PasskeyHandler) RegistrationOptions(c *gin.Context) {
+	resp, err := h.passkeyUC.RegistrationOptions(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Register 注册 passkey (需要钱包登录会话)
+// POST /api/user/passkeys
+func (h *PasskeyHandler) Register(c *gin.Context) {
+	var req dto.PasskeyRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	passkey, err := h.passkeyUC.Register(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, passkey)
+}
+
+// List 获取当前用户的 passkey
+// GET /api/user/passkeys
+func (h *PasskeyHandler) List(c *gin.Context) {
+	passkeys, err := h.passkeyUC.List(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, passkeys)
+}
+
+// Delete 删除 passkey (需要钱包登录会话)
+// DELETE /api/user/passkeys/:id
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	if err := h.passkeyUC.Delete(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// LoginOptions 获取 passkey 登录参数
+// POST /api/auth/passkey/options
+func (h *PasskeyHandler) LoginOptions(c *gin.Context) {
+	resp, err := h.passkeyUC.LoginOptions(c.Request.Context(), clientInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Login 使用 passkey 登录，签发只读会话
+// POST /api/auth/passkey/verify
+func (h *PasskeyHandler) Login(c *gin.Context) {
+	var req dto.PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.passkeyUC.Login(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *PasskeyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidPasskey):
+		response.Unauthorized(c, err.Error())
+	case errors.Is(err, usecase.ErrAccountDisabled):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, usecase.ErrTooManyAttempts):
+		response.TooManyRequests(c, err.Error())
+	case errors.Is(err, usecase.ErrPasskeyNotFound), errors.Is(err, usecase.ErrUserNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrPasskeyAlreadyRegistered):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, usecase.ErrPasskeyLimitReached), errors.Is(err, usecase.ErrPasskeyUnavailable):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
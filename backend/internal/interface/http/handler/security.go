@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHandler 登录防护管理处理器 (仅管理员)
+type SecurityHandler struct {
+	securityUC *usecase.SecurityUseCase
+}
+
+// NewSecurityHandler 创建登录防护管理处理器
+func NewSecurityHandler(securityUC *usecase.SecurityUseCase) *SecurityHandler {
+	return &SecurityHandler{
+		securityUC: securityUC,
+	}
+}
+
+// ListLockouts 列出生效中的登录锁定
+// GET /api/admin/security/lockouts
+func (h *SecurityHandler) ListLockouts(c *gin.Context) {
+	lockouts, err := h.securityUC.ListLockouts(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, lockouts)
+}
+
+// Unlock 解除登录锁定
+// DELETE /api/admin/security/lockouts/:scope/:key
+func (h *SecurityHandler) Unlock(c *gin.Context) {
+	scope := entity.LockoutScope(c.Param("scope"))
+	if err := h.securityUC.Unlock(c.Request.Context(), c.GetString("userID"), scope, c.Param("key")); err != nil {
+		if errors.Is(err, usecase.ErrInvalidLockoutScope) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// WalletHandler 多钱包绑定处理器
+type WalletHandler struct {
+	walletUC *usecase.WalletUseCase
+}
+
+// NewWalletHandler 创建钱包处理器
+func NewWalletHandler(walletUC *usecase.WalletUseCase) *WalletHandler {
+	return &WalletHandler{
+		walletUC: walletUC,
+	}
+}
+
+// ListWallets 获取我绑定的钱包
+// GET /api/user/wallets
+func (h *WalletHandler) ListWallets(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	wallets, err := h.walletUC.ListWallets(c.Request.Context(), userID.(string))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, wallets)
+}
+
+// CreateLinkChallenge 获取绑定新钱包的挑战
+// POST /api/user/wallets/challenge
+func (h *WalletHandler) CreateLinkChallenge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.LinkWalletChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	resp, err := h.walletUC.CreateLinkChallenge(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// LinkWallet 提交新钱包签名完成绑定
+// POST /api/user/wallets
+func (h *WalletHandler) LinkWallet(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.LinkWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	wallet, err := h.walletUC.LinkWallet(c.Request.Context(), userID.(string), c.GetString("sessionID"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, wallet)
+}
+
+// UnlinkWallet 解绑钱包
+// DELETE /api/user/wallets/:account
+func (h *WalletHandler) UnlinkWallet(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	if err := h.walletUC.UnlinkWallet(c.Request.Context(), userID.(string), c.Param("account")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"success": true,
+	})
+}
+
+// handleError 将用例错误映射为 HTTP 响应
+func (h *WalletHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrWalletNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, usecase.ErrWalletAlreadyLinked):
+		response.Conflict(c, err.Error())
+	case errors.Is(err, usecase.ErrLinkChallengeSession):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, usecase.ErrLinkChallengeNotFound),
+		errors.Is(err, usecase.ErrInvalidWalletSignature),
+		errors.Is(err, usecase.ErrUnsupportedSignatureType),
+		errors.Is(err, usecase.ErrUnsupportedChainID),
+		errors.Is(err, usecase.ErrCannotUnlinkPrimary),
+		errors.Is(err, chain.ErrInvalidAddress),
+		errors.Is(err, chain.ErrUnsupportedNamespace),
+		errors.Is(err, caip.ErrInvalidAccountID),
+		errors.Is(err, caip.ErrInvalidChainID):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
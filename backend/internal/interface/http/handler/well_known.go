@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownHandler struct {
+	jwtMgr *pkgJWT.JWTManager
+}
+
+func NewWellKnownHandler(jwtMgr *pkgJWT.JWTManager) *WellKnownHandler {
+	return &WellKnownHandler{
+		jwtMgr: jwtMgr,
+	}
+}
+
+// JWKS 公开 JWT 验证公钥
+// GET /.well-known/jwks.json
+// 按 RFC 7517 原样返回，不使用统一响应包装，便于标准 JWT 库直接消费
+func (h *WellKnownHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtMgr.JWKS())
+}
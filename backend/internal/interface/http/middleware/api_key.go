@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAuthenticator 校验 API key (摘要、有效期、scope 与限流)
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key, ip, scope string) (*entity.APIKey, error)
+}
+
+// AuthOrAPIKeyMiddleware 接受用户 JWT 或带有指定 scope 的 API key
+// API key 同样通过 Authorization: Bearer 传递，以 ddk_ 前缀区分
+func AuthOrAPIKeyMiddleware(authenticator TokenAuthenticator, keys APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	jwtAuth := AuthMiddleware(authenticator)
+	return func(c *gin.Context) {
+		if key, ok := bearerAPIKey(c); ok {
+			authenticateAPIKey(c, keys, key, scope)
+			return
+		}
+		jwtAuth(c)
+	}
+}
+
+// APIKeyMiddleware 只接受带有指定 scope 的 API key，用于合作平台等服务端调用
+func APIKeyMiddleware(keys APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := bearerAPIKey(c)
+		if !ok {
+			response.Unauthorized(c, "Missing API key")
+			c.Abort()
+			return
+		}
+		authenticateAPIKey(c, keys, key, scope)
+	}
+}
+
+// OptionalAPIKeyMiddleware 公开路由使用：携带 API key 时校验 scope 并计入该 key 的限流，否则直接放行
+func OptionalAPIKeyMiddleware(keys APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := bearerAPIKey(c); ok {
+			authenticateAPIKey(c, keys, key, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerAPIKey 从 Authorization 头中取出 API key
+func bearerAPIKey(c *gin.Context) (string, bool) {
+	parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" || !strings.HasPrefix(parts[1], entity.APIKeyPrefix) {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// authenticateAPIKey 校验 API key 并将 key 信息存入 context
+// role 由本次校验的 scope 决定 (entity.APIScopeRole)，后续的 RequireRole 按该角色判断
+func authenticateAPIKey(c *gin.Context, keys APIKeyAuthenticator, rawKey, scope string) {
+	key, err := keys.AuthenticateAPIKey(c.Request.Context(), rawKey, c.ClientIP(), scope)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInsufficientScope):
+			response.Forbidden(c, "API key does not have the required scope")
+		case errors.Is(err, usecase.ErrRateLimited):
+			response.TooManyRequests(c, "Rate limit exceeded")
+		case errors.Is(err, usecase.ErrInvalidAPIKey):
+			response.Unauthorized(c, "Invalid or expired API key")
+		default:
+			response.InternalError(c, err.Error())
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("apiKeyID", key.ID)
+	c.Set("apiKeyScopes", key.Scopes())
+	c.Set("role", string(entity.APIScopeRole(scope)))
+	if key.Platform != "" {
+		c.Set("platformID", key.Platform)
+	}
+	c.Next()
+}
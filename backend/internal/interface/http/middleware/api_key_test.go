@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeKeys 按 raw key 返回固定的 API key，并按 scope 校验
+type fakeKeys map[string]*entity.APIKey
+
+func (f fakeKeys) AuthenticateAPIKey(_ context.Context, raw, _, scope string) (*entity.APIKey, error) {
+	key, ok := f[raw]
+	if !ok {
+		return nil, usecase.ErrInvalidAPIKey
+	}
+	if scope != "" && !key.HasScope(scope) {
+		return nil, usecase.ErrInsufficientScope
+	}
+	return key, nil
+}
+
+// fakeTokens 拒绝所有 JWT，测试只走 API key 分支
+type fakeTokens struct{}
+
+func (fakeTokens) Authenticate(context.Context, string) (*pkgJWT.Claims, error) {
+	return nil, errors.New("invalid token")
+}
+
+var testKeys = fakeKeys{
+	"ddk_00000001_users":       {ID: "k1", Scope: entity.APIScopeUsersRead},
+	"ddk_00000002_leaderboard": {ID: "k2", Scope: entity.APIScopeLeaderboardRead},
+	"ddk_00000003_platform":    {ID: "k3", Scope: entity.APIScopeAuthorizationsRead, Platform: "demo"},
+}
+
+func serve(r *gin.Engine, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireRoleUsesScopeRoleForAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) { c.String(http.StatusOK, c.GetString("role")) }
+
+	// 管理后台只读 scope 映射为 ADMIN
+	r.GET("/admin/users", AuthOrAPIKeyMiddleware(fakeTokens{}, testKeys, entity.APIScopeUsersRead), RequireRole(entity.RoleAdmin), ok)
+	// 其余 scope 映射为 USER，不能通过 ADMIN 角色校验
+	r.GET("/admin/leaderboard", AuthOrAPIKeyMiddleware(fakeTokens{}, testKeys, entity.APIScopeLeaderboardRead), RequireRole(entity.RoleAdmin), ok)
+
+	if w := serve(r, "/admin/users", "ddk_00000001_users"); w.Code != http.StatusOK || w.Body.String() != string(entity.RoleAdmin) {
+		t.Errorf("users:read key: status %d body %q, want 200 ADMIN", w.Code, w.Body.String())
+	}
+	if w := serve(r, "/admin/leaderboard", "ddk_00000002_leaderboard"); w.Code != http.StatusForbidden {
+		t.Errorf("leaderboard:read key on admin route: status %d, want 403", w.Code)
+	}
+	if w := serve(r, "/admin/users", "ddk_00000002_leaderboard"); w.Code != http.StatusForbidden {
+		t.Errorf("key without scope: status %d, want 403", w.Code)
+	}
+	if w := serve(r, "/admin/users", "ddk_99999999_unknown"); w.Code != http.StatusUnauthorized {
+		t.Errorf("unknown key: status %d, want 401", w.Code)
+	}
+}
+
+func TestAPIKeyMiddlewareSetsPlatform(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/platform/authorizations", APIKeyMiddleware(testKeys, entity.APIScopeAuthorizationsRead), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("platformID"))
+	})
+
+	if w := serve(r, "/platform/authorizations", "ddk_00000003_platform"); w.Code != http.StatusOK || w.Body.String() != "demo" {
+		t.Errorf("platform key: status %d body %q, want 200 demo", w.Code, w.Body.String())
+	}
+	// 只接受 API key：缺少 key 或 key 没有 authorizations:read 均被拒绝
+	if w := serve(r, "/platform/authorizations", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("missing key: status %d, want 401", w.Code)
+	}
+	if w := serve(r, "/platform/authorizations", "ddk_00000001_users"); w.Code != http.StatusForbidden {
+		t.Errorf("key without scope: status %d, want 403", w.Code)
+	}
+}
@@ -1,15 +1,35 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"strings"
 
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/usecase"
 	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
 	"github.com/dedata/dedata-backend/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
+// TokenAuthenticator 校验 access token (签名、有效期、吊销状态)
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, tokenString string) (*pkgJWT.Claims, error)
+}
+
 // AuthMiddleware JWT 认证中间件
-func AuthMiddleware(jwtMgr *pkgJWT.JWTManager) gin.HandlerFunc {
+// passkey 登录的只读会话只能访问 GET / HEAD 请求，支付、钱包变更等写操作需要钱包签名登录
+func AuthMiddleware(authenticator TokenAuthenticator) gin.HandlerFunc {
+	return authMiddleware(authenticator, false)
+}
+
+// ReadSessionAuthMiddleware 同 AuthMiddleware，但允许只读会话执行写操作，仅用于登出、吊销会话等管理自身会话的接口
+func ReadSessionAuthMiddleware(authenticator TokenAuthenticator) gin.HandlerFunc {
+	return authMiddleware(authenticator, true)
+}
+
+func authMiddleware(authenticator TokenAuthenticator, allowReadSessionWrites bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. 从 Header 获取 token
 		authHeader := c.GetHeader("Authorization")
@@ -30,25 +50,34 @@ func AuthMiddleware(jwtMgr *pkgJWT.JWTManager) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		// 3. 验证 token
-		claims, err := jwtMgr.ValidateToken(tokenString)
+		claims, err := authenticator.Authenticate(c.Request.Context(), tokenString)
 		if err != nil {
+			if errors.Is(err, usecase.ErrAccountDisabled) {
+				response.Forbidden(c, "Account is suspended or blacklisted")
+				c.Abort()
+				return
+			}
 			response.Unauthorized(c, "Invalid or expired token")
 			c.Abort()
 			return
 		}
 
-		// 4. 将用户信息存入 context
-		c.Set("userID", claims.UserID)
-		c.Set("address", claims.Address)
-		c.Set("did", claims.DID)
-		c.Set("role", claims.Role)
+		// 4. 只读会话不能执行写操作
+		if !allowReadSessionWrites && claims.Scope == string(entity.SessionScopeRead) && !isReadMethod(c.Request.Method) {
+			response.ForbiddenWithData(c, "This action requires signing in with your wallet", gin.H{"walletSignatureRequired": true})
+			c.Abort()
+			return
+		}
+
+		// 5. 将用户信息存入 context
+		setClaims(c, claims)
 
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware 可选的 JWT 认证中间件 (不强制要求登录)
-func OptionalAuthMiddleware(jwtMgr *pkgJWT.JWTManager) gin.HandlerFunc {
+func OptionalAuthMiddleware(authenticator TokenAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -63,18 +92,30 @@ func OptionalAuthMiddleware(jwtMgr *pkgJWT.JWTManager) gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		claims, err := jwtMgr.ValidateToken(tokenString)
+		claims, err := authenticator.Authenticate(c.Request.Context(), tokenString)
 		if err != nil {
 			c.Next()
 			return
 		}
 
 		// 存入用户信息
-		c.Set("userID", claims.UserID)
-		c.Set("address", claims.Address)
-		c.Set("did", claims.DID)
-		c.Set("role", claims.Role)
+		setClaims(c, claims)
 
 		c.Next()
 	}
 }
+
+// setClaims 将 token 中的用户信息存入 context
+func setClaims(c *gin.Context, claims *pkgJWT.Claims) {
+	c.Set("claims", claims)
+	c.Set("userID", claims.UserID)
+	c.Set("address", claims.Address)
+	c.Set("did", claims.DID)
+	c.Set("role", claims.Role)
+	c.Set("sessionID", claims.SessionID)
+}
+
+// isReadMethod 是否为不修改状态的请求方法
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
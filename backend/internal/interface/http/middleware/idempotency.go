@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader 客户端为每次用户操作生成的唯一 key (例如 UUID)
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength Idempotency-Key 最大长度
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore 保存首次响应并判断重复请求
+type IdempotencyStore interface {
+	Begin(ctx context.Context, key, fingerprint string) (*entity.IdempotencyRecord, error)
+	Hold(ctx context.Context, key, fingerprint string) func()
+	Complete(ctx context.Context, key, fingerprint string, statusCode int, contentType string, body []byte)
+	Release(ctx context.Context, key string)
+}
+
+// Idempotency 按 Idempotency-Key 请求头去重，需放在 AuthMiddleware 之后
+// key 按用户与路由隔离；重复请求直接重放首次响应并带 Idempotent-Replayed: true，
+// 首次请求仍在处理中返回 409，同一个 key 用于不同请求体返回 422。
+// 不带请求头的请求不受影响；5xx 响应不保存，客户端可以使用同一个 key 重试
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			response.BadRequest(c, "Idempotency-Key is too long")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, "failed to read request body")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := c.GetString("userID") + ":" + c.Request.Method + ":" + c.FullPath() + ":" + idempotencyKey
+		digest := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(digest[:])
+
+		record, err := store.Begin(c.Request.Context(), key, fingerprint)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrIdempotencyKeyInUse):
+				response.Conflict(c, err.Error())
+			case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+				response.UnprocessableEntity(c, err.Error())
+			default:
+				response.InternalError(c, err.Error())
+			}
+			c.Abort()
+			return
+		}
+		if record != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		// 客户端断开后仍需续期并保存结果，否则重试会一直得到 409 直到占位过期
+		ctx := context.WithoutCancel(c.Request.Context())
+		stop := store.Hold(ctx, key, fingerprint)
+
+		// handler panic 时同样停止续期并释放占位 (Recovery 中间件返回 500)，否则重试会一直得到 409
+		completed := false
+		defer func() {
+			stop()
+			if !completed {
+				store.Release(ctx, key)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		store.Complete(ctx, key, fingerprint, status, writer.Header().Get("Content-Type"), writer.body.Bytes())
+		completed = true
+	}
+}
+
+// recordingWriter 在写出响应的同时保留一份响应体
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/infrastructure/cache"
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// idempotencyRecordKey 与中间件生成的 key 一致：userID:method:path:Idempotency-Key
+const idempotencyRecordKey = "idempotency:user-1:POST:/checkin:key-1"
+
+// idempotencyServer 使用 miniredis 上的真实仓储；handler 由各测试提供
+// 占位有效期为 1 秒，续期间隔约 333 毫秒。miniredis 的 TTL 只随 FastForward 减少
+func idempotencyServer(t *testing.T, mr *miniredis.Miniredis, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	store := usecase.NewIdempotencyUseCase(cache.NewRedisIdempotencyRepository(rdb), &config.CheckInConfig{LockTimeoutSec: 1}, zap.NewNop())
+
+	r := gin.New()
+	r.Use(Recovery())
+	r.POST("/checkin", func(c *gin.Context) { c.Set("userID", "user-1") }, Idempotency(store), handler)
+	return r
+}
+
+func post(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/checkin", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysCompletedResponse(t *testing.T) {
+	var calls int32
+	r := idempotencyServer(t, miniredis.RunT(t), func(c *gin.Context) {
+		n := atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusOK, gin.H{"call": n})
+	})
+
+	first := post(r, "key-1", `{"amount":1}`)
+	second := post(r, "key-1", `{"amount":1}`)
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("Idempotent-Replayed header is only expected on the replay")
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("replayed content type = %q", second.Header().Get("Content-Type"))
+	}
+
+	// 不带请求头的请求不去重
+	post(r, "", `{"amount":1}`)
+	post(r, "", `{"amount":1}`)
+	if calls != 3 {
+		t.Errorf("handler ran %d times without Idempotency-Key, want 3", calls)
+	}
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	r := idempotencyServer(t, miniredis.RunT(t), func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+	post(r, "key-1", `{"amount":1}`)
+	if w := post(r, "key-1", `{"amount":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body: status %d, want 422", w.Code)
+	}
+}
+
+func TestIdempotencyConflictWhileInFlight(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	r := idempotencyServer(t, miniredis.RunT(t), func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(r, "key-1", `{}`) }()
+	<-entered
+
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("duplicate while in flight: status %d, want 409", w.Code)
+	}
+	close(release)
+	if w := <-done; w.Code != http.StatusOK {
+		t.Errorf("first request: status %d, want 200", w.Code)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	var calls int32
+	mr := miniredis.RunT(t)
+	r := idempotencyServer(t, mr, func(c *gin.Context) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			c.JSON(http.StatusBadGateway, gin.H{})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusBadGateway {
+		t.Fatalf("first request: status %d, want 502", w.Code)
+	}
+	if mr.Exists(idempotencyRecordKey) {
+		t.Fatal("5xx response was kept, the client cannot retry")
+	}
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusOK || calls != 2 {
+		t.Errorf("retry: status %d after %d calls, want 200 after 2", w.Code, calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	var calls int32
+	mr := miniredis.RunT(t)
+	r := idempotencyServer(t, mr, func(c *gin.Context) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("first request: status %d, want 500", w.Code)
+	}
+	if mr.Exists(idempotencyRecordKey) {
+		t.Fatal("reservation was kept after a panic, the client cannot retry")
+	}
+	// 续期已停止：之后不会重新创建占位
+	time.Sleep(500 * time.Millisecond)
+	if mr.Exists(idempotencyRecordKey) {
+		t.Fatal("reservation renewed after the request finished")
+	}
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusOK || calls != 2 {
+		t.Errorf("retry: status %d after %d calls, want 200 after 2", w.Code, calls)
+	}
+}
+
+func TestIdempotencyConcurrentSameKey(t *testing.T) {
+	var calls int32
+	r := idempotencyServer(t, miniredis.RunT(t), func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	const workers = 20
+	var wg sync.WaitGroup
+	codes := make(chan int, workers)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			codes <- post(r, "key-1", `{}`).Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	// 其余请求在首次请求处理中得到 409，完成后得到重放的 200
+	for code := range codes {
+		if code != http.StatusOK && code != http.StatusConflict {
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+}
+
+func TestIdempotencyHoldRenewsReservation(t *testing.T) {
+	mr := miniredis.RunT(t)
+	r := idempotencyServer(t, mr, func(c *gin.Context) {
+		// 占位剩余 100 毫秒，等待续期后应恢复为完整的有效期
+		mr.FastForward(900 * time.Millisecond)
+		time.Sleep(500 * time.Millisecond)
+		if ttl := mr.TTL(idempotencyRecordKey); ttl <= 500*time.Millisecond {
+			t.Errorf("reservation TTL = %v after renewal, want close to 1s", ttl)
+		}
+
+		// 不续期时已经过期
+		mr.FastForward(900 * time.Millisecond)
+		if !mr.Exists(idempotencyRecordKey) {
+			t.Error("reservation expired while the request was in flight")
+		}
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	if w := post(r, "key-1", `{}`); w.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", w.Code)
+	}
+	// 完成后的记录使用重放有效期，不再是占位有效期
+	if ttl := mr.TTL(idempotencyRecordKey); ttl < time.Hour {
+		t.Errorf("completed record TTL = %v, want idempotency_ttl_hours", ttl)
+	}
+}
+
+func TestIdempotencyReservationExpiresWhileHeld(t *testing.T) {
+	var calls int32
+	mr := miniredis.RunT(t)
+	r := idempotencyServer(t, mr, func(c *gin.Context) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			c.JSON(http.StatusOK, gin.H{"call": "second"})
+			return
+		}
+		// 续期来不及执行占位就过期 (例如 Redis 暂停)：续期不会重新创建记录
+		mr.FastForward(2 * time.Second)
+		time.Sleep(500 * time.Millisecond)
+		if mr.Exists(idempotencyRecordKey) {
+			t.Error("renewal recreated an expired reservation")
+		}
+		c.JSON(http.StatusOK, gin.H{"call": "first"})
+	})
+
+	first := post(r, "key-1", `{}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", first.Code)
+	}
+	// 首次请求完成后仍保存响应，重复请求重放首次响应
+	replay := post(r, "key-1", `{}`)
+	if replay.Body.String() != first.Body.String() || calls != 1 {
+		t.Errorf("replay = %s after %d calls, want %s after 1", replay.Body, calls, first.Body)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 角色校验中间件，需放在 AuthMiddleware 或 AuthOrAPIKeyMiddleware 之后
+// API key 请求的角色由 scope 映射得到 (entity.APIScopeRole)
+func RequireRole(roles ...entity.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := entity.UserRole(c.GetString("role"))
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		response.Forbidden(c, "Insufficient permissions")
+		c.Abort()
+	}
+}
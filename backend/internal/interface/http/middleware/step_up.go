@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"github.com/dedata/dedata-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// StepUpChecker 检查当前会话是否已完成 TOTP 二次验证
+type StepUpChecker interface {
+	CheckStepUp(ctx context.Context, userID, sessionID string) error
+}
+
+// RequireStepUp 敏感管理操作的二次验证中间件，需放在 AuthMiddleware 与 RequireRole 之后
+// 返回的 data 告诉客户端应先调用 /api/admin/mfa/verify 或先绑定 TOTP
+func RequireStepUp(checker StepUpChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := checker.CheckStepUp(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"))
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrStepUpRequired):
+				response.ForbiddenWithData(c, err.Error(), gin.H{"stepUpRequired": true})
+			case errors.Is(err, usecase.ErrTOTPEnrollmentRequired):
+				response.ForbiddenWithData(c, err.Error(), gin.H{"enrollmentRequired": true})
+			default:
+				response.InternalError(c, err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminRoutes 注册管理后台路由 (仅管理员)
+// 只读接口也接受带有对应 scope 的 API key，修改操作只接受管理员 JWT 且需要二次验证
+func RegisterAdminRoutes(r *gin.RouterGroup, h *handler.AdminHandler, authenticator middleware.TokenAuthenticator, keys middleware.APIKeyAuthenticator, stepUp middleware.StepUpChecker) {
+	admin := r.Group("/admin")
+	requireAdmin := middleware.RequireRole(entity.RoleAdmin)
+	requireStepUp := middleware.RequireStepUp(stepUp)
+	withScope := func(scope string) gin.HandlerFunc {
+		return middleware.AuthOrAPIKeyMiddleware(authenticator, keys, scope)
+	}
+	{
+		admin.GET("/users", withScope(entity.APIScopeUsersRead), requireAdmin, h.ListUsers)
+		admin.GET("/users/:id", withScope(entity.APIScopeUsersRead), requireAdmin, h.GetUser)
+		admin.PUT("/users/:id/status", middleware.AuthMiddleware(authenticator), requireAdmin, requireStepUp, h.UpdateUserStatus)
+		admin.PUT("/users/:id/role", middleware.AuthMiddleware(authenticator), requireAdmin, requireStepUp, h.UpdateUserRole)
+		admin.GET("/users/:id/checkins", withScope(entity.APIScopeCheckinsRead), requireAdmin, h.GetUserCheckIns)
+		admin.GET("/payouts", withScope(entity.APIScopeAdminPayouts), requireAdmin, h.ListPendingPayouts)
+		admin.GET("/checkin/streak-rules", middleware.AuthMiddleware(authenticator), requireAdmin, h.ListStreakRules)
+		admin.PUT("/checkin/streak-rules", middleware.AuthMiddleware(authenticator), requireAdmin, requireStepUp, h.ReplaceStreakRules)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAPIKeyRoutes 注册 API key 管理路由 (仅管理员 JWT，API key 不能管理 API key；创建与吊销需要二次验证)
+func RegisterAPIKeyRoutes(r *gin.RouterGroup, h *handler.APIKeyHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	keys := r.Group("/admin/api-keys")
+	keys.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin))
+	{
+		keys.GET("", h.List)
+		keys.POST("", middleware.RequireStepUp(stepUp), h.Create)
+		keys.DELETE("/:id", middleware.RequireStepUp(stepUp), h.Revoke)
+	}
+}
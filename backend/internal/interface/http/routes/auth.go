@@ -3,23 +3,25 @@ package routes
 import (
 	"github.com/dedata/dedata-backend/internal/interface/http/handler"
 	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
-	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterAuthRoutes 注册认证路由
-func RegisterAuthRoutes(r *gin.RouterGroup, authHandler *handler.AuthHandler, jwtMgr *pkgJWT.JWTManager) {
+func RegisterAuthRoutes(r *gin.RouterGroup, authHandler *handler.AuthHandler, authenticator middleware.TokenAuthenticator) {
 	auth := r.Group("/auth")
 	{
 		// 公开路由
 		auth.POST("/nonce", authHandler.GetNonce)
 		auth.POST("/verify", authHandler.Verify)
+		auth.POST("/refresh", authHandler.Refresh)
 
-		// 需要认证的路由
+		// 需要认证的路由 (passkey 只读会话也可以登出和管理会话)
 		authenticated := auth.Group("")
-		authenticated.Use(middleware.AuthMiddleware(jwtMgr))
+		authenticated.Use(middleware.ReadSessionAuthMiddleware(authenticator))
 		{
 			authenticated.POST("/logout", authHandler.Logout)
+			authenticated.GET("/sessions", authHandler.ListSessions)
+			authenticated.DELETE("/sessions/:id", authHandler.RevokeSession)
 		}
 	}
 }
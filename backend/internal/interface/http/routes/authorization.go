@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAuthorizationRoutes 注册平台授权路由
+func RegisterAuthorizationRoutes(r *gin.RouterGroup, h *handler.AuthorizationHandler, authenticator middleware.TokenAuthenticator, keys middleware.APIKeyAuthenticator) {
+	// 公开接口
+	r.GET("/platforms", h.ListPlatforms)
+
+	// 用户管理自己的授权
+	authorizations := r.Group("/authorizations")
+	authorizations.Use(middleware.AuthMiddleware(authenticator))
+	{
+		authorizations.GET("", h.ListAuthorizations)
+		authorizations.POST("/challenge", h.CreateGrantChallenge)
+		authorizations.POST("", h.Grant)
+		authorizations.DELETE("/:id", h.Revoke)
+	}
+
+	// 合作平台查询授权状态 (绑定平台、带有 authorizations:read 的 API key)
+	platform := r.Group("/platform")
+	platform.Use(middleware.APIKeyMiddleware(keys, entity.APIScopeAuthorizationsRead))
+	{
+		platform.GET("/authorizations", h.CheckAuthorization)
+	}
+}
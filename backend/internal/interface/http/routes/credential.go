@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterCredentialRoutes 注册 Verifiable Credential 路由
+func RegisterCredentialRoutes(r *gin.RouterGroup, h *handler.CredentialHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	credentials := r.Group("/credentials")
+	{
+		// 公开: 第三方验证 credential 和拉取吊销状态列表
+		credentials.POST("/verify", h.Verify)
+		credentials.GET("/status/:list", h.StatusList)
+
+		authed := credentials.Group("")
+		authed.Use(middleware.AuthMiddleware(authenticator))
+		{
+			authed.GET("", h.ListCredentials)
+			authed.POST("", h.Issue)
+			authed.DELETE("/:id", h.Revoke)
+		}
+	}
+
+	admin := r.Group("/admin/credentials")
+	admin.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin), middleware.RequireStepUp(stepUp))
+	{
+		admin.POST("/:id/revoke", h.AdminRevoke)
+	}
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDIDRoutes 注册公开的 DID 解析路由
+func RegisterDIDRoutes(r *gin.RouterGroup, h *handler.DIDHandler) {
+	r.GET("/did/:did", h.Resolve)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMFARoutes 注册管理员 TOTP 二次验证路由 (仅管理员 JWT)
+func RegisterMFARoutes(r *gin.RouterGroup, h *handler.MFAHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	mfa := r.Group("/admin/mfa")
+	mfa.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin))
+	{
+		mfa.GET("", h.Status)
+		mfa.POST("/verify", h.Verify)
+
+		mfa.POST("/totp", h.Enroll)
+		mfa.POST("/totp/confirm", h.Confirm)
+		mfa.DELETE("/totp", h.Disable)
+		mfa.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+
+		mfa.DELETE("/users/:id", middleware.RequireStepUp(stepUp), h.Reset)
+	}
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMigrationRoutes 注册账户迁移路由
+func RegisterMigrationRoutes(r *gin.RouterGroup, h *handler.MigrationHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	// 旧钱包仍可用: 已登录用户发起，新旧钱包签名后立即生效
+	migrations := r.Group("/user/migrations")
+	migrations.Use(middleware.AuthMiddleware(authenticator))
+	{
+		migrations.GET("", h.ListMigrations)
+		migrations.POST("/challenge", h.CreateMigrationChallenge)
+		migrations.POST("", h.Migrate)
+		migrations.DELETE("/:id", h.CancelMigration)
+	}
+
+	// 旧钱包已丢失: 无需登录，仅新钱包签名，时间锁到期后由管理员批准
+	recovery := r.Group("/auth/recovery")
+	{
+		recovery.POST("/challenge", h.CreateRecoveryChallenge)
+		recovery.POST("", h.Recover)
+	}
+
+	admin := r.Group("/admin/migrations")
+	admin.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin))
+	{
+		admin.GET("", h.AdminListMigrations)
+		admin.POST("/:id/approve", middleware.RequireStepUp(stepUp), h.AdminApproveMigration)
+		admin.POST("/:id/reject", middleware.RequireStepUp(stepUp), h.AdminRejectMigration)
+	}
+}
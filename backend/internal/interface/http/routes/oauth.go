@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOAuthRoutes 注册 OAuth2 / OpenID Connect 路由
+func RegisterOAuthRoutes(r *gin.RouterGroup, h *handler.OAuthHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	oauth := r.Group("/oauth")
+	{
+		// 第三方应用调用 (client 认证 / OAuth access token)
+		oauth.POST("/token", h.Token)
+		oauth.GET("/userinfo", h.UserInfo)
+
+		// 同意页 (需要用户登录)
+		consent := oauth.Group("/authorize")
+		consent.Use(middleware.AuthMiddleware(authenticator))
+		{
+			consent.GET("", h.GetConsent)
+			consent.POST("", h.Authorize)
+		}
+	}
+
+	// 客户端管理 (仅管理员，注册与删除需要二次验证)
+	clients := r.Group("/admin/oauth/clients")
+	clients.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin))
+	{
+		clients.GET("", h.ListClients)
+		clients.POST("", middleware.RequireStepUp(stepUp), h.RegisterClient)
+		clients.DELETE("/:id", middleware.RequireStepUp(stepUp), h.DeleteClient)
+	}
+}
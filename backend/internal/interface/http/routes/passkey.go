@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPasskeyRoutes 注册 passkey 路由
+// 注册与删除是写操作，AuthMiddleware 只允许钱包登录会话调用；passkey 登录得到只读会话
+func RegisterPasskeyRoutes(r *gin.RouterGroup, h *handler.PasskeyHandler, authenticator middleware.TokenAuthenticator) {
+	login := r.Group("/auth/passkey")
+	{
+		login.POST("/options", h.LoginOptions)
+		login.POST("/verify", h.Login)
+	}
+
+	passkeys := r.Group("/user/passkeys")
+	passkeys.Use(middleware.AuthMiddleware(authenticator))
+	{
+		passkeys.GET("", h.List)
+		passkeys.POST("/options", h.RegistrationOptions)
+		passkeys.POST("", h.Register)
+		passkeys.DELETE("/:id", h.Delete)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSecurityRoutes 注册登录防护管理路由 (仅管理员)
+func RegisterSecurityRoutes(r *gin.RouterGroup, h *handler.SecurityHandler, authenticator middleware.TokenAuthenticator, stepUp middleware.StepUpChecker) {
+	security := r.Group("/admin/security")
+	security.Use(middleware.AuthMiddleware(authenticator), middleware.RequireRole(entity.RoleAdmin))
+	{
+		security.GET("/lockouts", h.ListLockouts)
+		security.DELETE("/lockouts/:scope/:key", middleware.RequireStepUp(stepUp), h.Unlock)
+	}
+}
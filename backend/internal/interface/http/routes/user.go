@@ -1,22 +1,32 @@
 package routes
 
 import (
+	"github.com/dedata/dedata-backend/internal/domain/entity"
 	"github.com/dedata/dedata-backend/internal/interface/http/handler"
 	"github.com/dedata/dedata-backend/internal/interface/http/middleware"
-	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterUserRoutes 注册用户路由
-func RegisterUserRoutes(r *gin.RouterGroup, h *handler.UserHandler, jwtMgr *pkgJWT.JWTManager) {
+func RegisterUserRoutes(r *gin.RouterGroup, h *handler.UserHandler, walletHandler *handler.WalletHandler, authenticator middleware.TokenAuthenticator, keys middleware.APIKeyAuthenticator) {
 	user := r.Group("/user")
 	{
 		// 需要认证的路由
-		user.GET("/me", middleware.AuthMiddleware(jwtMgr), h.GetMyInfo)
-		user.GET("/profile", middleware.AuthMiddleware(jwtMgr), h.GetProfile)
-		user.PUT("/profile", middleware.AuthMiddleware(jwtMgr), h.UpdateProfile)
+		user.GET("/me", middleware.AuthMiddleware(authenticator), h.GetMyInfo)
+		user.GET("/profile", middleware.AuthMiddleware(authenticator), h.GetProfile)
+		user.PUT("/profile", middleware.AuthMiddleware(authenticator), h.UpdateProfile)
 
-		// 公开的排行榜
-		user.GET("/leaderboard", h.GetLeaderboard)
+		// 多钱包绑定
+		wallets := user.Group("/wallets")
+		wallets.Use(middleware.AuthMiddleware(authenticator))
+		{
+			wallets.GET("", walletHandler.ListWallets)
+			wallets.POST("/challenge", walletHandler.CreateLinkChallenge)
+			wallets.POST("", walletHandler.LinkWallet)
+			wallets.DELETE("/:account", walletHandler.UnlinkWallet)
+		}
+
+		// 公开的排行榜 (携带 API key 时按 key 限流)
+		user.GET("/leaderboard", middleware.OptionalAPIKeyMiddleware(keys, entity.APIScopeLeaderboardRead), h.GetLeaderboard)
 	}
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/dedata/dedata-backend/internal/interface/http/handler"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterWellKnownRoutes 注册 /.well-known 公开路由
+func RegisterWellKnownRoutes(r gin.IRouter, h *handler.WellKnownHandler, didHandler *handler.DIDHandler, oauthHandler *handler.OAuthHandler) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", h.JWKS)
+		wellKnown.GET("/did.json", didHandler.PlatformDocument)
+		wellKnown.GET("/openid-configuration", oauthHandler.OpenIDConfiguration)
+	}
+}
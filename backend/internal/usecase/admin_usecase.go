@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/reward"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUserNotFound 用户不存在
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrInvalidStatus 无效的用户状态
+	ErrInvalidStatus = errors.New("invalid user status")
+
+	// ErrInvalidRole 无效的用户角色
+	ErrInvalidRole = errors.New("invalid user role")
+
+	// ErrCannotModifySelf 管理员不能修改自己的状态或角色
+	ErrCannotModifySelf = errors.New("cannot change your own status or role")
+
+	// ErrInvalidStreakRule 连续签到奖励规则不合法 (天数重复、倍数或额外奖励不是十进制数、奖励为负)
+	ErrInvalidStreakRule = errors.New("invalid streak rule")
+)
+
+// AdminUseCase 管理后台用例
+type AdminUseCase struct {
+	userRepo       repository.UserRepository
+	checkinRepo    repository.CheckInRepository
+	streakRuleRepo repository.StreakRuleRepository
+	authUseCase    *AuthUseCase
+	checkinConfig  *config.CheckInConfig
+	logger         *zap.Logger
+}
+
+func NewAdminUseCase(
+	userRepo repository.UserRepository,
+	checkinRepo repository.CheckInRepository,
+	streakRuleRepo repository.StreakRuleRepository,
+	authUseCase *AuthUseCase,
+	checkinCfg *config.CheckInConfig,
+	logger *zap.Logger,
+) *AdminUseCase {
+	return &AdminUseCase{
+		userRepo:       userRepo,
+		checkinRepo:    checkinRepo,
+		streakRuleRepo: streakRuleRepo,
+		authUseCase:    authUseCase,
+		checkinConfig:  checkinCfg,
+		logger:         logger,
+	}
+}
+
+// ListUsers 搜索用户（分页）
+func (uc *AdminUseCase) ListUsers(ctx context.Context, filter repository.UserFilter, page, limit int) (*dto.AdminUserListResponse, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	if filter.Role != "" && !filter.Role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	users, total, err := uc.userRepo.Search(ctx, filter, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.AdminUserListResponse{
+		Data: users,
+		Pagination: dto.Pagination{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// GetUser 获取用户详情
+func (uc *AdminUseCase) GetUser(ctx context.Context, userID string) (*entity.User, error) {
+	return uc.findUser(ctx, userID)
+}
+
+// UpdateUserStatus 修改用户状态，禁用时立即吊销其所有会话
+func (uc *AdminUseCase) UpdateUserStatus(ctx context.Context, adminID, userID string, req *dto.UpdateUserStatusRequest) (*entity.User, error) {
+	status := entity.UserStatus(req.Status)
+	if !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := uc.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.UpdateStatus(ctx, userID, status); err != nil {
+		return nil, fmt.Errorf("failed to update user status: %w", err)
+	}
+
+	if status != entity.StatusActive {
+		if err := uc.authUseCase.RevokeAllSessions(ctx, userID); err != nil {
+			uc.logger.Error("Failed to revoke sessions of disabled user",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	uc.logger.Info("Admin changed user status",
+		zap.String("admin_id", adminID),
+		zap.String("user_id", userID),
+		zap.String("from", string(user.Status)),
+		zap.String("to", string(status)),
+		zap.String("reason", req.Reason),
+	)
+
+	user.Status = status
+	return user, nil
+}
+
+// UpdateUserRole 修改用户角色
+func (uc *AdminUseCase) UpdateUserRole(ctx context.Context, adminID, userID string, req *dto.UpdateUserRoleRequest) (*entity.User, error) {
+	role := entity.UserRole(req.Role)
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := uc.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	uc.logger.Info("Admin changed user role",
+		zap.String("admin_id", adminID),
+		zap.String("user_id", userID),
+		zap.String("from", string(user.Role)),
+		zap.String("to", string(role)),
+	)
+
+	user.Role = role
+	return user, nil
+}
+
+// GetUserCheckIns 查看用户签到记录
+func (uc *AdminUseCase) GetUserCheckIns(ctx context.Context, userID string, page, pageSize int) ([]*entity.CheckIn, int64, error) {
+	if _, err := uc.findUser(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	return uc.checkinRepo.FindByUserID(ctx, userID, pageSize, offset)
+}
+
+// ListPendingPayouts 查询待发放的签到奖励 (支付成功等待发放，以及链上交易待确认)
+func (uc *AdminUseCase) ListPendingPayouts(ctx context.Context) (*dto.PendingPayoutsResponse, error) {
+	queued, err := uc.checkinRepo.FindPaymentSuccessRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find queued payouts: %w", err)
+	}
+	issuing, err := uc.checkinRepo.FindIssuingRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find issuing payouts: %w", err)
+	}
+
+	if queued == nil {
+		queued = []*entity.CheckIn{}
+	}
+	if issuing == nil {
+		issuing = []*entity.CheckIn{}
+	}
+	return &dto.PendingPayoutsResponse{Queued: queued, Issuing: issuing}, nil
+}
+
+// ListStreakRules 查看连续签到奖励规则
+func (uc *AdminUseCase) ListStreakRules(ctx context.Context) ([]*entity.StreakRule, error) {
+	rules, err := uc.streakRuleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streak rules: %w", err)
+	}
+	if rules == nil {
+		rules = []*entity.StreakRule{}
+	}
+	return rules, nil
+}
+
+// ReplaceStreakRules 替换全部连续签到奖励规则，每条规则按 reward_amount 计算一次奖励以拒绝无效的数量
+// 新规则只影响尚未发放的签到，已保存奖励数量的签到不变
+func (uc *AdminUseCase) ReplaceStreakRules(ctx context.Context, adminID string, req *dto.ReplaceStreakRulesRequest) ([]*entity.StreakRule, error) {
+	rules := make([]*entity.StreakRule, 0, len(req.Rules))
+	seen := make(map[int]bool)
+	for _, r := range req.Rules {
+		if seen[r.MinDays] {
+			return nil, fmt.Errorf("%w: duplicate min_days %d", ErrInvalidStreakRule, r.MinDays)
+		}
+		seen[r.MinDays] = true
+
+		rule := &entity.StreakRule{
+			MinDays:    r.MinDays,
+			Multiplier: strings.TrimSpace(r.Multiplier),
+			Bonus:      strings.TrimSpace(r.Bonus),
+		}
+		if rule.Multiplier == "" {
+			rule.Multiplier = "1"
+		}
+		if rule.Bonus == "" {
+			rule.Bonus = "0"
+		}
+		if _, err := reward.Calculate(uc.checkinConfig.RewardAmount, rule.Multiplier, rule.Bonus); err != nil {
+			return nil, fmt.Errorf("%w (min_days %d): %v", ErrInvalidStreakRule, r.MinDays, err)
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].MinDays < rules[j].MinDays })
+
+	if err := uc.streakRuleRepo.Replace(ctx, rules); err != nil {
+		return nil, fmt.Errorf("failed to replace streak rules: %w", err)
+	}
+
+	uc.logger.Info("Admin replaced streak rules",
+		zap.String("admin_id", adminID),
+		zap.Int("rules", len(rules)),
+	)
+	return uc.ListStreakRules(ctx)
+}
+
+func (uc *AdminUseCase) findUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAPIKeyNotFound API key 不存在
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidAPIKey API key 缺失、无效、已过期或已吊销
+	ErrInvalidAPIKey = errors.New("invalid api key")
+
+	// ErrPlatformRequired authorizations:read 需要绑定已接入的合作平台
+	ErrPlatformRequired = errors.New("authorizations:read requires a configured platform")
+
+	// ErrInsufficientScope API key 没有访问该接口所需的 scope
+	ErrInsufficientScope = errors.New("api key does not have the required scope")
+
+	// ErrRateLimited API key 超过每分钟请求数
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+const (
+	// apiKeyIDBytes key 前缀中随机部分的字节数，完整的 key 为 ddk_<8 位 hex>_<secret>
+	apiKeyIDBytes = 4
+
+	// lastUsedInterval 最后使用时间的更新间隔，避免每个请求都写数据库
+	lastUsedInterval = time.Minute
+)
+
+// APIKeyUseCase 服务端集成 API key：创建、吊销与请求认证
+type APIKeyUseCase struct {
+	keyRepo        repository.APIKeyRepository
+	rateLimitRepo  repository.RateLimitRepository
+	config         *config.APIKeyConfig
+	platformConfig *config.AuthorizationConfig
+	logger         *zap.Logger
+}
+
+func NewAPIKeyUseCase(
+	keyRepo repository.APIKeyRepository,
+	rateLimitRepo repository.RateLimitRepository,
+	cfg *config.APIKeyConfig,
+	platformCfg *config.AuthorizationConfig,
+	logger *zap.Logger,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		keyRepo:        keyRepo,
+		rateLimitRepo:  rateLimitRepo,
+		config:         cfg,
+		platformConfig: platformCfg,
+		logger:         logger,
+	}
+}
+
+// Create 创建 API key，完整的 key 只在此时返回一次
+func (uc *APIKeyUseCase) Create(ctx context.Context, adminID string, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	scope, err := normalizeScope(strings.Join(req.Scopes, " "), isKnownAPIScope)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合作平台的 key 以平台身份查询授权状态，平台必须已接入
+	forPlatform := req.Platform != ""
+	for _, s := range strings.Fields(scope) {
+		forPlatform = forPlatform || s == entity.APIScopeAuthorizationsRead
+	}
+	if forPlatform {
+		if _, ok := uc.platformConfig.Platform(req.Platform); !ok {
+			return nil, ErrPlatformRequired
+		}
+	}
+
+	id, err := crypto.RandomHex(apiKeyIDBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	secret, err := crypto.RandomHex(24)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	prefix := entity.APIKeyPrefix + id
+	rawKey := prefix + "_" + secret
+
+	key := &entity.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   crypto.HashToken(rawKey),
+		Scope:     scope,
+		Platform:  req.Platform,
+		RateLimit: req.RateLimit,
+		CreatedBy: adminID,
+	}
+	if days := uc.config.ExpireDays(req.ExpiresInDays); days > 0 {
+		expiresAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := uc.keyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	uc.logger.Info("API key created",
+		zap.String("key_id", key.ID),
+		zap.String("prefix", key.Prefix),
+		zap.String("scope", key.Scope),
+		zap.String("platform", key.Platform),
+		zap.String("admin_id", adminID),
+	)
+
+	return &dto.CreateAPIKeyResponse{APIKey: key, Key: rawKey}, nil
+}
+
+// List 列出所有 API key
+func (uc *APIKeyUseCase) List(ctx context.Context) ([]*entity.APIKey, error) {
+	keys, err := uc.keyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	if keys == nil {
+		keys = []*entity.APIKey{}
+	}
+	return keys, nil
+}
+
+// Revoke 吊销 API key，立即生效
+func (uc *APIKeyUseCase) Revoke(ctx context.Context, adminID, id string) error {
+	key, err := uc.keyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("failed to find api key: %w", err)
+	}
+
+	if err := uc.keyRepo.Revoke(ctx, key.ID); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	uc.logger.Info("API key revoked",
+		zap.String("key_id", key.ID),
+		zap.String("prefix", key.Prefix),
+		zap.String("admin_id", adminID),
+	)
+	return nil
+}
+
+// AuthenticateAPIKey 校验 API key 的摘要、有效期、scope 与限流，并记录最后使用时间
+// scope 为空时只校验 key 本身
+func (uc *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, rawKey, ip, scope string) (*entity.APIKey, error) {
+	// 1. 按前缀查找，常量时间比较摘要
+	prefixLen := len(entity.APIKeyPrefix) + 2*apiKeyIDBytes
+	if len(rawKey) <= prefixLen+1 || !strings.HasPrefix(rawKey, entity.APIKeyPrefix) || rawKey[prefixLen] != '_' {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := uc.keyRepo.FindByPrefix(ctx, rawKey[:prefixLen])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(crypto.HashToken(rawKey))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	if !key.IsActive() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// 2. scope
+	if scope != "" && !key.HasScope(scope) {
+		return nil, ErrInsufficientScope
+	}
+
+	// 3. 每个 key 独立限流
+	allowed, err := uc.rateLimitRepo.Allow(ctx, "apikey:"+key.ID, uc.config.RateLimit(key.RateLimit), time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if !allowed {
+		return nil, ErrRateLimited
+	}
+
+	// 4. 最后使用时间，写入失败不影响请求
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval || key.LastUsedIP != ip {
+		if err := uc.keyRepo.TouchLastUsed(ctx, key.ID, ip, now); err != nil {
+			uc.logger.Warn("Failed to record api key usage", zap.String("key_id", key.ID), zap.Error(err))
+		}
+	}
+
+	return key, nil
+}
+
+func isKnownAPIScope(scope string) bool {
+	for _, s := range entity.KnownAPIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
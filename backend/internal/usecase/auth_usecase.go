@@ -4,190 +4,945 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dedata/dedata-backend/config"
 	"github.com/dedata/dedata-backend/internal/domain/entity"
 	"github.com/dedata/dedata-backend/internal/domain/repository"
 	dbRepo "github.com/dedata/dedata-backend/internal/infrastructure/database"
 	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
 	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/dedata/dedata-backend/pkg/eip712"
 	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/dedata/dedata-backend/pkg/pow"
+	"github.com/dedata/dedata-backend/pkg/siwe"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrSessionNotFound 会话不存在或不属于当前用户
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrAccountDisabled 账户已被暂停或拉黑
+	ErrAccountDisabled = errors.New("account is suspended or blacklisted")
+
+	// ErrAccountOnOtherChain 地址已在其他链上注册，需要从已有账户绑定
+	ErrAccountOnOtherChain = errors.New("wallet address is registered on another chain, link it from the existing account")
+
+	// ErrUnsupportedChainID 链 ID 不在允许列表中
+	ErrUnsupportedChainID = errors.New("unsupported chain id")
+)
+
 type AuthUseCase struct {
-	authRepo repository.AuthRepository
-	userRepo repository.UserRepository
-	jwtMgr   *pkgJWT.JWTManager
-	logger   *zap.Logger
+	challenges  repository.ChallengeStore
+	security    *SecurityUseCase
+	userRepo    repository.UserRepository
+	walletRepo  repository.WalletRepository
+	tokenRepo   repository.TokenRepository
+	sessionRepo repository.SessionRepository
+	jwtMgr      *pkgJWT.JWTManager
+	sigVerifier crypto.SignatureVerifier
+	chains      *chain.Registry
+	config      *config.AuthConfig
+	logger      *zap.Logger
 }
 
 func NewAuthUseCase(
-	authRepo repository.AuthRepository,
+	challenges repository.ChallengeStore,
+	security *SecurityUseCase,
 	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	tokenRepo repository.TokenRepository,
+	sessionRepo repository.SessionRepository,
 	jwtMgr *pkgJWT.JWTManager,
+	sigVerifier crypto.SignatureVerifier,
+	chains *chain.Registry,
+	cfg *config.AuthConfig,
 	logger *zap.Logger,
 ) *AuthUseCase {
 	return &AuthUseCase{
-		authRepo: authRepo,
-		userRepo: userRepo,
-		jwtMgr:   jwtMgr,
-		logger:   logger,
+		challenges:  challenges,
+		security:    security,
+		userRepo:    userRepo,
+		walletRepo:  walletRepo,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		jwtMgr:      jwtMgr,
+		sigVerifier: sigVerifier,
+		chains:      chains,
+		config:      cfg,
+		logger:      logger,
 	}
 }
 
-// GenerateNonce 生成 nonce
-func (uc *AuthUseCase) GenerateNonce(ctx context.Context, req *dto.NonceRequest) (*dto.NonceResponse, error) {
-	// 1. 生成随机 nonce
+// GenerateNonce 生成 nonce 及 EIP-4361 / CAIP-122 登录消息
+// 同一地址或 IP 未使用的 nonce 达到上限时返回 repository.ErrTooManyChallenges
+func (uc *AuthUseCase) GenerateNonce(ctx context.Context, req *dto.NonceRequest, client *dto.ClientInfo) (*dto.NonceResponse, error) {
+	// 0. 被锁定的地址 / IP 或请求过于频繁的 IP 不再签发 nonce
+	if err := uc.security.CheckNonce(ctx, req.Address, client.IP); err != nil {
+		return nil, err
+	}
+
+	// 1. 确定链家族，校验地址和链 ID
+	family, err := walletFamily(uc.chains, uc.config, req.Namespace, req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := family.NormalizeAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := walletChainID(uc.config, family.Namespace(), req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 生成随机 nonce，按该 IP 的近期请求量确定工作量证明难度
 	nonce, err := crypto.GenerateNonce()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	// 2. 创建 challenge (简化版，只存储必要信息)
-	now := time.Now()
-	expiresAt := now.Add(5 * time.Minute)
+	difficulty, err := uc.security.PoWDifficulty(ctx, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 创建 challenge，时间截断到秒，与消息中的 RFC3339 时间保持一致
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(uc.config.NonceLifetime())
 
 	challenge := &entity.LoginChallenge{
-		Address:   req.Address,
-		Nonce:     nonce,
-		Used:      false,
-		ExpiresAt: expiresAt,
+		Address:       address,
+		Nonce:         nonce,
+		Namespace:     string(family.Namespace()),
+		ChainID:       chainID,
+		Domain:        uc.config.Domain,
+		URI:           uc.config.URI,
+		IPAddress:     client.IP,
+		PoWDifficulty: difficulty,
+		Used:          false,
+		IssuedAt:      now,
+		ExpiresAt:     expiresAt,
 	}
 
-	if err := uc.authRepo.CreateChallenge(ctx, challenge); err != nil {
+	if err := uc.challenges.SaveChallenge(ctx, challenge); err != nil {
+		if errors.Is(err, repository.ErrTooManyChallenges) {
+			uc.logger.Warn("Too many outstanding nonces",
+				zap.String("address", address),
+				zap.String("ip", client.IP),
+			)
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create challenge: %w", err)
 	}
 
-	// 3. 返回 nonce (前端自己构造消息)
+	// 4. 构造待签名的 SIWE 消息 (personal_sign / signMessage)，EVM 钱包另外提供等价的 EIP-712 typed data
+	message := &siwe.Message{
+		Domain:         challenge.Domain,
+		Blockchain:     family.Blockchain(),
+		Address:        address,
+		Statement:      uc.config.Statement,
+		URI:            challenge.URI,
+		Version:        siwe.Version,
+		ChainID:        chainID,
+		Nonce:          nonce,
+		IssuedAt:       now,
+		ExpirationTime: &expiresAt,
+	}
+
+	var typedData *apitypes.TypedData
+	if family.Namespace() == chain.EIP155 {
+		td := uc.loginTypedData(challenge)
+		typedData = &td
+	}
+
+	var powChallenge *dto.PoWChallenge
+	if difficulty > 0 {
+		powChallenge = &dto.PoWChallenge{
+			Algorithm:  pow.Algorithm,
+			Seed:       nonce,
+			Difficulty: difficulty,
+		}
+	}
+
 	return &dto.NonceResponse{
-		Nonce: nonce,
+		Nonce:     nonce,
+		Message:   message.String(),
+		TypedData: typedData,
+		PoW:       powChallenge,
+		IssuedAt:  now.Format(time.RFC3339),
+		ExpiresAt: expiresAt.Format(time.RFC3339),
 	}, nil
 }
 
 // VerifySignature 验证签名并登录/注册
-func (uc *AuthUseCase) VerifySignature(ctx context.Context, req *dto.VerifyRequest) (*dto.AuthResponse, error) {
+func (uc *AuthUseCase) VerifySignature(ctx context.Context, req *dto.VerifyRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
 	uc.logger.Info("VerifySignature called",
 		zap.String("address", req.Address),
 		zap.String("nonce", req.Nonce),
-		zap.String("signature", req.Signature),
-		zap.String("message", req.Message),
+		zap.String("signature_type", req.SignatureType),
 	)
 
-	// 1. 查找 challenge
-	challenge, err := uc.authRepo.FindChallengeByNonce(ctx, req.Nonce)
+	// 0. 多次验签失败的地址 / IP 在锁定期间直接拒绝
+	if err := uc.security.CheckVerify(ctx, req.Address, client.IP); err != nil {
+		return nil, err
+	}
+
+	// 1-5. 按签名类型取出 challenge 并校验签名；challenge 在校验前即被消费，校验失败后需要重新获取 nonce
+	// challenge 被消费后即使校验失败也会返回，用于确定失败计入哪个地址
+	var challenge *entity.LoginChallenge
+	var err error
+	switch req.SignatureType {
+	case "", dto.SignatureTypePersonalSign:
+		challenge, err = uc.verifySIWE(ctx, req)
+	case dto.SignatureTypeEIP712:
+		challenge, err = uc.verifyTypedData(ctx, req)
+	default:
+		err = fmt.Errorf("unsupported signature type: %s", req.SignatureType)
+	}
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			uc.logger.Warn("Nonce not found", zap.String("nonce", req.Nonce))
-			return nil, fmt.Errorf("nonce not found or expired")
+		// req.Address 由客户端提供且未经认证，只在消费了签发给该地址的 challenge 后才计入地址维度，
+		// 否则任何人都能用伪造的 nonce 锁定他人钱包；没有 challenge 时只计入 IP
+		address := ""
+		if challenge != nil {
+			address = challenge.Address
 		}
-		return nil, fmt.Errorf("failed to find challenge: %w", err)
+		uc.security.RecordFailure(ctx, address, client.IP, err)
+		return nil, err
 	}
+	uc.security.RecordSuccess(ctx, challenge.Address, client.IP)
 
-	uc.logger.Info("Challenge found",
-		zap.String("challenge_address", challenge.Address),
-		zap.String("challenge_nonce", challenge.Nonce),
-		zap.Time("expires_at", challenge.ExpiresAt),
-		zap.Bool("used", challenge.Used),
-	)
+	uc.logger.Info("Signature verified successfully")
 
-	// 2. 验证 challenge
-	if challenge.IsExpired() {
-		uc.logger.Warn("Nonce expired", zap.String("nonce", req.Nonce))
-		return nil, fmt.Errorf("nonce has expired")
+	// 6. 查找或创建用户 (任一已绑定钱包都解析到同一用户)
+	user, err := uc.resolveUser(ctx, challenge, req)
+	if err != nil {
+		return nil, err
 	}
 
-	if challenge.IsUsed() {
-		uc.logger.Warn("Nonce already used", zap.String("nonce", req.Nonce))
-		return nil, fmt.Errorf("nonce has already been used")
+	// 7. 被暂停或拉黑的用户禁止登录
+	if !user.IsActive() {
+		uc.logger.Warn("Login rejected for disabled user",
+			zap.String("user_id", user.ID),
+			zap.String("status", string(user.Status)),
+		)
+		return nil, ErrAccountDisabled
 	}
 
-	// 3. 验证地址匹配
-	if strings.ToLower(challenge.Address) != strings.ToLower(req.Address) {
-		uc.logger.Warn("Address mismatch",
-			zap.String("challenge_address", challenge.Address),
-			zap.String("request_address", req.Address),
-		)
-		return nil, fmt.Errorf("address mismatch")
+	// 8. 创建新的登录会话并签发 access / refresh token
+	return uc.StartSession(ctx, user, client, entity.SessionScopeFull)
+}
+
+// StartSession 为已验证身份的用户创建登录会话并签发 access / refresh token
+func (uc *AuthUseCase) StartSession(ctx context.Context, user *entity.User, client *dto.ClientInfo, scope entity.SessionScope) (*dto.AuthResponse, error) {
+	now := time.Now()
+	session := &entity.Session{
+		UserID:     user.ID,
+		Scope:      scope,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(uc.jwtMgr.RefreshTTL()),
 	}
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return uc.issueTokens(ctx, user, session.ID, scope)
+}
 
-	// 4. 构造期望的消息（如果前端没传，就自己构造）
-	expectedMessage := req.Message
-	if expectedMessage == "" {
-		expectedMessage = fmt.Sprintf("Sign this message to authenticate with DeData Protocol\n\nNonce: %s", req.Nonce)
+// Refresh 使用 refresh token 换取新的 access / refresh token (轮换)
+// 已使用过的 refresh token 再次出现视为泄露，整个 token family 立即吊销
+func (uc *AuthUseCase) Refresh(ctx context.Context, req *dto.RefreshRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
+	tokenHash := crypto.HashToken(req.RefreshToken)
+
+	// 1. 查找 refresh token
+	token, err := uc.tokenRepo.FindRefreshToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			return nil, fmt.Errorf("invalid or expired refresh token")
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
 	}
 
-	uc.logger.Info("Verifying signature",
-		zap.String("message", expectedMessage),
-		zap.String("signature", req.Signature),
-		zap.String("address", req.Address),
-	)
+	if token.IsExpired() {
+		return nil, fmt.Errorf("invalid or expired refresh token")
+	}
 
-	// 5. 验证签名
-	valid, err := crypto.VerifySignature(expectedMessage, req.Signature, req.Address)
+	// 2. 检查 token family 是否已吊销
+	revoked, err := uc.tokenRepo.IsSessionRevoked(ctx, token.SessionID)
 	if err != nil {
-		uc.logger.Error("Signature verification error", zap.Error(err))
-		return nil, fmt.Errorf("failed to verify signature: %w", err)
+		return nil, fmt.Errorf("failed to check session: %w", err)
+	}
+	if revoked {
+		return nil, fmt.Errorf("session has been revoked")
 	}
 
-	if !valid {
-		// 尝试恢复地址看看签名是否有效
-		recoveredAddr, recErr := crypto.RecoverAddress(expectedMessage, req.Signature)
-		uc.logger.Warn("Invalid signature",
-			zap.String("expected_address", req.Address),
-			zap.String("recovered_address", recoveredAddr),
-			zap.Error(recErr),
+	// 3. 原子地消费 refresh token，重复使用则吊销整个 family
+	first, err := uc.tokenRepo.ConsumeRefreshToken(ctx, tokenHash, time.Until(token.ExpiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	if !first {
+		uc.logger.Warn("Refresh token reuse detected, revoking session",
+			zap.String("user_id", token.UserID),
+			zap.String("session_id", token.SessionID),
 		)
-		return nil, fmt.Errorf("invalid signature")
+		if err := uc.revokeSession(ctx, token.SessionID); err != nil {
+			uc.logger.Error("Failed to revoke session", zap.Error(err))
+		}
+		return nil, fmt.Errorf("refresh token reuse detected")
 	}
 
-	uc.logger.Info("Signature verified successfully")
+	// 更新会话最后活跃信息
+	if err := uc.sessionRepo.Touch(ctx, token.SessionID, client.IP, client.UserAgent, time.Now().Add(uc.jwtMgr.RefreshTTL())); err != nil {
+		uc.logger.Warn("Failed to update session last seen", zap.String("session_id", token.SessionID), zap.Error(err))
+	}
+
+	// 4. 重新读取用户，确保角色等信息是最新的
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		if err := uc.revokeSession(ctx, token.SessionID); err != nil {
+			uc.logger.Error("Failed to revoke session", zap.Error(err))
+		}
+		return nil, ErrAccountDisabled
+	}
+
+	scope := token.Scope
+	if scope == "" {
+		scope = entity.SessionScopeFull
+	}
+	return uc.issueTokens(ctx, user, token.SessionID, scope)
+}
+
+// Logout 吊销当前会话的 refresh token family，并将当前 access token 加入黑名单
+func (uc *AuthUseCase) Logout(ctx context.Context, claims *pkgJWT.Claims) error {
+	if claims.SessionID != "" {
+		if err := uc.revokeSession(ctx, claims.SessionID); err != nil {
+			return err
+		}
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := uc.tokenRepo.DenylistAccessToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+			return fmt.Errorf("failed to denylist access token: %w", err)
+		}
+	}
+
+	uc.logger.Info("User logged out",
+		zap.String("user_id", claims.UserID),
+		zap.String("session_id", claims.SessionID),
+	)
+
+	return nil
+}
 
-	// 6. 标记 nonce 为已使用
-	if err := uc.authRepo.MarkChallengeAsUsed(ctx, req.Nonce); err != nil {
-		uc.logger.Error("Failed to mark challenge as used", zap.Error(err))
+// ListSessions 列出用户所有有效的登录会话
+func (uc *AuthUseCase) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.SessionInfo, error) {
+	sessions, err := uc.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	result := make([]*dto.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &dto.SessionInfo{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+			Scope:      string(session.Scope),
+			Current:    session.ID == currentSessionID,
+		})
 	}
 
-	// 7. 查找或创建用户
-	user, err := uc.userRepo.FindByAddress(ctx, req.Address)
+	return result, nil
+}
+
+// RevokeUserSession 吊销用户的指定会话
+func (uc *AuthUseCase) RevokeUserSession(ctx context.Context, userID, sessionID string) error {
+	session, err := uc.sessionRepo.FindByID(ctx, sessionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// 创建新用户
-			user = &entity.User{
-				DID:              dbRepo.GenerateDID(req.Address),
-				WalletAddress:    req.Address,
-				ChainID:          1, // 默认 Ethereum mainnet，可以从前端传入
-				Role:             entity.RoleUser,
-				Status:           entity.StatusActive,
-				ProfileCompleted: false,
-			}
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+
+	// 只能吊销自己的会话
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	if err := uc.revokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Session revoked",
+		zap.String("user_id", userID),
+		zap.String("session_id", sessionID),
+	)
+
+	return nil
+}
+
+// RevokeAllSessions 吊销用户所有有效会话 (账户被禁用时调用)
+func (uc *AuthUseCase) RevokeAllSessions(ctx context.Context, userID string) error {
+	sessions, err := uc.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		if err := uc.revokeSession(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeSession 在数据库中标记会话吊销，并写入 Redis 供认证中间件快速拒绝
+func (uc *AuthUseCase) revokeSession(ctx context.Context, sessionID string) error {
+	if err := uc.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := uc.tokenRepo.RevokeSession(ctx, sessionID, uc.jwtMgr.RefreshTTL()); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// Authenticate 校验 access token 的签名、有效期以及是否已被吊销
+// 同时检查用户当前状态，并以数据库中的角色覆盖 token 中的角色，使封禁和角色变更立即生效
+func (uc *AuthUseCase) Authenticate(ctx context.Context, tokenString string) (*pkgJWT.Claims, error) {
+	claims, err := uc.jwtMgr.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ID != "" {
+		denied, err := uc.tokenRepo.IsAccessTokenDenylisted(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token denylist: %w", err)
+		}
+		if denied {
+			return nil, fmt.Errorf("token has been revoked")
+		}
+	}
+
+	if claims.SessionID != "" {
+		revoked, err := uc.tokenRepo.IsSessionRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check session: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("session has been revoked")
+		}
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		return nil, ErrAccountDisabled
+	}
+	claims.Role = string(user.Role)
+
+	return claims, nil
+}
+
+// resolveUser 通过 CAIP-10 账户查找用户，不存在时注册新用户并绑定为主钱包
+// 链 ID 取自已签名的登录消息 (与 challenge 一致)
+func (uc *AuthUseCase) resolveUser(ctx context.Context, challenge *entity.LoginChallenge, req *dto.VerifyRequest) (*entity.User, error) {
+	account, err := uc.chains.Account(chain.Namespace(challenge.Namespace), challenge.ChainID, challenge.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. CAIP-10 精确匹配
+	wallet, err := uc.walletRepo.FindByAccount(ctx, account.String())
+	if err == nil {
+		if err := uc.markKeyVerified(ctx, wallet, challenge, req); err != nil {
+			return nil, err
+		}
+		return uc.findUser(ctx, wallet.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	// 2. 同一地址已绑定在其他链上，或是尚未回填 CAIP-10 的历史记录
+	wallet, err = uc.walletRepo.FindByAddress(ctx, challenge.Address)
+	if err == nil {
+		return uc.resolveKnownAddress(ctx, wallet, account, challenge, req)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	// 兼容尚未写入 user_wallets 的历史用户
+	user, err := uc.userRepo.FindByAddress(ctx, challenge.Address)
+	if err == nil {
+		return uc.resolveLegacyUser(ctx, user, account, challenge, req)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 3. 创建新用户，非 EVM 钱包没有数字链 ID
+	chainID := evmChainID(challenge)
+	user = &entity.User{
+		DID:              dbRepo.GenerateDID(account.Address),
+		Account:          account.String(),
+		WalletAddress:    account.Address,
+		ChainID:          int(chainID),
+		Namespace:        challenge.Namespace,
+		Role:             entity.RoleUser,
+		Status:           entity.StatusActive,
+		ProfileCompleted: false,
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := uc.walletRepo.Create(ctx, &entity.UserWallet{
+		UserID:      user.ID,
+		Account:     account.String(),
+		Address:     account.Address,
+		ChainID:     chainID,
+		Namespace:   challenge.Namespace,
+		IsPrimary:   true,
+		KeyVerified: uc.provesKeyControl(ctx, challenge, req),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	uc.logger.Info("New user registered", zap.String("account", account.String()))
+	return user, nil
+}
 
-			if err := uc.userRepo.Create(ctx, user); err != nil {
-				return nil, fmt.Errorf("failed to create user: %w", err)
+// resolveKnownAddress 处理地址已绑定但 CAIP-10 标识不匹配的登录
+//   - 同一链上的历史记录缺少或未规范化 CAIP-10 标识: 回填
+//   - 同一地址在其他链上: 见 linkOtherChain
+func (uc *AuthUseCase) resolveKnownAddress(ctx context.Context, wallet *entity.UserWallet, account caip.AccountID, challenge *entity.LoginChallenge, req *dto.VerifyRequest) (*entity.User, error) {
+	user, err := uc.findUser(ctx, wallet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sameChain := strings.EqualFold(wallet.Account, account.String())
+	if wallet.Account == "" {
+		sameChain = wallet.Namespace == challenge.Namespace && wallet.ChainID == evmChainID(challenge)
+	}
+	if sameChain {
+		if err := uc.walletRepo.UpdateAccount(ctx, wallet.ID, account.String()); err != nil {
+			return nil, fmt.Errorf("failed to update wallet account: %w", err)
+		}
+		if err := uc.markKeyVerified(ctx, wallet, challenge, req); err != nil {
+			return nil, err
+		}
+		if wallet.IsPrimary && user.Account != account.String() {
+			user.Account = account.String()
+			if err := uc.userRepo.Update(ctx, user); err != nil {
+				return nil, fmt.Errorf("failed to update user account: %w", err)
 			}
+		}
+		return user, nil
+	}
+
+	if err := uc.linkOtherChain(ctx, user, account, challenge, req); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// resolveLegacyUser 处理尚未写入 user_wallets 的历史用户，补写主钱包记录
+// 主钱包注册在其他链上时与 resolveKnownAddress 相同，由 linkOtherChain 决定是否绑定
+func (uc *AuthUseCase) resolveLegacyUser(ctx context.Context, user *entity.User, account caip.AccountID, challenge *entity.LoginChallenge, req *dto.VerifyRequest) (*entity.User, error) {
+	primary, err := uc.registeredAccount(user)
+	if err != nil {
+		// 缺少链信息的历史记录，视为注册在本次登录的链上
+		primary = account
+	}
+	sameChain := primary.String() == account.String()
+
+	if !sameChain {
+		if err := uc.linkOtherChain(ctx, user, account, challenge, req); err != nil {
+			return nil, err
+		}
+	}
 
-			uc.logger.Info("New user registered", zap.String("address", req.Address))
-		} else {
-			return nil, fmt.Errorf("failed to find user: %w", err)
+	if err := uc.walletRepo.Create(ctx, &entity.UserWallet{
+		UserID:    user.ID,
+		Account:   primary.String(),
+		Address:   primary.Address,
+		ChainID:   evmReference(primary.Chain.Namespace, primary.Chain.Reference),
+		Namespace: primary.Chain.Namespace,
+		IsPrimary: true,
+		// 本次签名只在同一链上证明主钱包持有私钥
+		KeyVerified: sameChain && uc.provesKeyControl(ctx, challenge, req),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	if user.Account != primary.String() {
+		user.Account = primary.String()
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to update user account: %w", err)
 		}
 	}
+	return user, nil
+}
+
+// registeredAccount 历史用户主钱包的 CAIP-10 标识，缺少时由注册时的链家族和链 ID 推出
+func (uc *AuthUseCase) registeredAccount(user *entity.User) (caip.AccountID, error) {
+	if user.Account != "" {
+		return caip.ParseAccountID(user.Account)
+	}
+	reference := strconv.Itoa(user.ChainID)
+	if user.Namespace == string(chain.Solana) {
+		reference = uc.config.SolanaChainID
+	}
+	return uc.chains.Account(chain.Namespace(user.Namespace), reference, user.WalletAddress)
+}
+
+// linkOtherChain 已注册的地址在另一条链上登录: 仅当开启 auth.auto_link_chains 且签名证明持有私钥 (EOA / ed25519) 时，
+// 将新链上的账户作为新钱包绑定到同一用户；合约钱包在不同链上可能由不同的人控制，一律拒绝
+func (uc *AuthUseCase) linkOtherChain(ctx context.Context, user *entity.User, account caip.AccountID, challenge *entity.LoginChallenge, req *dto.VerifyRequest) error {
+	if !uc.config.AutoLinkChains || !uc.provesKeyControl(ctx, challenge, req) {
+		uc.logger.Warn("Sign-in from a wallet registered on another chain",
+			zap.String("user_id", user.ID),
+			zap.String("account", account.String()),
+			zap.Bool("auto_link", uc.config.AutoLinkChains),
+		)
+		return ErrAccountOnOtherChain
+	}
+
+	if err := uc.walletRepo.Create(ctx, &entity.UserWallet{
+		UserID:      user.ID,
+		Account:     account.String(),
+		Address:     account.Address,
+		ChainID:     evmChainID(challenge),
+		Namespace:   challenge.Namespace,
+		KeyVerified: true,
+	}); err != nil {
+		return fmt.Errorf("failed to create wallet: %w", err)
+	}
 
-	// 8. 生成 JWT
-	token, err := uc.jwtMgr.GenerateToken(user.ID, user.WalletAddress, user.DID, string(user.Role))
+	uc.logger.Info("Wallet linked on another chain",
+		zap.String("user_id", user.ID),
+		zap.String("account", account.String()),
+	)
+	return nil
+}
+
+// markKeyVerified 登录签名证明持有私钥时记录到钱包上 (此前按合约钱包处理或在记录之前绑定的钱包)
+func (uc *AuthUseCase) markKeyVerified(ctx context.Context, wallet *entity.UserWallet, challenge *entity.LoginChallenge, req *dto.VerifyRequest) error {
+	if wallet.KeyVerified || !uc.provesKeyControl(ctx, challenge, req) {
+		return nil
+	}
+	if err := uc.walletRepo.MarkKeyVerified(ctx, wallet.ID); err != nil {
+		return fmt.Errorf("failed to update wallet: %w", err)
+	}
+	wallet.KeyVerified = true
+	return nil
+}
+
+// provesKeyControl 签名是否证明持有地址私钥，从而控制该地址在同一链家族所有链上的账户
+// Solana 地址即 ed25519 公钥；EVM 仅当签名可通过 ecrecover 恢复出地址 (EOA) 时成立
+func (uc *AuthUseCase) provesKeyControl(ctx context.Context, challenge *entity.LoginChallenge, req *dto.VerifyRequest) bool {
+	if challenge.Namespace != string(chain.EIP155) {
+		return true
+	}
+
+	eoa := crypto.NewECDSAVerifier()
+	var valid bool
+	var err error
+	if req.SignatureType == dto.SignatureTypeEIP712 {
+		valid, err = eip712.Verify(ctx, eoa, uc.loginTypedData(challenge), req.Signature, challenge.Address)
+	} else {
+		valid, err = crypto.VerifyPersonalSign(ctx, eoa, evmChainID(challenge), req.Message, req.Signature, challenge.Address)
+	}
+	return err == nil && valid
+}
+
+func (uc *AuthUseCase) findUser(ctx context.Context, userID string) (*entity.User, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// issueTokens 签发 access token 和新的 refresh token
+func (uc *AuthUseCase) issueTokens(ctx context.Context, user *entity.User, sessionID string, scope entity.SessionScope) (*dto.AuthResponse, error) {
+	accessToken, err := uc.jwtMgr.GenerateToken(user.ID, user.WalletAddress, user.DID, string(user.Role), sessionID, string(scope))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	refreshToken, err := crypto.RandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	if err := uc.tokenRepo.SaveRefreshToken(ctx, &entity.RefreshToken{
+		TokenHash: crypto.HashToken(refreshToken),
+		SessionID: sessionID,
+		UserID:    user.ID,
+		Scope:     scope,
+		ExpiresAt: now.Add(uc.jwtMgr.RefreshTTL()),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
 	return &dto.AuthResponse{
-		Token: token,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    now.Add(uc.jwtMgr.AccessTTL()).UTC().Format(time.RFC3339),
+		Scope:        string(scope),
 		User: &dto.UserInfo{
 			ID:               user.ID,
+			Account:          user.Account,
 			Address:          user.WalletAddress,
+			Namespace:        user.Namespace,
 			DID:              user.DID,
 			Role:             string(user.Role),
 			ProfileCompleted: user.ProfileCompleted,
 		},
 	}, nil
 }
+
+// verifySIWE 校验 EIP-4361 / CAIP-122 消息及钱包对消息原文的签名，返回对应的 challenge (已消费时校验失败也返回)
+func (uc *AuthUseCase) verifySIWE(ctx context.Context, req *dto.VerifyRequest) (*entity.LoginChallenge, error) {
+	if req.Message == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	// 1. 解析 SIWE 消息
+	message, err := siwe.Parse(req.Message)
+	if err != nil {
+		uc.logger.Warn("Invalid SIWE message", zap.Error(err))
+		return nil, fmt.Errorf("invalid sign-in message: %w", err)
+	}
+
+	if req.Nonce != "" && req.Nonce != message.Nonce {
+		return nil, fmt.Errorf("nonce mismatch")
+	}
+
+	// 2-3. 原子地取出 challenge，保证只能使用一次
+	challenge, err := uc.consumeChallenge(ctx, message.Nonce, req.PoWSolution)
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := uc.chains.Get(chain.Namespace(challenge.Namespace))
+	if err != nil {
+		return challenge, err
+	}
+
+	// 4. 消息内容必须与 challenge 严格一致
+	if err := uc.checkMessage(message, challenge, family, req.Address); err != nil {
+		uc.logger.Warn("SIWE message does not match challenge",
+			zap.String("nonce", message.Nonce),
+			zap.Error(err),
+		)
+		return challenge, err
+	}
+
+	// 5. 验证签名 (EVM: EOA 或 EIP-1271 合约钱包；Solana: ed25519)
+	valid, err := family.VerifyMessage(ctx, message.ChainID, req.Message, req.Signature, message.Address)
+	if err != nil {
+		uc.logger.Error("Signature verification error", zap.Error(err))
+		return challenge, fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	if !valid {
+		uc.logger.Warn("Invalid signature", zap.String("expected_address", message.Address))
+		return challenge, fmt.Errorf("invalid signature")
+	}
+
+	return challenge, nil
+}
+
+// verifyTypedData 校验 EIP-712 登录签名，typed data 由服务端根据 challenge 重建 (已消费时校验失败也返回 challenge)
+func (uc *AuthUseCase) verifyTypedData(ctx context.Context, req *dto.VerifyRequest) (*entity.LoginChallenge, error) {
+	if req.Nonce == "" {
+		return nil, fmt.Errorf("nonce is required")
+	}
+
+	challenge, err := uc.consumeChallenge(ctx, req.Nonce, req.PoWSolution)
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge.Namespace != string(chain.EIP155) {
+		return challenge, fmt.Errorf("eip712 signatures are only supported for eip155 wallets")
+	}
+
+	if !strings.EqualFold(challenge.Address, req.Address) {
+		return challenge, fmt.Errorf("address mismatch")
+	}
+
+	valid, err := eip712.Verify(ctx, uc.sigVerifier, uc.loginTypedData(challenge), req.Signature, challenge.Address)
+	if err != nil {
+		uc.logger.Error("Typed data verification error", zap.Error(err))
+		return challenge, fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	if !valid {
+		uc.logger.Warn("Invalid typed data signature", zap.String("expected_address", challenge.Address))
+		return challenge, fmt.Errorf("invalid signature")
+	}
+
+	return challenge, nil
+}
+
+// consumeChallenge 原子地取出 challenge，并发提交同一 nonce 时只有一个请求能取到
+// 要求工作量证明的 challenge 在验签前先检查 solution，避免为无效请求做签名验证 (可能涉及 EIP-1271 链上调用)
+func (uc *AuthUseCase) consumeChallenge(ctx context.Context, nonce, powSolution string) (*entity.LoginChallenge, error) {
+	challenge, err := uc.challenges.ConsumeChallenge(ctx, nonce)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			uc.logger.Warn("Nonce not found, expired or already used", zap.String("nonce", nonce))
+			return nil, fmt.Errorf("nonce not found, expired or already used")
+		}
+		return nil, fmt.Errorf("failed to find challenge: %w", err)
+	}
+
+	// 存储层按 TTL 过期，这里再按签发的过期时间兜底
+	if challenge.IsExpired() {
+		uc.logger.Warn("Nonce expired", zap.String("nonce", nonce))
+		return nil, fmt.Errorf("nonce has expired")
+	}
+
+	if err := uc.security.VerifyPoW(challenge, powSolution); err != nil {
+		uc.logger.Warn("Invalid proof of work",
+			zap.String("nonce", nonce),
+			zap.Int("difficulty", challenge.PoWDifficulty),
+		)
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// loginTypedData 根据 challenge 构造 EIP-712 登录消息
+func (uc *AuthUseCase) loginTypedData(challenge *entity.LoginChallenge) apitypes.TypedData {
+	domain := eip712.Domain{
+		Name:              uc.config.EIP712.Name,
+		Version:           uc.config.EIP712.Version,
+		ChainID:           evmChainID(challenge),
+		VerifyingContract: uc.config.EIP712.VerifyingContract,
+	}
+
+	return eip712.NewTypedData(domain, eip712.LoginSchema, apitypes.TypedDataMessage{
+		"domain":         challenge.Domain,
+		"wallet":         challenge.Address,
+		"statement":      uc.config.Statement,
+		"uri":            challenge.URI,
+		"chainId":        challenge.ChainID,
+		"nonce":          challenge.Nonce,
+		"issuedAt":       challenge.IssuedAt.UTC().Format(time.RFC3339),
+		"expirationTime": challenge.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// checkMessage 校验 SIWE 消息字段与服务端保存的 challenge 一致
+func (uc *AuthUseCase) checkMessage(message *siwe.Message, challenge *entity.LoginChallenge, family chain.Family, address string) error {
+	if message.Blockchain != family.Blockchain() {
+		return fmt.Errorf("blockchain mismatch")
+	}
+	normalized, err := family.NormalizeAddress(address)
+	if err != nil || normalized != challenge.Address || message.Address != challenge.Address {
+		return fmt.Errorf("address mismatch")
+	}
+	if message.Domain != challenge.Domain {
+		return fmt.Errorf("domain mismatch")
+	}
+	if message.URI != challenge.URI {
+		return fmt.Errorf("uri mismatch")
+	}
+	if message.ChainID != challenge.ChainID {
+		return fmt.Errorf("chain id mismatch")
+	}
+	if !message.IssuedAt.Equal(challenge.IssuedAt) {
+		return fmt.Errorf("issued at mismatch")
+	}
+	if message.ExpirationTime == nil || !message.ExpirationTime.Equal(challenge.ExpiresAt) {
+		return fmt.Errorf("expiration time mismatch")
+	}
+	if err := message.ValidAt(time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// walletFamily 确定钱包的链家族 (登录和绑定钱包共用): 优先使用请求中的 namespace，否则按地址格式推断
+func walletFamily(chains *chain.Registry, cfg *config.AuthConfig, namespace, address string) (chain.Family, error) {
+	var family chain.Family
+	var err error
+	if namespace != "" {
+		family, err = chains.Get(chain.Namespace(namespace))
+	} else {
+		family, err = chains.Detect(address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if family.Namespace() == chain.Solana && !cfg.SolanaEnabled() {
+		return nil, fmt.Errorf("%w: %s", chain.ErrUnsupportedNamespace, chain.Solana)
+	}
+	return family, nil
+}
+
+// walletChainID 返回写入签名消息的 CAIP-2 reference
+// EVM 钱包可选择允许的链 ID，Solana 固定为配置的集群
+func walletChainID(cfg *config.AuthConfig, namespace chain.Namespace, requested int64) (string, error) {
+	if namespace == chain.Solana {
+		return cfg.SolanaChainID, nil
+	}
+
+	chainID := requested
+	if chainID == 0 {
+		chainID = cfg.ChainID
+	}
+	if !cfg.IsChainAllowed(chainID) {
+		return "", fmt.Errorf("%w: %d", ErrUnsupportedChainID, chainID)
+	}
+	return strconv.FormatInt(chainID, 10), nil
+}
+
+// evmChainID 返回 eip155 challenge 的数字链 ID，其他链家族返回 0
+func evmChainID(challenge *entity.LoginChallenge) int64 {
+	return evmReference(challenge.Namespace, challenge.ChainID)
+}
+
+// evmReference 将 eip155 的 CAIP-2 reference 转为数字链 ID，其他链家族返回 0
+func evmReference(namespace, reference string) int64 {
+	if namespace != string(chain.EIP155) {
+		return 0
+	}
+	// reference 在创建 challenge 时已校验为十进制链 ID
+	id, _ := strconv.ParseInt(reference, 10, 64)
+	return id
+}
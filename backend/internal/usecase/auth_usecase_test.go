@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/dedata/dedata-backend/pkg/siwe"
+	"go.uber.org/zap"
+)
+
+const testWallet = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+// signInMessage 返回与 challenge 一致的 SIWE 消息
+func signInMessage(challenge *entity.LoginChallenge) *siwe.Message {
+	expiresAt := challenge.ExpiresAt
+	return &siwe.Message{
+		Domain:         challenge.Domain,
+		Blockchain:     siwe.Ethereum,
+		Address:        challenge.Address,
+		URI:            challenge.URI,
+		Version:        siwe.Version,
+		ChainID:        challenge.ChainID,
+		Nonce:          challenge.Nonce,
+		IssuedAt:       challenge.IssuedAt,
+		ExpirationTime: &expiresAt,
+	}
+}
+
+func TestCheckMessage(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	challenge := &entity.LoginChallenge{
+		Address:   testWallet,
+		Nonce:     "a1B2c3D4e5F6",
+		Namespace: string(chain.EIP155),
+		ChainID:   "1",
+		Domain:    "dedata.io",
+		URI:       "https://dedata.io",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(5 * time.Minute),
+	}
+
+	evm := chain.NewEVM(crypto.NewECDSAVerifier())
+
+	tests := []struct {
+		name    string
+		modify  func(m *siwe.Message)
+		address string
+		wantErr string
+	}{
+		{"matching message", func(m *siwe.Message) {}, testWallet, ""},
+		{"wrong domain", func(m *siwe.Message) { m.Domain = "evil.example" }, testWallet, "domain mismatch"},
+		{"wrong uri", func(m *siwe.Message) { m.URI = "https://evil.example" }, testWallet, "uri mismatch"},
+		{"wrong chain", func(m *siwe.Message) { m.ChainID = "137" }, testWallet, "chain id mismatch"},
+		{"wrong blockchain", func(m *siwe.Message) { m.Blockchain = "Solana" }, testWallet, "blockchain mismatch"},
+		{"lowercase address", func(m *siwe.Message) {}, strings.ToLower(testWallet), ""},
+		{"other address", func(m *siwe.Message) {}, "0x0000000000000000000000000000000000000001", "address mismatch"},
+		{"issued at changed", func(m *siwe.Message) { m.IssuedAt = now.Add(-time.Hour) }, testWallet, "issued at mismatch"},
+		{"expiration removed", func(m *siwe.Message) { m.ExpirationTime = nil }, testWallet, "expiration time mismatch"},
+		{"not yet valid", func(m *siwe.Message) {
+			notBefore := now.Add(time.Hour)
+			m.NotBefore = &notBefore
+		}, testWallet, "not yet valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := signInMessage(challenge)
+			tt.modify(message)
+
+			err := (&AuthUseCase{}).checkMessage(message, challenge, evm, tt.address)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkMessage: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkMessage error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMessageExpired(t *testing.T) {
+	issuedAt := time.Now().UTC().Truncate(time.Second).Add(-10 * time.Minute)
+	challenge := &entity.LoginChallenge{
+		Address:   testWallet,
+		Nonce:     "a1B2c3D4e5F6",
+		Namespace: string(chain.EIP155),
+		ChainID:   "1",
+		Domain:    "dedata.io",
+		URI:       "https://dedata.io",
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(5 * time.Minute),
+	}
+
+	err := (&AuthUseCase{}).checkMessage(signInMessage(challenge), challenge, chain.NewEVM(crypto.NewECDSAVerifier()), testWallet)
+	if err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("checkMessage error = %v, want expired", err)
+	}
+}
+
+// memoryTokenRepo 内存中的 TokenRepository
+type memoryTokenRepo struct {
+	mu      sync.Mutex
+	tokens  map[string]*entity.RefreshToken
+	used    map[string]bool
+	revoked map[string]bool
+}
+
+func newMemoryTokenRepo() *memoryTokenRepo {
+	return &memoryTokenRepo{
+		tokens:  make(map[string]*entity.RefreshToken),
+		used:    make(map[string]bool),
+		revoked: make(map[string]bool),
+	}
+}
+
+func (r *memoryTokenRepo) SaveRefreshToken(_ context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memoryTokenRepo) FindRefreshToken(_ context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, repository.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (r *memoryTokenRepo) ConsumeRefreshToken(_ context.Context, tokenHash string, _ time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.used[tokenHash] {
+		return false, nil
+	}
+	r.used[tokenHash] = true
+	return true, nil
+}
+
+func (r *memoryTokenRepo) RevokeSession(_ context.Context, sessionID string, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[sessionID] = true
+	return nil
+}
+
+func (r *memoryTokenRepo) IsSessionRevoked(_ context.Context, sessionID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revoked[sessionID], nil
+}
+
+func (r *memoryTokenRepo) DenylistAccessToken(context.Context, string, time.Duration) error {
+	return nil
+}
+
+func (r *memoryTokenRepo) IsAccessTokenDenylisted(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// memorySessionRepo 只记录吊销的会话
+type memorySessionRepo struct {
+	repository.SessionRepository
+	revoked map[string]bool
+}
+
+func (r *memorySessionRepo) Touch(context.Context, string, string, string, time.Time) error {
+	return nil
+}
+
+func (r *memorySessionRepo) Revoke(_ context.Context, id string) error {
+	r.revoked[id] = true
+	return nil
+}
+
+// memoryUserRepo 只返回同一个用户
+type memoryUserRepo struct {
+	repository.UserRepository
+	user *entity.User
+}
+
+func (r *memoryUserRepo) FindByID(context.Context, string) (*entity.User, error) {
+	return r.user, nil
+}
+
+const testSessionID = "session-1"
+
+// newTestRefresh 返回已为 testSessionID 签发 token 的 AuthUseCase 及首个 refresh token
+func newTestRefresh(t *testing.T) (*AuthUseCase, *memoryTokenRepo, *memorySessionRepo, string) {
+	t.Helper()
+	jwtMgr, err := pkgJWT.NewJWTManager(&config.JWTConfig{Secret: "test-secret", AccessExpireMinute: 5, RefreshExpireHour: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := newMemoryTokenRepo()
+	sessions := &memorySessionRepo{revoked: make(map[string]bool)}
+	user := &entity.User{ID: "user-1", WalletAddress: testWallet, Status: entity.StatusActive, Role: entity.RoleUser}
+	uc := &AuthUseCase{
+		tokenRepo:   tokens,
+		sessionRepo: sessions,
+		userRepo:    &memoryUserRepo{user: user},
+		jwtMgr:      jwtMgr,
+		logger:      zap.NewNop(),
+	}
+
+	resp, err := uc.issueTokens(context.Background(), user, testSessionID, entity.SessionScopeFull)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	return uc, tokens, sessions, resp.RefreshToken
+}
+
+func refresh(uc *AuthUseCase, refreshToken string) (*dto.AuthResponse, error) {
+	return uc.Refresh(context.Background(), &dto.RefreshRequest{RefreshToken: refreshToken}, &dto.ClientInfo{IP: ownerIP})
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	uc, tokens, _, first := newTestRefresh(t)
+
+	resp, err := refresh(uc, first)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if resp.RefreshToken == first || resp.Token == "" {
+		t.Fatalf("Refresh did not rotate the refresh token")
+	}
+	if !tokens.used[crypto.HashToken(first)] {
+		t.Error("rotated refresh token was not marked as used")
+	}
+
+	// 新的 refresh token 属于同一会话，可以继续轮换
+	if _, err := refresh(uc, resp.RefreshToken); err != nil {
+		t.Fatalf("Refresh with rotated token: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	uc, tokens, sessions, first := newTestRefresh(t)
+
+	resp, err := refresh(uc, first)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// 已轮换的 token 再次出现视为泄露，整个 family 被吊销
+	if _, err := refresh(uc, first); err == nil {
+		t.Fatal("Refresh with an already rotated token succeeded")
+	}
+	if !tokens.revoked[testSessionID] || !sessions.revoked[testSessionID] {
+		t.Errorf("session not revoked after reuse: tokens=%v sessions=%v", tokens.revoked, sessions.revoked)
+	}
+
+	// 合法持有者手中最新的 token 也随之失效
+	if _, err := refresh(uc, resp.RefreshToken); err == nil {
+		t.Error("Refresh with the latest token succeeded after the family was revoked")
+	}
+}
+
+func TestRefreshRejectsRevokedSession(t *testing.T) {
+	uc, tokens, _, first := newTestRefresh(t)
+
+	if err := uc.revokeSession(context.Background(), testSessionID); err != nil {
+		t.Fatalf("revokeSession: %v", err)
+	}
+
+	if _, err := refresh(uc, first); err == nil {
+		t.Fatal("Refresh against a revoked session succeeded")
+	}
+	if tokens.used[crypto.HashToken(first)] {
+		t.Error("refresh token was consumed for a revoked session")
+	}
+	if _, err := refresh(uc, "unknown-token"); err == nil {
+		t.Error("Refresh with an unknown token succeeded")
+	}
+}
@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPlatformNotFound 平台未接入
+	ErrPlatformNotFound = errors.New("platform not found")
+
+	// ErrScopeNotAllowed scope 不存在或平台无权申请
+	ErrScopeNotAllowed = errors.New("scope is not allowed for this platform")
+
+	// ErrAuthorizationNotFound 授权不存在或不属于当前用户
+	ErrAuthorizationNotFound = errors.New("authorization not found")
+)
+
+// AuthorizationUseCase 平台数据访问授权：用户签名同意、查询、吊销，平台查询授权状态
+type AuthorizationUseCase struct {
+	authorizationRepo repository.AuthorizationRepository
+	consentRepo       repository.ConsentChallengeRepository
+	userRepo          repository.UserRepository
+	chains            *chain.Registry
+	authConfig        *config.AuthConfig
+	config            *config.AuthorizationConfig
+	logger            *zap.Logger
+}
+
+func NewAuthorizationUseCase(
+	authorizationRepo repository.AuthorizationRepository,
+	consentRepo repository.ConsentChallengeRepository,
+	userRepo repository.UserRepository,
+	chains *chain.Registry,
+	authCfg *config.AuthConfig,
+	cfg *config.AuthorizationConfig,
+	logger *zap.Logger,
+) *AuthorizationUseCase {
+	return &AuthorizationUseCase{
+		authorizationRepo: authorizationRepo,
+		consentRepo:       consentRepo,
+		userRepo:          userRepo,
+		chains:            chains,
+		authConfig:        authCfg,
+		config:            cfg,
+		logger:            logger,
+	}
+}
+
+// ListPlatforms 列出可授权的平台
+func (uc *AuthorizationUseCase) ListPlatforms() []dto.PlatformInfo {
+	platforms := make([]dto.PlatformInfo, 0, len(uc.config.Platforms))
+	for _, p := range uc.config.Platforms {
+		scopes := p.Scopes
+		if len(scopes) == 0 {
+			scopes = entity.KnownScopes
+		}
+		platforms = append(platforms, dto.PlatformInfo{ID: p.ID, Name: p.Name, Scopes: scopes})
+	}
+	return platforms
+}
+
+// ListAuthorizations 列出用户的所有授权
+func (uc *AuthorizationUseCase) ListAuthorizations(ctx context.Context, userID string) ([]*entity.Authorization, error) {
+	return uc.authorizationRepo.FindByUserID(ctx, userID)
+}
+
+// CreateGrantChallenge 生成授权同意消息，需主钱包签名
+func (uc *AuthorizationUseCase) CreateGrantChallenge(ctx context.Context, userID, sessionID string, req *dto.GrantChallengeRequest) (*dto.GrantChallengeResponse, error) {
+	// 1. 校验平台和 scope
+	platform, ok := uc.config.Platform(req.Platform)
+	if !ok {
+		return nil, ErrPlatformNotFound
+	}
+	scope, err := normalizeScope(req.Scope, func(s string) bool {
+		return isKnownScope(s) && platform.AllowsScope(s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	account, err := primaryAccount(user)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 生成挑战并绑定到当前会话
+	nonce, err := crypto.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	grantExpiresAt := now.Add(uc.config.GrantLifetime(req.ExpiresInDays))
+	challenge := &entity.ConsentChallenge{
+		Nonce:          nonce,
+		UserID:         userID,
+		SessionID:      sessionID,
+		Account:        account.String(),
+		Platform:       platform.ID,
+		Scope:          scope,
+		GrantExpiresAt: &grantExpiresAt,
+		IssuedAt:       now,
+		ExpiresAt:      now.Add(uc.authConfig.NonceLifetime()),
+	}
+	challenge.Message = uc.consentMessage(challenge, platform, user.DID, account)
+
+	if err := uc.consentRepo.SaveConsentChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to save consent challenge: %w", err)
+	}
+
+	return &dto.GrantChallengeResponse{
+		Nonce:          nonce,
+		Message:        challenge.Message,
+		IssuedAt:       challenge.IssuedAt.Format(time.RFC3339),
+		ExpiresAt:      challenge.ExpiresAt.Format(time.RFC3339),
+		GrantExpiresAt: grantExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// Grant 验证主钱包对同意消息的签名并创建授权，替换该平台现有的授权
+func (uc *AuthorizationUseCase) Grant(ctx context.Context, userID, sessionID string, req *dto.GrantRequest) (*entity.Authorization, error) {
+	// 1. 原子地取出挑战，保证只能使用一次
+	challenge, err := uc.consentRepo.ConsumeConsentChallenge(ctx, req.Nonce)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, fmt.Errorf("consent challenge not found or expired")
+		}
+		return nil, fmt.Errorf("failed to find consent challenge: %w", err)
+	}
+	if challenge.IsExpired() {
+		return nil, fmt.Errorf("consent challenge has expired")
+	}
+
+	// 2. 挑战必须由同一用户的同一会话发起
+	if challenge.UserID != userID || challenge.SessionID != sessionID {
+		uc.logger.Warn("Consent challenge used from another session",
+			zap.String("user_id", userID),
+			zap.String("challenge_user_id", challenge.UserID),
+		)
+		return nil, fmt.Errorf("consent challenge does not belong to this session")
+	}
+
+	// 3. 验证主钱包签名
+	account, err := caip.ParseAccountID(challenge.Account)
+	if err != nil {
+		return nil, err
+	}
+	family, err := uc.chains.Get(chain.Namespace(account.Chain.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	valid, err := family.VerifyMessage(ctx, account.Chain.Reference, challenge.Message, req.Signature, account.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if !valid {
+		uc.logger.Warn("Invalid consent signature", zap.String("account", challenge.Account))
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	// 4. 创建授权
+	authorization := &entity.Authorization{
+		UserID:    userID,
+		Platform:  challenge.Platform,
+		Scope:     challenge.Scope,
+		Status:    entity.AuthActive,
+		SignedBy:  challenge.Account,
+		Message:   challenge.Message,
+		Signature: req.Signature,
+		ExpiresAt: challenge.GrantExpiresAt,
+	}
+	if err := uc.authorizationRepo.Grant(ctx, authorization); err != nil {
+		return nil, fmt.Errorf("failed to create authorization: %w", err)
+	}
+
+	uc.logger.Info("Platform authorization granted",
+		zap.String("user_id", userID),
+		zap.String("platform", authorization.Platform),
+		zap.String("scope", authorization.Scope),
+	)
+
+	return authorization, nil
+}
+
+// Revoke 用户吊销自己的授权
+func (uc *AuthorizationUseCase) Revoke(ctx context.Context, userID, id string) error {
+	authorization, err := uc.authorizationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAuthorizationNotFound
+		}
+		return fmt.Errorf("failed to find authorization: %w", err)
+	}
+	if authorization.UserID != userID {
+		return ErrAuthorizationNotFound
+	}
+
+	if err := uc.authorizationRepo.Revoke(ctx, authorization.ID); err != nil {
+		return fmt.Errorf("failed to revoke authorization: %w", err)
+	}
+
+	uc.logger.Info("Platform authorization revoked",
+		zap.String("user_id", userID),
+		zap.String("platform", authorization.Platform),
+	)
+	return nil
+}
+
+// Check 平台查询用户是否授予了全部指定 scope，用户不存在或已被暂停时视为未授权
+func (uc *AuthorizationUseCase) Check(ctx context.Context, platformID, did string, scopes []string) (*dto.AuthorizationStatusResponse, error) {
+	resp := &dto.AuthorizationStatusResponse{DID: did, Platform: platformID}
+
+	user, err := uc.userRepo.FindByDID(ctx, did)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		return resp, nil
+	}
+
+	authorization, err := uc.authorizationRepo.FindActive(ctx, user.ID, platformID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("failed to find authorization: %w", err)
+	}
+	if !authorization.IsActive() {
+		return resp, nil
+	}
+
+	resp.Scope = authorization.Scope
+	resp.ExpiresAt = authorization.ExpiresAt
+	resp.Active = authorization.HasScopes(scopes...)
+	return resp, nil
+}
+
+// ExpireDue 将已过期的 ACTIVE 授权标记为 EXPIRED (由 worker 定期调用)
+func (uc *AuthorizationUseCase) ExpireDue(ctx context.Context) (int64, error) {
+	return uc.authorizationRepo.ExpireDue(ctx)
+}
+
+// consentMessage 构造同意消息，包含平台、scope 与授权过期时间
+func (uc *AuthorizationUseCase) consentMessage(challenge *entity.ConsentChallenge, platform *config.PlatformConfig, did string, account caip.AccountID) string {
+	var b strings.Builder
+	b.WriteString(uc.authConfig.Domain + " wants you to authorize " + platform.Name + " to access your DeData account:\n")
+	b.WriteString(account.Address + "\n")
+	b.WriteString("\n")
+	b.WriteString("Account: " + did + "\n")
+	b.WriteString("Platform: " + platform.ID + "\n")
+	b.WriteString("Scope: " + challenge.Scope + "\n")
+	b.WriteString("URI: " + uc.authConfig.URI + "\n")
+	b.WriteString("Chain ID: " + account.Chain.Reference + "\n")
+	b.WriteString("Nonce: " + challenge.Nonce + "\n")
+	b.WriteString("Issued At: " + challenge.IssuedAt.Format(time.RFC3339) + "\n")
+	b.WriteString("Expiration Time: " + challenge.ExpiresAt.Format(time.RFC3339) + "\n")
+	b.WriteString("Authorization Expires: " + challenge.GrantExpiresAt.Format(time.RFC3339))
+	return b.String()
+}
+
+// normalizeScope 校验每个 scope 均可申请，去重排序后以空格连接
+func normalizeScope(scope string, allowed func(string) bool) (string, error) {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, s := range strings.Fields(scope) {
+		if seen[s] {
+			continue
+		}
+		if !allowed(s) {
+			return "", fmt.Errorf("%w: %s", ErrScopeNotAllowed, s)
+		}
+		seen[s] = true
+		scopes = append(scopes, s)
+	}
+	if len(scopes) == 0 {
+		return "", fmt.Errorf("%w: scope is empty", ErrScopeNotAllowed)
+	}
+	sort.Strings(scopes)
+	return strings.Join(scopes, " "), nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, s := range entity.KnownScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryAccount 返回用户主钱包的 CAIP-10 标识，尚未回填的历史 EVM 用户按 chain_id 推导
+func primaryAccount(user *entity.User) (caip.AccountID, error) {
+	if user.Account != "" {
+		return caip.ParseAccountID(user.Account)
+	}
+	if user.Namespace != "" && user.Namespace != string(chain.EIP155) {
+		return caip.AccountID{}, fmt.Errorf("primary wallet has no account id, sign in again")
+	}
+	return caip.NewAccountID(string(chain.EIP155), strconv.Itoa(user.ChainID), user.WalletAddress)
+}
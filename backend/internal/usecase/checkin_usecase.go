@@ -15,26 +15,40 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrCheckInInProgress 用户有正在发起或正在发放的签到
+	ErrCheckInInProgress = errors.New("you have a checkin in progress, please wait")
+
+	// ErrDailyLimitReached 今日成功签到次数已达到 checkin.daily_limit
+	ErrDailyLimitReached = errors.New("daily checkin limit reached, please come back tomorrow")
+)
+
 type CheckInUseCase struct {
 	checkinRepo repository.CheckInRepository
 	userRepo    repository.UserRepository
+	lockRepo    repository.LockRepository
 	x402Client  external.X402Client
 	config      *config.CheckInConfig
+	location    *time.Location // 签到日切换时区
 	logger      *zap.Logger
 }
 
 func NewCheckInUseCase(
 	checkinRepo repository.CheckInRepository,
 	userRepo repository.UserRepository,
+	lockRepo repository.LockRepository,
 	x402Client external.X402Client,
 	cfg *config.CheckInConfig,
+	location *time.Location,
 	logger *zap.Logger,
 ) *CheckInUseCase {
 	return &CheckInUseCase{
 		checkinRepo: checkinRepo,
 		userRepo:    userRepo,
+		lockRepo:    lockRepo,
 		x402Client:  x402Client,
 		config:      cfg,
+		location:    location,
 		logger:      logger,
 	}
 }
@@ -50,14 +64,32 @@ func (uc *CheckInUseCase) CheckIn(ctx context.Context, userID string) (*entity.C
 		return nil, nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	// 2. 检查今天是否已经签到成功
-	hasCheckedIn, err := uc.checkinRepo.CheckTodayCheckin(ctx, userID)
+	// 用户锁：并发请求不能同时查到"没有待支付记录"后各自创建新的支付挑战
+	lockKey := "checkin:" + userID
+	lockToken, locked, err := uc.lockRepo.Acquire(ctx, lockKey, uc.config.LockTimeout())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire checkin lock: %w", err)
+	}
+	if !locked {
+		return nil, nil, ErrCheckInInProgress
+	}
+	defer func() {
+		if err := uc.lockRepo.Release(context.WithoutCancel(ctx), lockKey, lockToken); err != nil {
+			uc.logger.Warn("Failed to release checkin lock",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		}
+	}()
+
+	// 2. 检查今天的签到名额是否已用完 (支付验证时由唯一索引再次保证)
+	today := uc.day(time.Now())
+	todayCount, err := uc.checkinRepo.CountDayCheckins(ctx, userID, today)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to check today checkin: %w", err)
 	}
-	if hasCheckedIn {
-		fmt.Printf("already checked in")
-		//return nil, nil, fmt.Errorf("already checked in today")
+	if todayCount >= int64(uc.config.PerDayLimit()) {
+		return nil, nil, ErrDailyLimitReached
 	}
 
 	// 3. 检查是否有正在进行的签到（等待支付或正在发放）
@@ -97,11 +129,11 @@ func (uc *CheckInUseCase) CheckIn(ctx context.Context, userID string) (*entity.C
 		return nil, nil, fmt.Errorf("failed to check issuing status: %w", err)
 	}
 	if existingIssuing != nil {
-		return nil, nil, fmt.Errorf("you have a checkin in progress, please wait")
+		return nil, nil, ErrCheckInInProgress
 	}
 
 	// 4. 调用 x402 的 daily-checkin API（固定 0.01 美元 Polygon USDT）
-	statusCode, respBody, err := uc.x402Client.DailyCheckin(ctx, userID)
+	statusCode, respBody, err := uc.x402Client.DailyCheckin(ctx, userID, today.Format("2006-01-02"))
 	if err != nil {
 		uc.logger.Error("Failed to call x402 daily-checkin", zap.Error(err))
 		return nil, nil, fmt.Errorf("failed to call daily-checkin: %w", err)
@@ -110,7 +142,7 @@ func (uc *CheckInUseCase) CheckIn(ctx context.Context, userID string) (*entity.C
 	// 5. 处理响应
 	// 如果返回 200，说明 x402 那边已经签到过了（理论上不会走到这里，因为我们上面已经检查过了）
 	if statusCode == 200 {
-		return nil, nil, fmt.Errorf("already checked in today")
+		return nil, nil, ErrDailyLimitReached
 	}
 
 	// 如果返回 402，解析支付挑战
@@ -184,7 +216,8 @@ func (uc *CheckInUseCase) GetCheckInSummary(ctx context.Context, userID string)
 	}
 
 	// 1. 检查今日是否已签到
-	checkedInToday, err := uc.checkinRepo.CheckTodayCheckin(ctx, userID)
+	today := uc.day(time.Now())
+	todayCount, err := uc.checkinRepo.CountDayCheckins(ctx, userID, today)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check today checkin: %w", err)
 	}
@@ -205,19 +238,29 @@ func (uc *CheckInUseCase) GetCheckInSummary(ctx context.Context, userID string)
 		rank = 0 // 如果获取失败，设置为0
 	}
 
-	// 4. 获取每日统计（可选，用于展示日历等）
+	// 4. 连续签到 (超过宽限天数未签到时当前连续天数为 0)
+	currentStreak := user.StreakAt(today, uc.config.StreakGraceDays)
+
+	// 5. 获取每日统计（可选，用于展示日历等）
 	dailyStats, err := uc.checkinRepo.GetDailyStats(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get daily stats: %w", err)
 	}
 
 	summary := map[string]interface{}{
-		"checkedInToday": checkedInToday,     // 今日是否已签到
-		"totalRewards":   user.TotalRewards,  // 签到总获取的tokens数
-		"totalCheckins":  totalCheckins,      // 签到总次数
-		"rank":           rank,               // 总排名
-		"lastCheckinAt":  user.LastCheckinAt, // 最后签到时间
-		"dailyStats":     dailyStats,         // 每日统计（日历展示等）
+		"today":           today.Format("2006-01-02"), // 当前签到日 (签到时区)
+		"timezone":        uc.location.String(),       // 签到日切换时区
+		"checkedInToday":  todayCount > 0,             // 今日是否已签到
+		"checkinsToday":   todayCount,                 // 今日已签到次数
+		"dailyLimit":      uc.config.PerDayLimit(),    // 每日签到次数上限
+		"currentStreak":   currentStreak,              // 当前连续签到天数
+		"longestStreak":   user.LongestStreak,         // 最长连续签到天数
+		"streakGraceDays": uc.config.StreakGraceDays,  // 允许中断的天数
+		"totalRewards":    user.TotalRewards,          // 签到总获取的tokens数
+		"totalCheckins":   totalCheckins,              // 签到总次数
+		"rank":            rank,                       // 总排名
+		"lastCheckinAt":   user.LastCheckinAt,         // 最后签到时间
+		"dailyStats":      dailyStats,                 // 每日统计（日历展示等）
 	}
 
 	return summary, nil
@@ -258,13 +301,16 @@ func (uc *CheckInUseCase) VerifyCheckin(ctx context.Context, orderID, userID str
 		return false, "", fmt.Errorf("failed to verify payment: %w", err)
 	}
 
-	// 6. 如果支付成功，更新状态为 payment_success
+	// 6. 如果支付成功，占用发起签到当天的名额并更新状态为 payment_success
 	if success {
-		checkin.Status = entity.CheckInPaymentSuccess
-		if err := uc.checkinRepo.Update(ctx, checkin); err != nil {
+		claimed, err := uc.checkinRepo.ClaimDaySlot(ctx, checkin, uc.day(checkin.CreatedAt), uc.config.PerDayLimit(), uc.config.StreakGraceDays)
+		if err != nil {
 			uc.logger.Error("Failed to update checkin status", zap.Error(err))
 			return false, "", fmt.Errorf("failed to update checkin status: %w", err)
 		}
+		if !claimed {
+			return uc.resolveUnclaimedPayment(ctx, checkin)
+		}
 
 		uc.logger.Info("Payment verified successfully",
 			zap.String("order_id", orderID),
@@ -275,6 +321,46 @@ func (uc *CheckInUseCase) VerifyCheckin(ctx context.Context, orderID, userID str
 	return success, message, nil
 }
 
+// resolveUnclaimedPayment 支付成功但未能占用名额：并发验证已处理该记录时视为成功，
+// 否则当天名额已满，记录标记为 payment_failed，需人工退款
+func (uc *CheckInUseCase) resolveUnclaimedPayment(ctx context.Context, checkin *entity.CheckIn) (bool, string, error) {
+	current, err := uc.checkinRepo.FindByID(ctx, checkin.ID)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to find checkin: %w", err)
+	}
+	if current.Status != entity.CheckInPendingPayment {
+		return current.CheckInDay != nil, string(current.Status), nil
+	}
+
+	uc.logger.Error("Payment verified after daily checkin limit was reached, refund required",
+		zap.String("checkin_id", current.ID),
+		zap.String("user_id", current.UserID),
+		zap.Stringp("order_id", current.OrderID),
+	)
+	current.Status = entity.CheckInPaymentFailed
+	current.FailureReason = ptr(ErrDailyLimitReached.Error())
+	if err := uc.checkinRepo.Update(ctx, current); err != nil {
+		return false, "", fmt.Errorf("failed to update checkin status: %w", err)
+	}
+	return false, "", ErrDailyLimitReached
+}
+
+// NextCheckInAt 下一个签到日的开始时间，今日名额用完时返回给客户端
+func (uc *CheckInUseCase) NextCheckInAt() time.Time {
+	return uc.day(time.Now()).AddDate(0, 0, 1)
+}
+
+// day 返回 t 所在签到日的零点 (签到时区)
+func (uc *CheckInUseCase) day(t time.Time) time.Time {
+	return checkInDay(t, uc.location)
+}
+
+// checkInDay 返回 t 在签到时区 loc 中所在日期的零点
+func checkInDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
 // ptr 辅助函数,返回字符串指针
 func ptr(s string) *string {
 	return &s
@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/did"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/dedata/dedata-backend/pkg/vc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCredentialNotFound credential 不存在或不属于当前用户
+	ErrCredentialNotFound = errors.New("credential not found")
+
+	// ErrInvalidCredentialType 不支持的 credential 类型
+	ErrInvalidCredentialType = errors.New("invalid credential type")
+
+	// ErrNothingToAttest 当前没有可证明的数据 (例如尚未签到)
+	ErrNothingToAttest = errors.New("nothing to attest yet")
+
+	// ErrStatusListNotFound 状态列表尚未分配
+	ErrStatusListNotFound = errors.New("status list not found")
+)
+
+// credentialIDPrefix VC-JWT jti 前缀
+const credentialIDPrefix = "urn:uuid:"
+
+// CredentialUseCase Verifiable Credential 签发、验证与吊销
+// credential 以平台 did:web 为 issuer，使用 JWT 签发密钥签名，吊销状态通过 StatusList2021 公开
+type CredentialUseCase struct {
+	credentialRepo repository.CredentialRepository
+	userRepo       repository.UserRepository
+	checkinRepo    repository.CheckInRepository
+	jwtMgr         *pkgJWT.JWTManager
+	jwtConfig      *config.JWTConfig
+	config         *config.CredentialConfig
+	checkinConfig  *config.CheckInConfig
+	checkinLoc     *time.Location // 签到日切换时区，用于判断连续签到是否中断
+	logger         *zap.Logger
+}
+
+func NewCredentialUseCase(
+	credentialRepo repository.CredentialRepository,
+	userRepo repository.UserRepository,
+	checkinRepo repository.CheckInRepository,
+	jwtMgr *pkgJWT.JWTManager,
+	jwtCfg *config.JWTConfig,
+	cfg *config.CredentialConfig,
+	checkinCfg *config.CheckInConfig,
+	checkinLoc *time.Location,
+	logger *zap.Logger,
+) *CredentialUseCase {
+	return &CredentialUseCase{
+		credentialRepo: credentialRepo,
+		userRepo:       userRepo,
+		checkinRepo:    checkinRepo,
+		jwtMgr:         jwtMgr,
+		jwtConfig:      jwtCfg,
+		config:         cfg,
+		checkinConfig:  checkinCfg,
+		checkinLoc:     checkinLoc,
+		logger:         logger,
+	}
+}
+
+// ListCredentials 列出用户的 credential
+func (uc *CredentialUseCase) ListCredentials(ctx context.Context, userID string) ([]*entity.Credential, error) {
+	return uc.credentialRepo.FindByUserID(ctx, userID)
+}
+
+// Issue 根据当前签到和奖励数据为用户签发 credential
+func (uc *CredentialUseCase) Issue(ctx context.Context, userID string, req *dto.IssueCredentialRequest) (*entity.Credential, error) {
+	credentialType := entity.CredentialType(req.Type)
+	if !credentialType.IsValid() {
+		return nil, ErrInvalidCredentialType
+	}
+
+	issuer, err := did.WebDID(uc.jwtConfig.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issuer did: %w", err)
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 1. 从签到记录和用户数据中取出要证明的事实
+	subject, err := uc.attest(ctx, user, credentialType)
+	if err != nil {
+		return nil, err
+	}
+	subjectJSON, err := json.Marshal(subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential subject: %w", err)
+	}
+
+	// 2. 事实未变化时返回仍然有效的同类 credential，避免重复签发占用状态位
+	existing, err := uc.findReusable(ctx, user.ID, credentialType, subjectJSON)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	// 3. 分配吊销状态位
+	statusIndex, err := uc.credentialRepo.NextStatusIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate status index: %w", err)
+	}
+	list, offset := vc.Locate(statusIndex)
+	statusListURL := uc.statusListURL(list)
+
+	credential := vc.New(string(credentialType), subject)
+	credential.CredentialStatus = &vc.Status{
+		ID:                   statusListURL + "#" + strconv.Itoa(offset),
+		Type:                 vc.StatusEntryType,
+		StatusPurpose:        vc.StatusPurposeRevocation,
+		StatusListIndex:      strconv.Itoa(offset),
+		StatusListCredential: statusListURL,
+	}
+
+	// 4. 签名
+	id := uuid.NewString()
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(uc.config.Lifetime())
+	token, err := uc.jwtMgr.SignWithKeyID(&vc.Claims{
+		VC: credential,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        credentialIDPrefix + id,
+			Issuer:    issuer,
+			Subject:   user.DID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}, issuer+"#")
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign credential: %w", err)
+	}
+
+	record := &entity.Credential{
+		ID:          id,
+		UserID:      user.ID,
+		SubjectDID:  user.DID,
+		Type:        credentialType,
+		Subject:     string(subjectJSON),
+		Token:       token,
+		StatusIndex: statusIndex,
+		IssuedAt:    now,
+		ExpiresAt:   expiresAt,
+	}
+	if err := uc.credentialRepo.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to save credential: %w", err)
+	}
+
+	uc.logger.Info("Credential issued",
+		zap.String("credential_id", id),
+		zap.String("user_id", user.ID),
+		zap.String("type", string(credentialType)),
+	)
+
+	return record, nil
+}
+
+// Revoke 用户吊销自己的 credential
+func (uc *CredentialUseCase) Revoke(ctx context.Context, userID, credentialID string) error {
+	credential, err := uc.findCredential(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+	if credential.UserID != userID {
+		return ErrCredentialNotFound
+	}
+
+	return uc.revoke(ctx, credential, userID)
+}
+
+// AdminRevoke 管理员吊销任意 credential (例如发现刷量)
+func (uc *CredentialUseCase) AdminRevoke(ctx context.Context, adminID, credentialID string) error {
+	credential, err := uc.findCredential(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+
+	return uc.revoke(ctx, credential, adminID)
+}
+
+// Verify 验证 VC-JWT 的签名、issuer、有效期以及吊销状态
+// 验证失败不返回 error，而是在结果中说明原因
+func (uc *CredentialUseCase) Verify(ctx context.Context, token string) (*dto.VerifyCredentialResponse, error) {
+	issuer, err := did.WebDID(uc.jwtConfig.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issuer did: %w", err)
+	}
+
+	var claims vc.Claims
+	if err := uc.jwtMgr.ParseWithKeyID(token, &claims, issuer+"#"); err != nil {
+		return &dto.VerifyCredentialResponse{Error: err.Error()}, nil
+	}
+
+	resp := &dto.VerifyCredentialResponse{
+		ID:                claims.ID,
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Type:              claims.VC.Type,
+		CredentialSubject: claims.VC.CredentialSubject,
+	}
+	if claims.IssuedAt != nil {
+		resp.IssuedAt = claims.IssuedAt.UTC().Format(time.RFC3339)
+	}
+	if claims.ExpiresAt != nil {
+		resp.ExpiresAt = claims.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	if claims.Issuer != issuer {
+		resp.Error = "unexpected issuer"
+		return resp, nil
+	}
+
+	credential, err := uc.credentialRepo.FindByID(ctx, strings.TrimPrefix(claims.ID, credentialIDPrefix))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resp.Error = "unknown credential"
+			return resp, nil
+		}
+		return nil, fmt.Errorf("failed to find credential: %w", err)
+	}
+	if credential.IsRevoked() {
+		resp.Revoked = true
+		resp.Error = "credential has been revoked"
+		return resp, nil
+	}
+
+	resp.Valid = true
+	return resp, nil
+}
+
+// StatusList 返回签名的 StatusList2021Credential (VC-JWT)
+func (uc *CredentialUseCase) StatusList(ctx context.Context, list int64) (string, error) {
+	issuer, err := did.WebDID(uc.jwtConfig.Issuer)
+	if err != nil {
+		return "", fmt.Errorf("failed to build issuer did: %w", err)
+	}
+
+	// 只有已分配过状态位的列表才存在，同时避免 list * StatusListSize 溢出
+	current, err := uc.credentialRepo.CurrentStatusIndex(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get status index: %w", err)
+	}
+	if list > current/vc.StatusListSize {
+		return "", ErrStatusListNotFound
+	}
+
+	from := list * vc.StatusListSize
+	revoked, err := uc.credentialRepo.FindRevokedStatusIndexes(ctx, from, from+vc.StatusListSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to find revoked credentials: %w", err)
+	}
+
+	bits := vc.NewStatusList()
+	for _, index := range revoked {
+		if err := bits.Set(int(index - from)); err != nil {
+			return "", err
+		}
+	}
+	encoded, err := bits.Encode()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode status list: %w", err)
+	}
+
+	url := uc.statusListURL(list)
+	credential := vc.New(vc.TypeStatusList, map[string]interface{}{
+		"id":            url + "#list",
+		"type":          "StatusList2021",
+		"statusPurpose": vc.StatusPurposeRevocation,
+		"encodedList":   encoded,
+	})
+	credential.Context = append(credential.Context, vc.ContextStatusList)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	return uc.jwtMgr.SignWithKeyID(&vc.Claims{
+		VC: credential,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        url,
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}, issuer+"#")
+}
+
+// attest 构造 credentialSubject
+func (uc *CredentialUseCase) attest(ctx context.Context, user *entity.User, credentialType entity.CredentialType) (map[string]interface{}, error) {
+	subject := map[string]interface{}{
+		"id": user.DID,
+	}
+
+	switch credentialType {
+	case entity.CredentialCheckInCount:
+		count, err := uc.checkinRepo.CountSuccessCheckinsByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count checkins: %w", err)
+		}
+		if count == 0 {
+			return nil, ErrNothingToAttest
+		}
+		subject["successfulCheckIns"] = count
+	case entity.CredentialCheckInStreak:
+		// 与签到统计、排行榜和奖励规则使用同一连续签到天数
+		streak := user.StreakAt(checkInDay(time.Now(), uc.checkinLoc), uc.checkinConfig.StreakGraceDays)
+		if streak == 0 {
+			return nil, ErrNothingToAttest
+		}
+		subject["checkInStreakDays"] = streak
+	case entity.CredentialRewards:
+		subject["totalRewards"] = user.TotalRewards
+	}
+
+	return subject, nil
+}
+
+func (uc *CredentialUseCase) revoke(ctx context.Context, credential *entity.Credential, revokedBy string) error {
+	if err := uc.credentialRepo.Revoke(ctx, credential.ID); err != nil {
+		return fmt.Errorf("failed to revoke credential: %w", err)
+	}
+
+	uc.logger.Info("Credential revoked",
+		zap.String("credential_id", credential.ID),
+		zap.String("user_id", credential.UserID),
+		zap.String("revoked_by", revokedBy),
+	)
+
+	return nil
+}
+
+// findReusable 查找用户未过期、未吊销且 credentialSubject 与本次相同的同类 credential
+func (uc *CredentialUseCase) findReusable(ctx context.Context, userID string, credentialType entity.CredentialType, subjectJSON []byte) (*entity.Credential, error) {
+	credentials, err := uc.credentialRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find credentials: %w", err)
+	}
+
+	var subject interface{}
+	if err := json.Unmarshal(subjectJSON, &subject); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, credential := range credentials {
+		if credential.Type != credentialType || credential.IsRevoked() || !credential.ExpiresAt.After(now) {
+			continue
+		}
+		// JSONB 会重新格式化，按解码后的值比较
+		var existing interface{}
+		if err := json.Unmarshal([]byte(credential.Subject), &existing); err != nil {
+			continue
+		}
+		if reflect.DeepEqual(existing, subject) {
+			return credential, nil
+		}
+	}
+	return nil, nil
+}
+
+func (uc *CredentialUseCase) findCredential(ctx context.Context, credentialID string) (*entity.Credential, error) {
+	credential, err := uc.credentialRepo.FindByID(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCredentialNotFound
+		}
+		return nil, fmt.Errorf("failed to find credential: %w", err)
+	}
+	return credential, nil
+}
+
+// statusListURL 状态列表 credential 的公开地址
+func (uc *CredentialUseCase) statusListURL(list int64) string {
+	return strings.TrimRight(uc.jwtConfig.Issuer, "/") + "/api/credentials/status/" + strconv.FormatInt(list, 10)
+}
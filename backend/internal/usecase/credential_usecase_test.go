@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/vc"
+	"go.uber.org/zap"
+)
+
+var (
+	errStatusIndexAllocated = errors.New("status index allocated")
+	errStatusListRead       = errors.New("status list read")
+)
+
+// memoryCredentialRepo 分配状态位 / 读取状态列表时返回固定错误，用于判断用例是否会签发新 credential 或生成状态列表
+type memoryCredentialRepo struct {
+	repository.CredentialRepository
+	credentials []*entity.Credential
+	current     int64
+}
+
+func (r *memoryCredentialRepo) FindByUserID(context.Context, string) ([]*entity.Credential, error) {
+	return r.credentials, nil
+}
+
+func (r *memoryCredentialRepo) NextStatusIndex(context.Context) (int64, error) {
+	return 0, errStatusIndexAllocated
+}
+
+func (r *memoryCredentialRepo) CurrentStatusIndex(context.Context) (int64, error) {
+	return r.current, nil
+}
+
+func (r *memoryCredentialRepo) FindRevokedStatusIndexes(context.Context, int64, int64) ([]int64, error) {
+	return nil, errStatusListRead
+}
+
+func newTestCredentialUseCase(repo *memoryCredentialRepo, user *entity.User) *CredentialUseCase {
+	return NewCredentialUseCase(repo, &memoryUserRepo{user: user}, nil, nil,
+		&config.JWTConfig{Issuer: "https://api.dedata.io"}, &config.CredentialConfig{}, &config.CheckInConfig{},
+		time.UTC, zap.NewNop())
+}
+
+func TestIssueReusesUnchangedCredential(t *testing.T) {
+	user := &entity.User{ID: "user-1", DID: "did:pkh:eip155:1:0xabc", TotalRewards: "1.5"}
+	// JSONB 保存后键之间带空格
+	subject := `{"id": "did:pkh:eip155:1:0xabc", "totalRewards": "1.5"}`
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		credential *entity.Credential
+		reuse      bool
+	}{
+		{"same subject", &entity.Credential{ID: "c1", Type: entity.CredentialRewards, Subject: subject, ExpiresAt: now.Add(time.Hour)}, true},
+		{"changed subject", &entity.Credential{ID: "c2", Type: entity.CredentialRewards, Subject: `{"id": "did:pkh:eip155:1:0xabc", "totalRewards": "1"}`, ExpiresAt: now.Add(time.Hour)}, false},
+		{"other type", &entity.Credential{ID: "c3", Type: entity.CredentialCheckInCount, Subject: subject, ExpiresAt: now.Add(time.Hour)}, false},
+		{"expired", &entity.Credential{ID: "c4", Type: entity.CredentialRewards, Subject: subject, ExpiresAt: now.Add(-time.Hour)}, false},
+		{"revoked", &entity.Credential{ID: "c5", Type: entity.CredentialRewards, Subject: subject, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestCredentialUseCase(&memoryCredentialRepo{credentials: []*entity.Credential{tt.credential}}, user)
+			credential, err := uc.Issue(context.Background(), user.ID, &dto.IssueCredentialRequest{Type: string(entity.CredentialRewards)})
+			if tt.reuse {
+				if err != nil || credential.ID != tt.credential.ID {
+					t.Errorf("Issue = %v, %v; want existing credential %s", credential, err, tt.credential.ID)
+				}
+			} else if !errors.Is(err, errStatusIndexAllocated) {
+				t.Errorf("Issue error = %v, want a new credential to be issued", err)
+			}
+		})
+	}
+}
+
+func TestStatusListRejectsUnallocatedList(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		list    int64
+		wantErr error
+	}{
+		{"first list before any issue", 0, 0, errStatusListRead},
+		{"last allocated list", 2*vc.StatusListSize + 1, 2, errStatusListRead},
+		{"next list", 2*vc.StatusListSize + 1, 3, ErrStatusListNotFound},
+		{"overflowing list", 1, math.MaxInt64/vc.StatusListSize + 1, ErrStatusListNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestCredentialUseCase(&memoryCredentialRepo{current: tt.current}, &entity.User{})
+			if _, err := uc.StatusList(context.Background(), tt.list); !errors.Is(err, tt.wantErr) {
+				t.Errorf("StatusList(%d) error = %v, want %v", tt.list, err, tt.wantErr)
+			}
+		})
+	}
+}
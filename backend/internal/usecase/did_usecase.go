@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/did"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"gorm.io/gorm"
+)
+
+// DIDUseCase did:dedata 解析，实现 did.Resolver
+type DIDUseCase struct {
+	userRepo    repository.UserRepository
+	walletRepo  repository.WalletRepository
+	profileRepo repository.ProfileRepository
+	jwtMgr      *pkgJWT.JWTManager
+	config      *config.JWTConfig
+}
+
+func NewDIDUseCase(
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	profileRepo repository.ProfileRepository,
+	jwtMgr *pkgJWT.JWTManager,
+	cfg *config.JWTConfig,
+) *DIDUseCase {
+	return &DIDUseCase{
+		userRepo:    userRepo,
+		walletRepo:  walletRepo,
+		profileRepo: profileRepo,
+		jwtMgr:      jwtMgr,
+		config:      cfg,
+	}
+}
+
+// Resolve 解析 did:dedata 为 DID Document
+// 所有已绑定钱包都作为 verificationMethod；用户被暂停或拉黑时标记为 deactivated 且不返回任何验证方法
+func (uc *DIDUseCase) Resolve(ctx context.Context, id string) (*did.ResolutionResult, error) {
+	id, err := did.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByDID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, did.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	meta := did.DocumentMetadata{
+		Created:     &user.CreatedAt,
+		Updated:     &user.UpdatedAt,
+		Deactivated: !user.IsActive(),
+	}
+
+	doc := did.NewDocument(user.DID, did.ContextSecp256k1, did.ContextEd25519)
+	if meta.Deactivated {
+		return did.NewResolutionResult(doc, meta), nil
+	}
+
+	wallets, err := uc.walletRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find wallets: %w", err)
+	}
+	if len(wallets) == 0 {
+		// 兼容尚未写入 user_wallets 的历史用户
+		wallets = []*entity.UserWallet{{
+			Account:   user.Account,
+			Address:   user.WalletAddress,
+			ChainID:   int64(user.ChainID),
+			Namespace: user.Namespace,
+			IsPrimary: true,
+		}}
+	}
+	for _, wallet := range wallets {
+		doc.AddVerificationMethod(walletVerificationMethod(doc.ID, wallet))
+	}
+
+	profile, err := uc.profileRepo.FindByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+	if profile != nil {
+		addProfileServices(doc, profile)
+	}
+
+	return did.NewResolutionResult(doc, meta), nil
+}
+
+// PlatformDocument 返回平台自身的 did:web 文档 (/.well-known/did.json)，验证方法为 JWKS 中的公钥
+func (uc *DIDUseCase) PlatformDocument() (*did.Document, error) {
+	id, err := did.WebDID(uc.config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := did.NewDocument(id, did.ContextJWS2020)
+	for _, key := range uc.jwtMgr.JWKS().Keys {
+		doc.AddVerificationMethod(did.VerificationMethod{
+			ID:           id + "#" + key.Kid,
+			Type:         did.TypeJSONWebKey,
+			Controller:   id,
+			PublicKeyJwk: key,
+		})
+	}
+	return doc, nil
+}
+
+// walletVerificationMethod 钱包对应的验证方法: Solana 地址本身即 ed25519 公钥，EVM 钱包通过 ecrecover 验证
+func walletVerificationMethod(id string, wallet *entity.UserWallet) did.VerificationMethod {
+	if wallet.Namespace == string(chain.Solana) {
+		return did.VerificationMethod{
+			ID:              id + "#" + walletFragment(wallet.Account, wallet.Address),
+			Type:            did.TypeEd25519,
+			Controller:      id,
+			PublicKeyBase58: wallet.Address,
+		}
+	}
+	account := wallet.Account
+	if account == "" {
+		// 尚未回填 CAIP-10 标识的历史钱包
+		account = did.BlockchainAccountID(wallet.ChainID, wallet.Address)
+	}
+	return did.VerificationMethod{
+		ID:                  id + "#" + walletFragment(strings.ToLower(account), wallet.Address),
+		Type:                did.TypeSecp256k1Recovery,
+		Controller:          id,
+		BlockchainAccountID: account,
+	}
+}
+
+// walletFragment 验证方法 ID 的片段，由 CAIP-10 标识派生 (eip155:137:0xabc... → wallet-eip155-137-0xabc...)
+// 同一地址可以绑定在多条链上，只用地址会产生重复的 ID；尚未回填 CAIP-10 标识时退回使用地址
+func walletFragment(account, address string) string {
+	if account == "" {
+		return "wallet-" + address
+	}
+	return "wallet-" + strings.ReplaceAll(account, ":", "-")
+}
+
+// addProfileServices 将 Profile 中公开的联系方式作为服务端点 (email 不公开)
+func addProfileServices(doc *did.Document, profile *entity.Profile) {
+	if profile.Telegram != nil && *profile.Telegram != "" {
+		doc.AddService("telegram", "Telegram", "https://t.me/"+strings.TrimPrefix(*profile.Telegram, "@"))
+	}
+	if profile.Avatar != nil && strings.HasPrefix(*profile.Avatar, "https://") {
+		doc.AddService("avatar", "Avatar", *profile.Avatar)
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrIdempotencyKeyInUse 使用同一个 key 的首次请求仍在处理中
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is still in progress")
+
+	// ErrIdempotencyKeyReused 同一个 key 被用于不同的请求
+	ErrIdempotencyKeyReused = errors.New("idempotency key has already been used for a different request")
+)
+
+// IdempotencyUseCase 签到接口的 Idempotency-Key 处理
+// 首次请求处理中时占位 (有效期同签到用户锁，处理期间由 Hold 续期，进程退出后自动过期)，
+// 完成后保存响应供重复请求重放 (有效期为 idempotency_ttl_hours)
+type IdempotencyUseCase struct {
+	idempotencyRepo repository.IdempotencyRepository
+	config          *config.CheckInConfig
+	logger          *zap.Logger
+}
+
+func NewIdempotencyUseCase(
+	idempotencyRepo repository.IdempotencyRepository,
+	cfg *config.CheckInConfig,
+	logger *zap.Logger,
+) *IdempotencyUseCase {
+	return &IdempotencyUseCase{
+		idempotencyRepo: idempotencyRepo,
+		config:          cfg,
+		logger:          logger,
+	}
+}
+
+// Begin 开始处理带 key 的请求
+// 首次请求返回 nil，调用方继续处理；key 已完成时返回首次响应供重放
+func (uc *IdempotencyUseCase) Begin(ctx context.Context, key, fingerprint string) (*entity.IdempotencyRecord, error) {
+	record := &entity.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	}
+	existing, reserved, err := uc.idempotencyRepo.Reserve(ctx, key, record, uc.config.LockTimeout())
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	// 已有记录恰好过期时按处理中返回，客户端重试即可
+	if existing == nil {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.IsCompleted() {
+		return nil, ErrIdempotencyKeyInUse
+	}
+	return existing, nil
+}
+
+// Hold 在首次请求处理期间定期延长占位，避免处理时间超过占位有效期后重复请求被再次处理
+// 返回的函数停止续期并等待续期 goroutine 退出，需在 Complete 或 Release 之前调用
+func (uc *IdempotencyUseCase) Hold(ctx context.Context, key, fingerprint string) func() {
+	ttl := uc.config.LockTimeout()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				held, err := uc.idempotencyRepo.Extend(ctx, key, fingerprint, ttl)
+				if err != nil {
+					uc.logger.Warn("Failed to extend idempotency key",
+						zap.String("key", key),
+						zap.Error(err),
+					)
+					continue
+				}
+				if !held {
+					uc.logger.Warn("Idempotency key reservation lost while request was in progress",
+						zap.String("key", key),
+					)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// Complete 保存首次响应，失败时只记录日志，之后的重复请求在占位过期后会被重新处理
+func (uc *IdempotencyUseCase) Complete(ctx context.Context, key, fingerprint string, statusCode int, contentType string, body []byte) {
+	record := &entity.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+		CreatedAt:   time.Now(),
+	}
+	if err := uc.idempotencyRepo.Complete(ctx, key, record, uc.config.IdempotencyTTL()); err != nil {
+		uc.logger.Warn("Failed to save idempotent response",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+}
+
+// Release 放弃占位 (例如服务端错误)，允许客户端使用同一个 key 重试
+func (uc *IdempotencyUseCase) Release(ctx context.Context, key string) {
+	if err := uc.idempotencyRepo.Release(ctx, key); err != nil {
+		uc.logger.Warn("Failed to release idempotency key",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,447 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/dedata/dedata-backend/pkg/totp"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 二次验证安全事件名称，写入 security logger 的 event 字段
+const (
+	EventTOTPEnrolled       = "mfa.totp_enrolled"
+	EventTOTPDisabled       = "mfa.totp_disabled"
+	EventTOTPReset          = "mfa.totp_reset"
+	EventStepUpVerified     = "mfa.step_up_verified"
+	EventStepUpFailed       = "mfa.step_up_failed"
+	EventRecoveryCodeUsed   = "mfa.recovery_code_used"
+	EventRecoveryCodesReset = "mfa.recovery_codes_regenerated"
+)
+
+var (
+	// ErrTOTPUnavailable 未配置 totp.encryption_key，无法绑定或校验 TOTP
+	ErrTOTPUnavailable = errors.New("totp is not configured on this server")
+
+	// ErrTOTPNotEnrolled 管理员尚未绑定 (或尚未确认) TOTP
+	ErrTOTPNotEnrolled = errors.New("totp is not enrolled")
+
+	// ErrTOTPAlreadyEnrolled 已确认绑定，需先解绑才能重新绑定
+	ErrTOTPAlreadyEnrolled = errors.New("totp is already enrolled")
+
+	// ErrInvalidTOTPCode 验证码或恢复码错误、过期或已被使用
+	ErrInvalidTOTPCode = errors.New("invalid or already used verification code")
+
+	// ErrStepUpRequired 敏感操作需要先完成二次验证
+	ErrStepUpRequired = errors.New("step-up verification required")
+
+	// ErrTOTPEnrollmentRequired 配置要求管理员绑定 TOTP 后才能执行敏感操作
+	ErrTOTPEnrollmentRequired = errors.New("totp enrollment required for this operation")
+)
+
+const (
+	// totpSkew 允许前后各 1 个时间步 (30 秒) 的时钟偏差
+	totpSkew = 1
+
+	// totpAttemptLimit 每个管理员每分钟最多尝试的验证次数
+	totpAttemptLimit = 5
+
+	// recoveryCodeBytes 恢复码随机字节数，展示为 xxxxx-xxxxx
+	recoveryCodeBytes = 5
+)
+
+// MFAUseCase 管理员 TOTP 二次验证：绑定、恢复码与敏感操作前的 step-up 校验
+// step-up 按会话记录，会话注销或到期后需要重新验证
+type MFAUseCase struct {
+	totpRepo      repository.TOTPRepository
+	stepUpRepo    repository.StepUpRepository
+	rateLimitRepo repository.RateLimitRepository
+	userRepo      repository.UserRepository
+	cipher        *crypto.SecretCipher
+	config        *config.TOTPConfig
+	events        *zap.Logger
+}
+
+// NewMFAUseCase cipher 为 nil 时 (未配置加密密钥) 无法绑定 TOTP
+func NewMFAUseCase(
+	totpRepo repository.TOTPRepository,
+	stepUpRepo repository.StepUpRepository,
+	rateLimitRepo repository.RateLimitRepository,
+	userRepo repository.UserRepository,
+	cipher *crypto.SecretCipher,
+	cfg *config.TOTPConfig,
+	logger *zap.Logger,
+) *MFAUseCase {
+	return &MFAUseCase{
+		totpRepo:      totpRepo,
+		stepUpRepo:    stepUpRepo,
+		rateLimitRepo: rateLimitRepo,
+		userRepo:      userRepo,
+		cipher:        cipher,
+		config:        cfg,
+		events:        logger.Named("security"),
+	}
+}
+
+// Status 返回管理员的绑定状态与当前会话的 step-up 状态
+func (uc *MFAUseCase) Status(ctx context.Context, userID, sessionID string) (*dto.MFAStatusResponse, error) {
+	resp := &dto.MFAStatusResponse{Enforced: uc.config.Enforce}
+
+	enrollment, err := uc.findEnrollment(ctx, userID)
+	if err != nil && !errors.Is(err, ErrTOTPNotEnrolled) {
+		return nil, err
+	}
+	if enrollment != nil {
+		resp.Enrolled = enrollment.IsConfirmed()
+		resp.Pending = !enrollment.IsConfirmed()
+		resp.ConfirmedAt = enrollment.ConfirmedAt
+	}
+
+	if resp.Enrolled {
+		remaining, err := uc.totpRepo.CountRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+		}
+		resp.RecoveryCodesRemaining = remaining
+
+		if sessionID != "" {
+			expiresAt, err := uc.stepUpRepo.ExpiresAt(ctx, sessionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check step-up: %w", err)
+			}
+			resp.StepUpExpiresAt = expiresAt
+		}
+	}
+	return resp, nil
+}
+
+// Enroll 发起绑定：生成新密钥并加密保存，需调用 Confirm 提交验证码后生效
+// 未确认的绑定可以重复发起，旧密钥被覆盖
+func (uc *MFAUseCase) Enroll(ctx context.Context, userID string) (*dto.TOTPEnrollResponse, error) {
+	if uc.cipher == nil {
+		return nil, ErrTOTPUnavailable
+	}
+
+	existing, err := uc.findEnrollment(ctx, userID)
+	if err != nil && !errors.Is(err, ErrTOTPNotEnrolled) {
+		return nil, err
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	encrypted, err := uc.cipher.Encrypt([]byte(secret), []byte(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	if err := uc.totpRepo.Save(ctx, &entity.TOTPEnrollment{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save totp enrollment: %w", err)
+	}
+
+	return &dto.TOTPEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.ProvisioningURI(uc.config.IssuerName(), user.WalletAddress, secret),
+	}, nil
+}
+
+// Confirm 提交验证器上的验证码确认绑定，返回恢复码 (只返回一次)
+// 确认即视为完成一次二次验证，当前会话直接获得 step-up
+func (uc *MFAUseCase) Confirm(ctx context.Context, userID, sessionID, code string) (*dto.RecoveryCodesResponse, error) {
+	enrollment, err := uc.findEnrollment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.IsConfirmed() {
+		return nil, ErrTOTPAlreadyEnrolled
+	}
+
+	if err := uc.allowAttempt(ctx, userID); err != nil {
+		return nil, err
+	}
+	step, err := uc.validateCode(enrollment, code)
+	if err != nil {
+		uc.logFailure(userID, "confirm", err)
+		return nil, err
+	}
+
+	if err := uc.totpRepo.Confirm(ctx, userID, step, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to confirm totp enrollment: %w", err)
+	}
+	codes, err := uc.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.events.Info("TOTP enrolled",
+		zap.String("event", EventTOTPEnrolled),
+		zap.String("user_id", userID),
+	)
+	uc.grantStepUp(ctx, userID, sessionID)
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyStepUp 使用验证码或恢复码完成二次验证，当前会话在 step_up_minutes 内可执行敏感操作
+func (uc *MFAUseCase) VerifyStepUp(ctx context.Context, userID, sessionID string, req *dto.StepUpRequest) (*dto.StepUpResponse, error) {
+	if sessionID == "" {
+		return nil, ErrStepUpRequired
+	}
+	if err := uc.verify(ctx, userID, req.Code, req.RecoveryCode); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(uc.config.StepUpLifetime())
+	if err := uc.stepUpRepo.Grant(ctx, sessionID, uc.config.StepUpLifetime()); err != nil {
+		return nil, fmt.Errorf("failed to save step-up: %w", err)
+	}
+
+	uc.events.Info("Step-up verified",
+		zap.String("event", EventStepUpVerified),
+		zap.String("user_id", userID),
+		zap.String("session_id", sessionID),
+	)
+	return &dto.StepUpResponse{ExpiresAt: expiresAt}, nil
+}
+
+// RegenerateRecoveryCodes 使用当前验证码重新生成恢复码，旧恢复码全部作废
+func (uc *MFAUseCase) RegenerateRecoveryCodes(ctx context.Context, userID, code string) (*dto.RecoveryCodesResponse, error) {
+	if err := uc.verify(ctx, userID, code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, err := uc.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.events.Info("Recovery codes regenerated",
+		zap.String("event", EventRecoveryCodesReset),
+		zap.String("user_id", userID),
+	)
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 管理员使用验证码或恢复码解绑自己的 TOTP，同时清除当前会话的 step-up
+func (uc *MFAUseCase) Disable(ctx context.Context, userID, sessionID string, req *dto.StepUpRequest) error {
+	if err := uc.verify(ctx, userID, req.Code, req.RecoveryCode); err != nil {
+		return err
+	}
+
+	if err := uc.totpRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete totp enrollment: %w", err)
+	}
+	if sessionID != "" {
+		if err := uc.stepUpRepo.Revoke(ctx, sessionID); err != nil {
+			uc.events.Error("Failed to revoke step-up", zap.String("session_id", sessionID), zap.Error(err))
+		}
+	}
+
+	uc.events.Info("TOTP disabled",
+		zap.String("event", EventTOTPDisabled),
+		zap.String("user_id", userID),
+	)
+	return nil
+}
+
+// Reset 管理员重置另一名管理员的 TOTP (设备与恢复码均丢失时)，该操作本身需要 step-up
+func (uc *MFAUseCase) Reset(ctx context.Context, adminID, userID string) error {
+	if _, err := uc.findEnrollment(ctx, userID); err != nil {
+		return err
+	}
+	if err := uc.totpRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete totp enrollment: %w", err)
+	}
+
+	uc.events.Warn("TOTP reset by admin",
+		zap.String("event", EventTOTPReset),
+		zap.String("user_id", userID),
+		zap.String("admin_id", adminID),
+	)
+	return nil
+}
+
+// CheckStepUp 敏感操作前检查当前会话是否已完成二次验证
+// 未绑定 TOTP 的管理员在 enforce 关闭时直接放行
+func (uc *MFAUseCase) CheckStepUp(ctx context.Context, userID, sessionID string) error {
+	enrollment, err := uc.findEnrollment(ctx, userID)
+	if err != nil && !errors.Is(err, ErrTOTPNotEnrolled) {
+		return err
+	}
+	if enrollment == nil || !enrollment.IsConfirmed() {
+		if uc.config.Enforce {
+			return ErrTOTPEnrollmentRequired
+		}
+		return nil
+	}
+
+	if sessionID == "" {
+		return ErrStepUpRequired
+	}
+	expiresAt, err := uc.stepUpRepo.ExpiresAt(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to check step-up: %w", err)
+	}
+	if expiresAt == nil {
+		return ErrStepUpRequired
+	}
+	return nil
+}
+
+// verify 校验已确认绑定的管理员提交的验证码或恢复码
+func (uc *MFAUseCase) verify(ctx context.Context, userID, code, recoveryCode string) error {
+	enrollment, err := uc.findEnrollment(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enrollment.IsConfirmed() {
+		return ErrTOTPNotEnrolled
+	}
+	if err := uc.allowAttempt(ctx, userID); err != nil {
+		return err
+	}
+
+	if code == "" && recoveryCode != "" {
+		used, err := uc.totpRepo.ConsumeRecoveryCode(ctx, userID, crypto.HashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return fmt.Errorf("failed to consume recovery code: %w", err)
+		}
+		if !used {
+			uc.logFailure(userID, "recovery_code", ErrInvalidTOTPCode)
+			return ErrInvalidTOTPCode
+		}
+		uc.events.Warn("Recovery code used",
+			zap.String("event", EventRecoveryCodeUsed),
+			zap.String("user_id", userID),
+		)
+		return nil
+	}
+
+	step, err := uc.validateCode(enrollment, code)
+	if err != nil {
+		uc.logFailure(userID, "totp", err)
+		return err
+	}
+	advanced, err := uc.totpRepo.AdvanceStep(ctx, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record totp step: %w", err)
+	}
+	if !advanced {
+		uc.logFailure(userID, "totp_replay", ErrInvalidTOTPCode)
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// validateCode 解密密钥并校验验证码，返回匹配的时间步；不拒绝重放，由调用方通过时间步判断
+func (uc *MFAUseCase) validateCode(enrollment *entity.TOTPEnrollment, code string) (int64, error) {
+	if uc.cipher == nil {
+		return 0, ErrTOTPUnavailable
+	}
+	if code == "" {
+		return 0, ErrInvalidTOTPCode
+	}
+
+	secret, err := uc.cipher.Decrypt(enrollment.SecretEncrypted, []byte(enrollment.UserID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+	step, ok := totp.Validate(string(secret), code, time.Now(), totpSkew)
+	if !ok || step <= enrollment.LastUsedStep {
+		return 0, ErrInvalidTOTPCode
+	}
+	return step, nil
+}
+
+// allowAttempt 限制每个管理员的验证频率，防止暴力猜测 6 位验证码
+func (uc *MFAUseCase) allowAttempt(ctx context.Context, userID string) error {
+	allowed, err := uc.rateLimitRepo.Allow(ctx, "totp:"+userID, totpAttemptLimit, time.Minute)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if !allowed {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// replaceRecoveryCodes 生成新的恢复码并保存摘要，返回明文
+func (uc *MFAUseCase) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	count := uc.config.RecoveryCodeCount()
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		raw, err := crypto.RandomHex(recoveryCodeBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, crypto.HashToken(raw))
+	}
+
+	if err := uc.totpRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// grantStepUp 为当前会话记录 step-up，失败只记录日志 (管理员可以再次验证)
+func (uc *MFAUseCase) grantStepUp(ctx context.Context, userID, sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	if err := uc.stepUpRepo.Grant(ctx, sessionID, uc.config.StepUpLifetime()); err != nil {
+		uc.events.Error("Failed to save step-up", zap.String("user_id", userID), zap.Error(err))
+	}
+}
+
+// findEnrollment 查找 TOTP 绑定，不存在时返回 ErrTOTPNotEnrolled
+func (uc *MFAUseCase) findEnrollment(ctx context.Context, userID string) (*entity.TOTPEnrollment, error) {
+	enrollment, err := uc.totpRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTOTPNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to find totp enrollment: %w", err)
+	}
+	return enrollment, nil
+}
+
+// logFailure 记录二次验证失败，不记录提交的验证码
+func (uc *MFAUseCase) logFailure(userID, method string, reason error) {
+	uc.events.Warn("Step-up verification failed",
+		zap.String("event", EventStepUpFailed),
+		zap.String("user_id", userID),
+		zap.String("method", method),
+		zap.String("reason", reason.Error()),
+	)
+}
+
+// normalizeRecoveryCode 忽略大小写、空格与分隔符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/dedata/dedata-backend/pkg/totp"
+	"go.uber.org/zap"
+)
+
+const testAdminID = "00000000-0000-0000-0000-000000000001"
+
+// memoryTOTPRepo 内存中的 TOTP 绑定，AdvanceStep 与数据库实现一样是条件更新
+// stale 为 true 时 FindByUserID 始终返回最初的快照，模拟并发请求在对方更新前读取了绑定
+type memoryTOTPRepo struct {
+	mu         sync.Mutex
+	enrollment entity.TOTPEnrollment
+	snapshot   entity.TOTPEnrollment
+	stale      bool
+}
+
+func (r *memoryTOTPRepo) Save(context.Context, *entity.TOTPEnrollment) error { return nil }
+
+func (r *memoryTOTPRepo) FindByUserID(context.Context, string) (*entity.TOTPEnrollment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.enrollment
+	if r.stale {
+		e = r.snapshot
+	}
+	return &e, nil
+}
+
+func (r *memoryTOTPRepo) Confirm(context.Context, string, int64, time.Time) error { return nil }
+
+func (r *memoryTOTPRepo) AdvanceStep(_ context.Context, _ string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.enrollment.LastUsedStep >= step {
+		return false, nil
+	}
+	r.enrollment.LastUsedStep = step
+	return true, nil
+}
+
+func (r *memoryTOTPRepo) Delete(context.Context, string) error { return nil }
+
+func (r *memoryTOTPRepo) ReplaceRecoveryCodes(context.Context, string, []string) error { return nil }
+
+func (r *memoryTOTPRepo) ConsumeRecoveryCode(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func (r *memoryTOTPRepo) CountRecoveryCodes(context.Context, string) (int64, error) { return 0, nil }
+
+type memoryStepUpRepo struct{}
+
+func (memoryStepUpRepo) Grant(context.Context, string, time.Duration) error { return nil }
+
+func (memoryStepUpRepo) ExpiresAt(context.Context, string) (*time.Time, error) { return nil, nil }
+
+func (memoryStepUpRepo) Revoke(context.Context, string) error { return nil }
+
+// unlimitedRate 不限流，测试只关注重放
+type unlimitedRate struct{}
+
+func (unlimitedRate) Allow(context.Context, string, int, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (unlimitedRate) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 1, nil
+}
+
+// newTestMFA 返回已确认绑定的 MFAUseCase 与 TOTP 明文密钥
+func newTestMFA(t *testing.T) (*MFAUseCase, *memoryTOTPRepo, string) {
+	t.Helper()
+	cipher, err := crypto.NewSecretCipher(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := cipher.Encrypt([]byte(secret), []byte(testAdminID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirmedAt := time.Now()
+	enrollment := entity.TOTPEnrollment{UserID: testAdminID, SecretEncrypted: encrypted, ConfirmedAt: &confirmedAt}
+	repo := &memoryTOTPRepo{enrollment: enrollment, snapshot: enrollment}
+	uc := NewMFAUseCase(repo, memoryStepUpRepo{}, unlimitedRate{}, nil, cipher, &config.TOTPConfig{}, zap.NewNop())
+	return uc, repo, secret
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestVerifyStepUpRejectsReplay(t *testing.T) {
+	uc, _, secret := newTestMFA(t)
+	ctx := context.Background()
+	req := &dto.StepUpRequest{Code: currentCode(t, secret)}
+
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session-1", req); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	// 同一验证码在另一个会话中再次提交
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session-2", req); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Fatalf("replay error = %v, want ErrInvalidTOTPCode", err)
+	}
+}
+
+func TestVerifyStepUpRejectsSameStepReplayWithStaleRead(t *testing.T) {
+	// 两个请求都在对方更新前读取了绑定 (LastUsedStep 仍为 0)，只能由 AdvanceStep 拒绝重放
+	uc, repo, secret := newTestMFA(t)
+	repo.stale = true
+	ctx := context.Background()
+	req := &dto.StepUpRequest{Code: currentCode(t, secret)}
+
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session-1", req); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session-2", req); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Fatalf("replay error = %v, want ErrInvalidTOTPCode", err)
+	}
+}
+
+func TestVerifyStepUpConcurrentSameCode(t *testing.T) {
+	uc, repo, secret := newTestMFA(t)
+	repo.stale = true
+	ctx := context.Background()
+	req := &dto.StepUpRequest{Code: currentCode(t, secret)}
+
+	const workers = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.VerifyStepUp(ctx, testAdminID, "session", req)
+			if err != nil && !errors.Is(err, ErrInvalidTOTPCode) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err == nil {
+				mu.Lock()
+				successes++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if successes != 1 {
+		t.Errorf("%d concurrent verifications succeeded, want exactly 1", successes)
+	}
+}
+
+func TestVerifyStepUpRejectsOlderStep(t *testing.T) {
+	// 使用过较新的时间步后，时钟偏差窗口内更早的验证码同样被拒绝
+	uc, repo, secret := newTestMFA(t)
+	ctx := context.Background()
+	now := totp.Step(time.Now())
+
+	next, err := totp.Code(secret, now+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session", &dto.StepUpRequest{Code: next}); err != nil {
+		t.Fatalf("next-step code: %v", err)
+	}
+	if repo.enrollment.LastUsedStep != now+1 {
+		t.Fatalf("LastUsedStep = %d, want %d", repo.enrollment.LastUsedStep, now+1)
+	}
+
+	previous, err := totp.Code(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.VerifyStepUp(ctx, testAdminID, "session", &dto.StepUpRequest{Code: previous}); !errors.Is(err, ErrInvalidTOTPCode) {
+		t.Fatalf("older code error = %v, want ErrInvalidTOTPCode", err)
+	}
+}
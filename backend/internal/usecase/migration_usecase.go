@@ -0,0 +1,619 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	dbRepo "github.com/dedata/dedata-backend/internal/infrastructure/database"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/dedata/dedata-backend/pkg/eip712"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMigrationNotFound 迁移记录不存在或不属于当前用户
+	ErrMigrationNotFound = errors.New("migration not found")
+
+	// ErrMigrationPending 用户已有等待批准的迁移
+	ErrMigrationPending = errors.New("a wallet migration is already pending")
+
+	// ErrMigrationNotPending 迁移已完成、取消或被拒绝
+	ErrMigrationNotPending = errors.New("migration is not pending")
+
+	// ErrMigrationLocked 时间锁尚未到期
+	ErrMigrationLocked = errors.New("migration time lock has not elapsed")
+
+	// ErrInvalidMigrationStatus 无效的迁移状态
+	ErrInvalidMigrationStatus = errors.New("invalid migration status")
+
+	// ErrMigrationUnsupportedChain 主钱包不是 EVM 钱包，暂不支持迁移
+	ErrMigrationUnsupportedChain = errors.New("wallet migration is only supported for EVM accounts")
+
+	// ErrRecoveryRateLimited 同一 DID 或 IP 发起恢复过于频繁
+	ErrRecoveryRateLimited = errors.New("too many recovery requests, try again later")
+)
+
+// MigrationUseCase 账户迁移到新钱包地址用例
+type MigrationUseCase struct {
+	migrationRepo repository.MigrationRepository
+	challengeRepo repository.MigrationChallengeRepository
+	userRepo      repository.UserRepository
+	walletRepo    repository.WalletRepository
+	rateLimitRepo repository.RateLimitRepository
+	authUseCase   *AuthUseCase
+	sigVerifier   crypto.SignatureVerifier
+	config        *config.AuthConfig
+	logger        *zap.Logger
+}
+
+func NewMigrationUseCase(
+	migrationRepo repository.MigrationRepository,
+	challengeRepo repository.MigrationChallengeRepository,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	rateLimitRepo repository.RateLimitRepository,
+	authUseCase *AuthUseCase,
+	sigVerifier crypto.SignatureVerifier,
+	cfg *config.AuthConfig,
+	logger *zap.Logger,
+) *MigrationUseCase {
+	return &MigrationUseCase{
+		migrationRepo: migrationRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		walletRepo:    walletRepo,
+		rateLimitRepo: rateLimitRepo,
+		authUseCase:   authUseCase,
+		sigVerifier:   sigVerifier,
+		config:        cfg,
+		logger:        logger,
+	}
+}
+
+// ListMigrations 列出用户的迁移历史
+func (uc *MigrationUseCase) ListMigrations(ctx context.Context, userID string) ([]*entity.WalletMigration, error) {
+	return uc.migrationRepo.FindByUserID(ctx, userID)
+}
+
+// CreateMigrationChallenge 为当前会话生成 HANDOVER 迁移挑战，新旧钱包都需对其签名
+func (uc *MigrationUseCase) CreateMigrationChallenge(ctx context.Context, userID, sessionID string, req *dto.MigrationChallengeRequest) (*dto.MigrationChallengeResponse, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return uc.createChallenge(ctx, user, entity.MigrationHandover, sessionID, req.Address, req.ChainID)
+}
+
+// Migrate 验证新旧钱包对挑战的签名并立即完成迁移
+func (uc *MigrationUseCase) Migrate(ctx context.Context, userID, sessionID string, req *dto.MigrateWalletRequest) (*entity.WalletMigration, error) {
+	challenge, err := uc.consumeChallenge(ctx, req.Nonce, entity.MigrationHandover)
+	if err != nil {
+		return nil, err
+	}
+
+	// 挑战必须由同一用户的同一会话发起
+	if challenge.UserID != userID || challenge.SessionID != sessionID {
+		uc.logger.Warn("Migration challenge used from another session",
+			zap.String("user_id", userID),
+			zap.String("challenge_user_id", challenge.UserID),
+		)
+		return nil, fmt.Errorf("migration challenge does not belong to this session")
+	}
+
+	// 旧钱包在其所在链上签名移交，新钱包签名证明控制权
+	if _, err := uc.verify(ctx, challenge, challenge.FromChainID, req.SignatureType, req.OldSignature, challenge.FromAddress); err != nil {
+		return nil, err
+	}
+	keyVerified, err := uc.verify(ctx, challenge, challenge.ChainID, req.SignatureType, req.NewSignature, challenge.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 再次检查，防止挑战期间新钱包被其他账户绑定
+	if err := uc.checkTarget(ctx, userID, challenge.ToAddress); err != nil {
+		return nil, err
+	}
+
+	// 当前钱包的签名证明用户仍持有旧钱包，等待中的恢复请求 (可能由他人提交) 被取代
+	if err := uc.supersedeRecovery(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	migration := &entity.WalletMigration{
+		UserID:      userID,
+		FromAddress: challenge.FromAddress,
+		ToAddress:   challenge.ToAddress,
+		ChainID:     challenge.ChainID,
+		Mode:        entity.MigrationHandover,
+		KeyVerified: keyVerified,
+	}
+	if err := uc.execute(ctx, migration, ""); err != nil {
+		return nil, err
+	}
+
+	return migration, nil
+}
+
+// CreateRecoveryChallenge 为丢失旧钱包的账户生成 RECOVERY 挑战，只需新钱包签名
+// 无需登录，按客户端 IP 和 DID 限流
+func (uc *MigrationUseCase) CreateRecoveryChallenge(ctx context.Context, req *dto.RecoveryChallengeRequest, client *dto.ClientInfo) (*dto.MigrationChallengeResponse, error) {
+	if err := uc.checkRecoveryRate(ctx, req.DID, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByDID(ctx, req.DID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return uc.createChallenge(ctx, user, entity.MigrationRecovery, "", req.Address, req.ChainID)
+}
+
+// Recover 验证新钱包签名并创建等待时间锁和管理员批准的迁移
+func (uc *MigrationUseCase) Recover(ctx context.Context, req *dto.RecoverWalletRequest) (*entity.WalletMigration, error) {
+	challenge, err := uc.consumeChallenge(ctx, req.Nonce, entity.MigrationRecovery)
+	if err != nil {
+		return nil, err
+	}
+
+	keyVerified, err := uc.verify(ctx, challenge, challenge.ChainID, req.SignatureType, req.Signature, challenge.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.migrationRepo.FindPendingByUserID(ctx, challenge.UserID); err == nil {
+		return nil, ErrMigrationPending
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find pending migration: %w", err)
+	}
+
+	if err := uc.checkTarget(ctx, challenge.UserID, challenge.ToAddress); err != nil {
+		return nil, err
+	}
+
+	unlocksAt := time.Now().Add(uc.config.MigrationTimelock())
+	migration := &entity.WalletMigration{
+		UserID:      challenge.UserID,
+		FromAddress: challenge.FromAddress,
+		ToAddress:   challenge.ToAddress,
+		ChainID:     challenge.ChainID,
+		Mode:        entity.MigrationRecovery,
+		Status:      entity.MigrationPending,
+		KeyVerified: keyVerified,
+		UnlocksAt:   &unlocksAt,
+	}
+	if err := uc.migrationRepo.Create(ctx, migration); err != nil {
+		return nil, fmt.Errorf("failed to create migration: %w", err)
+	}
+
+	uc.logger.Info("Wallet recovery requested",
+		zap.String("migration_id", migration.ID),
+		zap.String("user_id", migration.UserID),
+		zap.String("to", migration.ToAddress),
+		zap.Time("unlocks_at", unlocksAt),
+	)
+
+	return migration, nil
+}
+
+// CancelMigration 用户在时间锁内取消等待中的迁移 (例如旧钱包并未丢失)
+func (uc *MigrationUseCase) CancelMigration(ctx context.Context, userID, migrationID string) error {
+	migration, err := uc.findMigration(ctx, migrationID)
+	if err != nil {
+		return err
+	}
+	if migration.UserID != userID {
+		return ErrMigrationNotFound
+	}
+	if !migration.IsPending() {
+		return ErrMigrationNotPending
+	}
+
+	if err := uc.close(ctx, migration.ID, entity.MigrationCancelled, "", "cancelled by account owner"); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Wallet migration cancelled",
+		zap.String("migration_id", migration.ID),
+		zap.String("user_id", userID),
+	)
+
+	return nil
+}
+
+// ListMigrationsByStatus 按状态查询迁移记录（管理后台）
+func (uc *MigrationUseCase) ListMigrationsByStatus(ctx context.Context, status entity.MigrationStatus, page, limit int) (*dto.AdminMigrationListResponse, error) {
+	switch status {
+	case "", entity.MigrationPending, entity.MigrationCompleted, entity.MigrationCancelled, entity.MigrationRejected:
+	default:
+		return nil, ErrInvalidMigrationStatus
+	}
+
+	migrations, total, err := uc.migrationRepo.FindByStatus(ctx, status, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &dto.AdminMigrationListResponse{
+		Data: migrations,
+		Pagination: dto.Pagination{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// ApproveMigration 管理员批准时间锁已到期的迁移并立即执行
+func (uc *MigrationUseCase) ApproveMigration(ctx context.Context, adminID, migrationID string) (*entity.WalletMigration, error) {
+	migration, err := uc.findMigration(ctx, migrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !migration.IsPending() {
+		return nil, ErrMigrationNotPending
+	}
+	if !migration.IsUnlocked() {
+		return nil, ErrMigrationLocked
+	}
+
+	// 时间锁期间新钱包可能已被其他账户绑定
+	if err := uc.checkTarget(ctx, migration.UserID, migration.ToAddress); err != nil {
+		return nil, err
+	}
+
+	if err := uc.execute(ctx, migration, adminID); err != nil {
+		return nil, err
+	}
+
+	return migration, nil
+}
+
+// RejectMigration 管理员拒绝等待中的迁移
+func (uc *MigrationUseCase) RejectMigration(ctx context.Context, adminID, migrationID string, req *dto.RejectMigrationRequest) (*entity.WalletMigration, error) {
+	migration, err := uc.findMigration(ctx, migrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !migration.IsPending() {
+		return nil, ErrMigrationNotPending
+	}
+
+	if err := uc.close(ctx, migration.ID, entity.MigrationRejected, adminID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("Admin rejected wallet migration",
+		zap.String("admin_id", adminID),
+		zap.String("migration_id", migration.ID),
+		zap.String("user_id", migration.UserID),
+		zap.String("reason", req.Reason),
+	)
+
+	migration.Status = entity.MigrationRejected
+	migration.ReviewedBy = &adminID
+	if req.Reason != "" {
+		migration.Reason = &req.Reason
+	}
+	return migration, nil
+}
+
+// execute 执行迁移并吊销用户所有会话 (token 中的钱包地址已失效，需用新钱包重新登录)
+// 之后的 token 发放读取 User.WalletAddress，自动转到新地址
+func (uc *MigrationUseCase) execute(ctx context.Context, migration *entity.WalletMigration, reviewedBy string) error {
+	if err := uc.migrationRepo.Execute(ctx, migration, reviewedBy); err != nil {
+		if errors.Is(err, dbRepo.ErrMigrationConflict) {
+			return ErrMigrationNotPending
+		}
+		return fmt.Errorf("failed to migrate wallet: %w", err)
+	}
+
+	if err := uc.authUseCase.RevokeAllSessions(ctx, migration.UserID); err != nil {
+		uc.logger.Error("Failed to revoke sessions after wallet migration",
+			zap.String("user_id", migration.UserID),
+			zap.Error(err),
+		)
+	}
+
+	uc.logger.Info("Wallet migrated",
+		zap.String("migration_id", migration.ID),
+		zap.String("user_id", migration.UserID),
+		zap.String("mode", string(migration.Mode)),
+		zap.String("from", migration.FromAddress),
+		zap.String("to", migration.ToAddress),
+		zap.String("reviewed_by", reviewedBy),
+	)
+
+	return nil
+}
+
+// close 关闭等待中的迁移
+func (uc *MigrationUseCase) close(ctx context.Context, migrationID string, status entity.MigrationStatus, reviewedBy, reason string) error {
+	if err := uc.migrationRepo.Close(ctx, migrationID, status, reviewedBy, reason); err != nil {
+		if errors.Is(err, dbRepo.ErrMigrationConflict) {
+			return ErrMigrationNotPending
+		}
+		return fmt.Errorf("failed to update migration: %w", err)
+	}
+	return nil
+}
+
+// createChallenge 校验新钱包并生成迁移挑战
+func (uc *MigrationUseCase) createChallenge(ctx context.Context, user *entity.User, mode entity.MigrationMode, sessionID, newAddress string, chainID int64) (*dto.MigrationChallengeResponse, error) {
+	// 1. 迁移消息与签名验证仅支持 EVM，主钱包为其他链家族 (例如 Solana) 的账户直接拒绝
+	if user.Namespace != string(chain.EIP155) {
+		return nil, ErrMigrationUnsupportedChain
+	}
+
+	// 2. 校验地址和链 ID
+	if !common.IsHexAddress(newAddress) {
+		return nil, fmt.Errorf("invalid wallet address")
+	}
+	address := common.HexToAddress(newAddress).Hex()
+
+	if chainID == 0 {
+		chainID = uc.config.ChainID
+	}
+	if !uc.config.IsChainAllowed(chainID) {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedChainID, chainID)
+	}
+
+	// 3. 新钱包不能属于其他账户，且同一时间只能有一个等待中的恢复；HANDOVER 可以取代等待中的恢复
+	if err := uc.checkTarget(ctx, user.ID, address); err != nil {
+		return nil, err
+	}
+	if mode == entity.MigrationRecovery {
+		if _, err := uc.migrationRepo.FindPendingByUserID(ctx, user.ID); err == nil {
+			return nil, ErrMigrationPending
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find pending migration: %w", err)
+		}
+	}
+
+	// 4. 生成挑战
+	nonce, err := crypto.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// 旧钱包的签名在其登录时所在的链上验证 (合约钱包只部署在该链)
+	fromChainID := int64(user.ChainID)
+	if fromChainID == 0 {
+		fromChainID = chainID
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	challenge := &entity.MigrationChallenge{
+		Nonce:       nonce,
+		Mode:        mode,
+		UserID:      user.ID,
+		SessionID:   sessionID,
+		DID:         user.DID,
+		FromAddress: common.HexToAddress(user.WalletAddress).Hex(),
+		FromChainID: fromChainID,
+		ToAddress:   address,
+		ChainID:     chainID,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(uc.config.NonceLifetime()),
+	}
+	challenge.Message = uc.migrationMessage(challenge)
+
+	if err := uc.challengeRepo.SaveMigrationChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to save migration challenge: %w", err)
+	}
+
+	typedData := uc.migrationTypedData(challenge, challenge.ChainID)
+
+	resp := &dto.MigrationChallengeResponse{
+		Nonce:       nonce,
+		Mode:        string(mode),
+		FromAddress: challenge.FromAddress,
+		FromChainID: challenge.FromChainID,
+		ToAddress:   challenge.ToAddress,
+		ChainID:     challenge.ChainID,
+		Message:     challenge.Message,
+		TypedData:   &typedData,
+		IssuedAt:    challenge.IssuedAt.Format(time.RFC3339),
+		ExpiresAt:   challenge.ExpiresAt.Format(time.RFC3339),
+	}
+	if mode == entity.MigrationHandover {
+		fromTypedData := uc.migrationTypedData(challenge, challenge.FromChainID)
+		resp.FromTypedData = &fromTypedData
+	} else {
+		resp.UnlocksAt = now.Add(uc.config.MigrationTimelock()).Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+// checkRecoveryRate 发起恢复前按 IP 和 DID 限流 (每天)
+// 先检查 IP，被限流的 IP 不再消耗 DID 的次数，避免他人反复提交恢复占用该 DID 的额度
+func (uc *MigrationUseCase) checkRecoveryRate(ctx context.Context, did, ip string) error {
+	if ip != "" {
+		if err := uc.allowRecovery(ctx, "recovery:ip:"+ip, uc.config.RecoveryIPLimit(), did, ip); err != nil {
+			return err
+		}
+	}
+	return uc.allowRecovery(ctx, "recovery:did:"+did, uc.config.RecoveryDIDLimit(), did, ip)
+}
+
+func (uc *MigrationUseCase) allowRecovery(ctx context.Context, key string, limit int, did, ip string) error {
+	allowed, err := uc.rateLimitRepo.Allow(ctx, key, limit, 24*time.Hour)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if !allowed {
+		uc.logger.Warn("Recovery rate limit exceeded",
+			zap.String("did", did),
+			zap.String("ip", ip),
+		)
+		return ErrRecoveryRateLimited
+	}
+	return nil
+}
+
+// supersedeRecovery 取消用户等待中的恢复请求，没有时不做任何事
+func (uc *MigrationUseCase) supersedeRecovery(ctx context.Context, userID string) error {
+	pending, err := uc.migrationRepo.FindPendingByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find pending migration: %w", err)
+	}
+
+	if err := uc.close(ctx, pending.ID, entity.MigrationCancelled, "", "superseded by wallet handover"); err != nil {
+		return err
+	}
+
+	uc.logger.Info("Pending wallet recovery superseded by handover",
+		zap.String("migration_id", pending.ID),
+		zap.String("user_id", userID),
+		zap.String("to", pending.ToAddress),
+	)
+	return nil
+}
+
+// consumeChallenge 原子地取出挑战，保证只能使用一次
+func (uc *MigrationUseCase) consumeChallenge(ctx context.Context, nonce string, mode entity.MigrationMode) (*entity.MigrationChallenge, error) {
+	challenge, err := uc.challengeRepo.ConsumeMigrationChallenge(ctx, nonce)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, fmt.Errorf("migration challenge not found or expired")
+		}
+		return nil, fmt.Errorf("failed to find migration challenge: %w", err)
+	}
+	if challenge.IsExpired() {
+		return nil, fmt.Errorf("migration challenge has expired")
+	}
+	if challenge.Mode != mode {
+		return nil, fmt.Errorf("migration challenge mode mismatch")
+	}
+	return challenge, nil
+}
+
+// verify 验证钱包在 chainID 上对迁移挑战的签名 (EOA 或 EIP-1271 合约钱包)
+// 返回签名能否通过 ecrecover 恢复出地址 (EOA)：此时该地址在所有 EVM 链上都由用户控制，迁移后的主钱包可以在其他链上接收奖励
+func (uc *MigrationUseCase) verify(ctx context.Context, challenge *entity.MigrationChallenge, chainID int64, signatureType, signature, address string) (bool, error) {
+	check := func(v crypto.SignatureVerifier) (bool, error) {
+		switch signatureType {
+		case "", dto.SignatureTypePersonalSign:
+			return crypto.VerifyPersonalSign(ctx, v, chainID, challenge.Message, signature, address)
+		case dto.SignatureTypeEIP712:
+			return eip712.Verify(ctx, v, uc.migrationTypedData(challenge, chainID), signature, address)
+		default:
+			return false, fmt.Errorf("unsupported signature type: %s", signatureType)
+		}
+	}
+
+	valid, err := check(uc.sigVerifier)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if !valid {
+		uc.logger.Warn("Invalid migration signature", zap.String("expected_address", address))
+		return false, fmt.Errorf("invalid signature from %s", address)
+	}
+
+	eoa, err := check(crypto.NewECDSAVerifier())
+	return err == nil && eoa, nil
+}
+
+// checkTarget 检查新钱包未绑定到其他账户，且不是当前主钱包 (已绑定到本账户的钱包可以提升为主钱包)
+func (uc *MigrationUseCase) checkTarget(ctx context.Context, userID, address string) error {
+	if wallet, err := uc.walletRepo.FindByAddress(ctx, address); err == nil {
+		if wallet.UserID != userID {
+			return ErrWalletAlreadyLinked
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	if user, err := uc.userRepo.FindByAddress(ctx, address); err == nil {
+		if user.ID != userID {
+			return ErrWalletAlreadyLinked
+		}
+		return fmt.Errorf("wallet is already the primary wallet")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *MigrationUseCase) findMigration(ctx context.Context, migrationID string) (*entity.WalletMigration, error) {
+	migration, err := uc.migrationRepo.FindByID(ctx, migrationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMigrationNotFound
+		}
+		return nil, fmt.Errorf("failed to find migration: %w", err)
+	}
+	return migration, nil
+}
+
+// migrationMessage 构造 personal_sign 迁移消息
+func (uc *MigrationUseCase) migrationMessage(challenge *entity.MigrationChallenge) string {
+	var b strings.Builder
+	if challenge.Mode == entity.MigrationRecovery {
+		b.WriteString(uc.config.Domain + " wants you to recover your DeData account with this wallet:\n")
+	} else {
+		b.WriteString(uc.config.Domain + " wants you to move your DeData account to a new wallet:\n")
+	}
+	b.WriteString(challenge.ToAddress + "\n")
+	b.WriteString("\n")
+	b.WriteString("Account: " + challenge.DID + "\n")
+	b.WriteString("From: " + challenge.FromAddress + "\n")
+	b.WriteString("From Chain ID: " + strconv.FormatInt(challenge.FromChainID, 10) + "\n")
+	b.WriteString("Mode: " + string(challenge.Mode) + "\n")
+	b.WriteString("URI: " + uc.config.URI + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatInt(challenge.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + challenge.Nonce + "\n")
+	b.WriteString("Issued At: " + challenge.IssuedAt.Format(time.RFC3339) + "\n")
+	b.WriteString("Expiration Time: " + challenge.ExpiresAt.Format(time.RFC3339))
+	return b.String()
+}
+
+// migrationTypedData 构造 EIP-712 迁移消息，domain 使用签名钱包所在的链
+// 旧钱包签名时为 FromChainID，新钱包签名时为 ChainID
+func (uc *MigrationUseCase) migrationTypedData(challenge *entity.MigrationChallenge, chainID int64) apitypes.TypedData {
+	domain := eip712.Domain{
+		Name:              uc.config.EIP712.Name,
+		Version:           uc.config.EIP712.Version,
+		ChainID:           chainID,
+		VerifyingContract: uc.config.EIP712.VerifyingContract,
+	}
+
+	return eip712.NewTypedData(domain, eip712.MigrateWalletSchema, apitypes.TypedDataMessage{
+		"account":        challenge.DID,
+		"fromWallet":     challenge.FromAddress,
+		"fromChainId":    strconv.FormatInt(challenge.FromChainID, 10),
+		"toWallet":       challenge.ToAddress,
+		"mode":           string(challenge.Mode),
+		"chainId":        strconv.FormatInt(challenge.ChainID, 10),
+		"nonce":          challenge.Nonce,
+		"issuedAt":       challenge.IssuedAt.Format(time.RFC3339),
+		"expirationTime": challenge.ExpiresAt.Format(time.RFC3339),
+	})
+}
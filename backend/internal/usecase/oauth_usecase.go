@@ -0,0 +1,651 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOAuthClientNotFound OAuth 客户端不存在
+	ErrOAuthClientNotFound = errors.New("oauth client not found")
+
+	// ErrInvalidRedirectURI 回调地址未登记或格式不合法
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+
+	// ErrInvalidOAuthRequest 授权或 token 请求缺少参数或参数不合法
+	ErrInvalidOAuthRequest = errors.New("invalid request")
+
+	// ErrUnsupportedResponseType 只支持 response_type=code
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+
+	// ErrInvalidClient client_id 不存在或 client_secret 错误
+	ErrInvalidClient = errors.New("invalid client")
+
+	// ErrInvalidGrant 授权码无效、已使用、已过期，或与客户端、回调地址、PKCE 不匹配
+	ErrInvalidGrant = errors.New("invalid grant")
+
+	// ErrUnsupportedGrantType 只支持 grant_type=authorization_code
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+
+	// ErrInvalidAccessToken OAuth access token 无效、已过期或授权已被吊销
+	ErrInvalidAccessToken = errors.New("invalid access token")
+)
+
+// idTokenClaims OpenID Connect ID token，sub 为用户 DID
+type idTokenClaims struct {
+	DID   string `json:"did"`
+	Nonce string `json:"nonce,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// OAuthUseCase OAuth2 / OpenID Connect 授权服务器 ("Log in with DeData")
+// 用户通过钱包登录后在同意页授权，授权记录为 Authorization (Platform 为 client_id)，吊销授权后 access token 立即失效
+type OAuthUseCase struct {
+	clientRepo        repository.OAuthClientRepository
+	tokenRepo         repository.OAuthTokenRepository
+	authorizationRepo repository.AuthorizationRepository
+	userRepo          repository.UserRepository
+	profileRepo       repository.ProfileRepository
+	checkinRepo       repository.CheckInRepository
+	walletRepo        repository.WalletRepository
+	credentialRepo    repository.CredentialRepository
+	jwtMgr            *pkgJWT.JWTManager
+	authzConfig       *config.AuthorizationConfig
+	config            *config.OAuthConfig
+	checkinConfig     *config.CheckInConfig
+	checkinLoc        *time.Location // 签到日切换时区，用于判断连续签到是否中断
+	logger            *zap.Logger
+}
+
+func NewOAuthUseCase(
+	clientRepo repository.OAuthClientRepository,
+	tokenRepo repository.OAuthTokenRepository,
+	authorizationRepo repository.AuthorizationRepository,
+	userRepo repository.UserRepository,
+	profileRepo repository.ProfileRepository,
+	checkinRepo repository.CheckInRepository,
+	walletRepo repository.WalletRepository,
+	credentialRepo repository.CredentialRepository,
+	jwtMgr *pkgJWT.JWTManager,
+	authzCfg *config.AuthorizationConfig,
+	cfg *config.OAuthConfig,
+	checkinCfg *config.CheckInConfig,
+	checkinLoc *time.Location,
+	logger *zap.Logger,
+) *OAuthUseCase {
+	return &OAuthUseCase{
+		clientRepo:        clientRepo,
+		tokenRepo:         tokenRepo,
+		authorizationRepo: authorizationRepo,
+		userRepo:          userRepo,
+		profileRepo:       profileRepo,
+		checkinRepo:       checkinRepo,
+		walletRepo:        walletRepo,
+		credentialRepo:    credentialRepo,
+		jwtMgr:            jwtMgr,
+		authzConfig:       authzCfg,
+		config:            cfg,
+		checkinConfig:     checkinCfg,
+		checkinLoc:        checkinLoc,
+		logger:            logger,
+	}
+}
+
+// RegisterClient 注册 OAuth 客户端，机密客户端的 client_secret 只在此时返回一次
+func (uc *OAuthUseCase) RegisterClient(ctx context.Context, adminID string, req *dto.RegisterOAuthClientRequest) (*dto.OAuthClientResponse, error) {
+	for _, uri := range req.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return nil, err
+		}
+	}
+
+	scope, err := normalizeScope(strings.Join(req.Scopes, " "), func(s string) bool {
+		return (s == entity.ScopeOpenID && uc.supportsOpenID()) || isKnownScope(s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := &entity.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: strings.Join(req.RedirectURIs, " "),
+		Scope:        scope,
+		CreatedBy:    adminID,
+	}
+
+	var secret string
+	if !req.Public {
+		secret, err = crypto.RandomHex(32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate client secret: %w", err)
+		}
+		client.SecretHash = crypto.HashToken(secret)
+	}
+
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return nil, fmt.Errorf("failed to create oauth client: %w", err)
+	}
+
+	uc.logger.Info("OAuth client registered",
+		zap.String("client_id", client.ID),
+		zap.String("name", client.Name),
+		zap.String("admin_id", adminID),
+	)
+
+	resp := toOAuthClientResponse(client)
+	resp.ClientSecret = secret
+	return resp, nil
+}
+
+// ListClients 列出所有 OAuth 客户端
+func (uc *OAuthUseCase) ListClients(ctx context.Context) ([]*dto.OAuthClientResponse, error) {
+	clients, err := uc.clientRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+
+	resp := make([]*dto.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		resp = append(resp, toOAuthClientResponse(client))
+	}
+	return resp, nil
+}
+
+// DeleteClient 删除 OAuth 客户端并吊销用户对它的全部授权，已签发的 access token 随之失效
+func (uc *OAuthUseCase) DeleteClient(ctx context.Context, adminID, clientID string) error {
+	if _, err := uc.findClient(ctx, clientID); err != nil {
+		return err
+	}
+
+	if err := uc.authorizationRepo.RevokeByPlatform(ctx, clientID); err != nil {
+		return fmt.Errorf("failed to revoke authorizations: %w", err)
+	}
+	if err := uc.clientRepo.Delete(ctx, clientID); err != nil {
+		return fmt.Errorf("failed to delete oauth client: %w", err)
+	}
+
+	uc.logger.Info("OAuth client deleted", zap.String("client_id", clientID), zap.String("admin_id", adminID))
+	return nil
+}
+
+// GetConsent 校验授权请求并返回同意页需要展示的信息
+func (uc *OAuthUseCase) GetConsent(ctx context.Context, userID string, req *dto.AuthorizeRequest) (*dto.OAuthConsentResponse, error) {
+	client, scope, err := uc.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.findActiveAuthorization(ctx, userID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := strings.Fields(scope)
+
+	return &dto.OAuthConsentResponse{
+		ClientID:        client.ID,
+		ClientName:      client.Name,
+		Scopes:          scopes,
+		ConsentRequired: existing == nil || !existing.HasScopes(scopes...),
+	}, nil
+}
+
+// Authorize 处理用户在同意页的决定：同意时记录授权并签发授权码，拒绝时返回 access_denied
+// 返回前端应跳转的回调地址
+func (uc *OAuthUseCase) Authorize(ctx context.Context, userID string, req *dto.AuthorizeDecisionRequest) (*dto.AuthorizeResponse, error) {
+	client, scope, err := uc.validateAuthorizeRequest(ctx, &req.AuthorizeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Approve {
+		return &dto.AuthorizeResponse{
+			RedirectURI: buildRedirect(req.RedirectURI, url.Values{"error": {"access_denied"}}, req.State),
+		}, nil
+	}
+
+	// 1. 记录授权，已有授权时合并 scope
+	if err := uc.grant(ctx, userID, client, scope); err != nil {
+		return nil, err
+	}
+
+	// 2. 签发授权码
+	code, err := crypto.RandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate authorization code: %w", err)
+	}
+	authCode := &entity.OAuthCode{
+		CodeHash:      crypto.HashToken(code),
+		ClientID:      client.ID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         scope,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		ExpiresAt:     time.Now().Add(uc.config.CodeLifetime()),
+	}
+	if err := uc.tokenRepo.SaveCode(ctx, authCode); err != nil {
+		return nil, fmt.Errorf("failed to save authorization code: %w", err)
+	}
+
+	return &dto.AuthorizeResponse{
+		RedirectURI: buildRedirect(req.RedirectURI, url.Values{"code": {code}}, req.State),
+	}, nil
+}
+
+// Exchange 用授权码兑换 access token 与 ID token (grant_type=authorization_code)
+func (uc *OAuthUseCase) Exchange(ctx context.Context, req *dto.TokenRequest) (*dto.TokenResponse, error) {
+	if req.GrantType != "authorization_code" {
+		return nil, ErrUnsupportedGrantType
+	}
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, fmt.Errorf("%w: code, redirect_uri and code_verifier are required", ErrInvalidOAuthRequest)
+	}
+
+	// 1. 客户端认证，公共客户端只依赖 PKCE
+	client, err := uc.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 原子地取出授权码，保证只能兑换一次
+	code, err := uc.tokenRepo.ConsumeCode(ctx, crypto.HashToken(req.Code))
+	if err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			return nil, fmt.Errorf("%w: authorization code not found or already used", ErrInvalidGrant)
+		}
+		return nil, fmt.Errorf("failed to find authorization code: %w", err)
+	}
+	if code.IsExpired() {
+		return nil, fmt.Errorf("%w: authorization code has expired", ErrInvalidGrant)
+	}
+	if code.ClientID != client.ID || code.RedirectURI != req.RedirectURI {
+		return nil, fmt.Errorf("%w: authorization code was issued to another client or redirect uri", ErrInvalidGrant)
+	}
+	if !verifyPKCE(code.CodeChallenge, req.CodeVerifier) {
+		return nil, fmt.Errorf("%w: code_verifier does not match code_challenge", ErrInvalidGrant)
+	}
+
+	// 3. 用户仍然有效且授权未被吊销
+	user, err := uc.userRepo.FindByID(ctx, code.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		return nil, fmt.Errorf("%w: account is suspended or blacklisted", ErrInvalidGrant)
+	}
+	authorization, err := uc.findActiveAuthorization(ctx, user.ID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil || !authorization.HasScopes(strings.Fields(code.Scope)...) {
+		return nil, fmt.Errorf("%w: authorization has been revoked", ErrInvalidGrant)
+	}
+
+	// 4. 签发 access token (不透明令牌，只保存摘要)
+	accessToken, err := crypto.RandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	now := time.Now()
+	lifetime := uc.config.TokenLifetime()
+	token := &entity.OAuthAccessToken{
+		TokenHash: crypto.HashToken(accessToken),
+		ClientID:  client.ID,
+		UserID:    user.ID,
+		Scope:     code.Scope,
+		ExpiresAt: now.Add(lifetime),
+	}
+	if err := uc.tokenRepo.SaveAccessToken(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to save access token: %w", err)
+	}
+
+	resp := &dto.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(lifetime.Seconds()),
+		Scope:       code.Scope,
+	}
+
+	// 5. 请求了 openid 时签发 ID token，只使用非对称密钥签名 (kid 与 JWKS 一致)，不会用 HS256 共享密钥
+	if token.HasScope(entity.ScopeOpenID) {
+		idToken, err := uc.jwtMgr.SignWithKeyID(&idTokenClaims{
+			DID:   user.DID,
+			Nonce: code.Nonce,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    uc.jwtMgr.Issuer(),
+				Subject:   user.DID,
+				Audience:  jwt.ClaimStrings{client.ID},
+				ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		}, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign id token: %w", err)
+		}
+		resp.IDToken = idToken
+	}
+
+	uc.logger.Info("OAuth tokens issued",
+		zap.String("client_id", client.ID),
+		zap.String("user_id", user.ID),
+		zap.String("scope", code.Scope),
+	)
+
+	return resp, nil
+}
+
+// UserInfo 返回 access token 对应用户的信息，只包含当前仍被授权的 scope
+func (uc *OAuthUseCase) UserInfo(ctx context.Context, accessToken string) (*dto.UserInfoResponse, error) {
+	token, err := uc.tokenRepo.FindAccessToken(ctx, crypto.HashToken(accessToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) {
+			return nil, ErrInvalidAccessToken
+		}
+		return nil, fmt.Errorf("failed to find access token: %w", err)
+	}
+	if token.IsExpired() {
+		return nil, ErrInvalidAccessToken
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		return nil, ErrInvalidAccessToken
+	}
+
+	authorization, err := uc.findActiveAuthorization(ctx, user.ID, token.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	resp := &dto.UserInfoResponse{Sub: user.DID, DID: user.DID}
+	for _, scope := range token.Scopes() {
+		if !authorization.HasScopes(scope) {
+			continue
+		}
+		if err := uc.fillUserInfo(ctx, resp, user, scope); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// OpenIDConfiguration 返回 OpenID Connect Discovery 元数据
+// 未配置非对称密钥时不声明 openid scope 与任何 ID token 签名算法
+func (uc *OAuthUseCase) OpenIDConfiguration() *dto.OpenIDConfiguration {
+	issuer := strings.TrimSuffix(uc.jwtMgr.Issuer(), "/")
+	scopes := entity.KnownScopes
+	algs := []string{}
+	if uc.supportsOpenID() {
+		scopes = append([]string{entity.ScopeOpenID}, entity.KnownScopes...)
+		algs = []string{uc.jwtMgr.SigningAlg()}
+	}
+	return &dto.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             uc.config.AuthorizeURL,
+		TokenEndpoint:                     issuer + "/api/oauth/token",
+		UserInfoEndpoint:                  issuer + "/api/oauth/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   scopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  algs,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "did", "iss", "aud", "exp", "iat", "nonce", "name", "picture"},
+	}
+}
+
+// validateAuthorizeRequest 校验客户端、回调地址、PKCE 与 scope，返回客户端与规范化后的 scope
+func (uc *OAuthUseCase) validateAuthorizeRequest(ctx context.Context, req *dto.AuthorizeRequest) (*entity.OAuthClient, string, error) {
+	client, err := uc.findClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, "", ErrInvalidRedirectURI
+	}
+	if req.ResponseType != "code" {
+		return nil, "", ErrUnsupportedResponseType
+	}
+
+	// 所有客户端都必须使用 PKCE (S256)
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return nil, "", fmt.Errorf("%w: code_challenge with code_challenge_method S256 is required", ErrInvalidOAuthRequest)
+	}
+
+	scope, err := normalizeScope(req.Scope, func(s string) bool {
+		return client.AllowsScope(s) && (s != entity.ScopeOpenID || uc.supportsOpenID())
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return client, scope, nil
+}
+
+// supportsOpenID 只有配置了非对称签发密钥时才支持 openid：HS256 签名的 ID token 第三方无法验证，
+// 且共享密钥同时用于签发 access token
+func (uc *OAuthUseCase) supportsOpenID() bool {
+	return uc.jwtMgr.HasSigningKey()
+}
+
+// grant 记录用户对客户端的授权，已有授权覆盖全部 scope 时保持不变，否则合并 scope 后替换
+func (uc *OAuthUseCase) grant(ctx context.Context, userID string, client *entity.OAuthClient, scope string) error {
+	existing, err := uc.findActiveAuthorization(ctx, userID, client.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.HasScopes(strings.Fields(scope)...) {
+			return nil
+		}
+		scope, err = normalizeScope(existing.Scope+" "+scope, func(string) bool { return true })
+		if err != nil {
+			return err
+		}
+	}
+
+	expiresAt := time.Now().Add(uc.authzConfig.GrantLifetime(0))
+	authorization := &entity.Authorization{
+		UserID:    userID,
+		Platform:  client.ID,
+		Scope:     scope,
+		Status:    entity.AuthActive,
+		ExpiresAt: &expiresAt,
+	}
+	if err := uc.authorizationRepo.Grant(ctx, authorization); err != nil {
+		return fmt.Errorf("failed to create authorization: %w", err)
+	}
+
+	uc.logger.Info("OAuth client authorized",
+		zap.String("user_id", userID),
+		zap.String("client_id", client.ID),
+		zap.String("scope", scope),
+	)
+	return nil
+}
+
+// authenticateClient 校验 client_id 与 client_secret，公共客户端不能携带 secret
+func (uc *OAuthUseCase) authenticateClient(ctx context.Context, clientID, clientSecret string) (*entity.OAuthClient, error) {
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := uc.findClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrOAuthClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if client.IsPublic() {
+		if clientSecret != "" {
+			return nil, ErrInvalidClient
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(crypto.HashToken(clientSecret))) != 1 {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// fillUserInfo 按 scope 填充 userinfo 字段
+func (uc *OAuthUseCase) fillUserInfo(ctx context.Context, resp *dto.UserInfoResponse, user *entity.User, scope string) error {
+	switch scope {
+	case entity.ScopeProfileRead:
+		profile, err := uc.profileRepo.FindByUserID(ctx, user.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to find profile: %w", err)
+		}
+		resp.Name = profile.DisplayName
+		resp.Picture = profile.Avatar
+		resp.Bio = profile.Bio
+
+	case entity.ScopeCheckinsRead:
+		count, err := uc.checkinRepo.CountSuccessCheckinsByUserID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count check-ins: %w", err)
+		}
+		resp.CheckIns = &dto.CheckInStats{
+			SuccessfulCheckIns: count,
+			StreakDays:         user.StreakAt(checkInDay(time.Now(), uc.checkinLoc), uc.checkinConfig.StreakGraceDays),
+			TotalRewards:       user.TotalRewards,
+			LastCheckinAt:      user.LastCheckinAt,
+		}
+
+	case entity.ScopeWalletsRead:
+		wallets, err := uc.walletRepo.FindByUserID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find wallets: %w", err)
+		}
+		resp.Wallets = make([]string, 0, len(wallets))
+		for _, wallet := range wallets {
+			// 尚未回填 CAIP-10 标识的钱包在下次登录前不对外提供
+			if wallet.Account != "" {
+				resp.Wallets = append(resp.Wallets, wallet.Account)
+			}
+		}
+
+	case entity.ScopeCredentialsRead:
+		credentials, err := uc.credentialRepo.FindByUserID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find credentials: %w", err)
+		}
+		resp.Credentials = make([]string, 0, len(credentials))
+		for _, credential := range credentials {
+			if credential.IsRevoked() || time.Now().After(credential.ExpiresAt) {
+				continue
+			}
+			resp.Credentials = append(resp.Credentials, credential.Token)
+		}
+	}
+	return nil
+}
+
+func (uc *OAuthUseCase) findClient(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	client, err := uc.clientRepo.FindByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthClientNotFound
+		}
+		return nil, fmt.Errorf("failed to find oauth client: %w", err)
+	}
+	return client, nil
+}
+
+// findActiveAuthorization 查找用户对客户端仍然有效的授权，不存在时返回 nil
+func (uc *OAuthUseCase) findActiveAuthorization(ctx context.Context, userID, clientID string) (*entity.Authorization, error) {
+	authorization, err := uc.authorizationRepo.FindActive(ctx, userID, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find authorization: %w", err)
+	}
+	if !authorization.IsActive() {
+		return nil, nil
+	}
+	return authorization, nil
+}
+
+func toOAuthClientResponse(client *entity.OAuthClient) *dto.OAuthClientResponse {
+	return &dto.OAuthClientResponse{
+		ClientID:     client.ID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIList(),
+		Scopes:       client.Scopes(),
+		Public:       client.IsPublic(),
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// validateRedirectURI 回调地址必须是不带 fragment 的 https 地址，本地开发允许 http 回环地址
+func validateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" || u.Fragment != "" || strings.ContainsAny(uri, " \t\n") {
+		return fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+	}
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if host := u.Hostname(); host == "localhost" || host == "127.0.0.1" || host == "::1" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+}
+
+// verifyPKCE 校验 code_verifier (RFC 7636 S256)
+func verifyPKCE(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// buildRedirect 在回调地址上追加查询参数与 state
+func buildRedirect(redirectURI string, params url.Values, state string) string {
+	if state != "" {
+		params.Set("state", state)
+	}
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
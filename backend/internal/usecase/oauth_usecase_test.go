@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	pkgJWT "github.com/dedata/dedata-backend/pkg/jwt"
+	"go.uber.org/zap"
+)
+
+// newTestOAuth 只初始化 discovery 与 scope 校验用到的依赖；withKey 为 false 时 JWT 管理器处于 HS256 模式
+func newTestOAuth(t *testing.T, withKey bool) *OAuthUseCase {
+	t.Helper()
+	cfg := &config.JWTConfig{Secret: "test-secret", Issuer: "https://dedata.test"}
+	if withKey {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "jwt.pem")
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Keys = []config.JWTKeyConfig{{ID: "k1", PrivateKeyFile: path}}
+		cfg.SigningKeyID = "k1"
+	}
+	jwtMgr, err := pkgJWT.NewJWTManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &OAuthUseCase{jwtMgr: jwtMgr, config: &config.OAuthConfig{}, logger: zap.NewNop()}
+}
+
+func TestOpenIDConfigurationAdvertisesAsymmetricAlgOnly(t *testing.T) {
+	meta := newTestOAuth(t, true).OpenIDConfiguration()
+	if len(meta.IDTokenSigningAlgValuesSupported) != 1 || meta.IDTokenSigningAlgValuesSupported[0] != "ES256" {
+		t.Errorf("id_token_signing_alg_values_supported = %v, want [ES256]", meta.IDTokenSigningAlgValuesSupported)
+	}
+	if meta.ScopesSupported[0] != entity.ScopeOpenID {
+		t.Errorf("scopes_supported = %v, want openid", meta.ScopesSupported)
+	}
+
+	// HS256 模式下不声明 openid，也不声明任何 ID token 签名算法
+	meta = newTestOAuth(t, false).OpenIDConfiguration()
+	if len(meta.IDTokenSigningAlgValuesSupported) != 0 {
+		t.Errorf("HS256 mode advertises id_token_signing_alg_values_supported = %v", meta.IDTokenSigningAlgValuesSupported)
+	}
+	for _, scope := range meta.ScopesSupported {
+		if scope == entity.ScopeOpenID {
+			t.Errorf("HS256 mode advertises openid scope: %v", meta.ScopesSupported)
+		}
+	}
+}
+
+func TestOpenIDScopeRequiresAsymmetricKey(t *testing.T) {
+	uc := newTestOAuth(t, false)
+
+	_, err := uc.RegisterClient(context.Background(), "00000000-0000-0000-0000-000000000001", &dto.RegisterOAuthClientRequest{
+		Name:         "example",
+		RedirectURIs: []string{"https://client.example/callback"},
+		Scopes:       []string{entity.ScopeOpenID, entity.ScopeProfileRead},
+	})
+	if !errors.Is(err, ErrScopeNotAllowed) {
+		t.Fatalf("RegisterClient with openid in HS256 mode = %v, want ErrScopeNotAllowed", err)
+	}
+}
@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/webauthn"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPasskeyUnavailable 未配置 passkey.rp_id / origins
+	ErrPasskeyUnavailable = errors.New("passkey sign-in is not enabled")
+
+	// ErrPasskeyNotFound passkey 不存在或不属于当前用户
+	ErrPasskeyNotFound = errors.New("passkey not found")
+
+	// ErrPasskeyLimitReached 用户注册的 passkey 已达上限
+	ErrPasskeyLimitReached = errors.New("passkey limit reached")
+
+	// ErrPasskeyAlreadyRegistered 凭证已被注册
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+
+	// ErrInvalidPasskey 挑战无效或已过期、凭证未注册，或 WebAuthn 校验失败
+	ErrInvalidPasskey = errors.New("invalid passkey response")
+)
+
+// PasskeyUseCase WebAuthn passkey：钱包登录后注册，之后可不签名登录获得只读会话
+// 支付、钱包变更等写操作仍需要钱包签名登录 (由 AuthMiddleware 按会话 scope 拦截)
+type PasskeyUseCase struct {
+	passkeyRepo   repository.PasskeyRepository
+	challengeRepo repository.PasskeyChallengeRepository
+	userRepo      repository.UserRepository
+	auth          *AuthUseCase
+	security      *SecurityUseCase
+	rp            *webauthn.RelyingParty
+	config        *config.PasskeyConfig
+	logger        *zap.Logger
+}
+
+func NewPasskeyUseCase(
+	passkeyRepo repository.PasskeyRepository,
+	challengeRepo repository.PasskeyChallengeRepository,
+	userRepo repository.UserRepository,
+	auth *AuthUseCase,
+	security *SecurityUseCase,
+	cfg *config.PasskeyConfig,
+	logger *zap.Logger,
+) *PasskeyUseCase {
+	return &PasskeyUseCase{
+		passkeyRepo:   passkeyRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		auth:          auth,
+		security:      security,
+		rp:            &webauthn.RelyingParty{ID: cfg.RPID, Origins: cfg.Origins},
+		config:        cfg,
+		logger:        logger,
+	}
+}
+
+// RegistrationOptions 生成注册 passkey 的参数，挑战绑定到当前 (钱包登录) 会话
+func (uc *PasskeyUseCase) RegistrationOptions(ctx context.Context, userID, sessionID string) (*dto.PasskeyCreationOptions, error) {
+	if !uc.config.Enabled() {
+		return nil, ErrPasskeyUnavailable
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	passkeys, err := uc.passkeyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	if len(passkeys) >= uc.config.Limit() {
+		return nil, ErrPasskeyLimitReached
+	}
+
+	challenge, err := uc.newChallenge(ctx, entity.PasskeyChallengeRegistration, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]dto.PasskeyCredentialParam, 0, len(webauthn.SupportedAlgorithms))
+	for _, alg := range webauthn.SupportedAlgorithms {
+		params = append(params, dto.PasskeyCredentialParam{Type: "public-key", Alg: alg})
+	}
+
+	return &dto.PasskeyCreationOptions{
+		Challenge: challenge,
+		RP:        dto.PasskeyRelyingParty{ID: uc.config.RPID, Name: uc.config.DisplayName()},
+		User: dto.PasskeyUser{
+			ID:          userHandle(userID),
+			Name:        user.WalletAddress,
+			DisplayName: user.DID,
+		},
+		PubKeyCredParams: params,
+		Timeout:          uc.config.ChallengeLifetime().Milliseconds(),
+		Attestation:      "none",
+		AuthenticatorSelection: dto.PasskeyAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		ExcludeCredentials: credentialDescriptors(passkeys),
+	}, nil
+}
+
+// Register 校验 navigator.credentials.create() 的结果并保存 passkey
+func (uc *PasskeyUseCase) Register(ctx context.Context, userID, sessionID string, req *dto.PasskeyRegisterRequest) (*entity.Passkey, error) {
+	if !uc.config.Enabled() {
+		return nil, ErrPasskeyUnavailable
+	}
+
+	clientDataJSON, err := decodeBase64URL(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	attestationObject, err := decodeBase64URL(req.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	challenge, err := uc.consumeChallenge(ctx, clientDataJSON, entity.PasskeyChallengeRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserID != userID || challenge.SessionID != sessionID {
+		return nil, ErrInvalidPasskey
+	}
+
+	credential, err := uc.rp.VerifyRegistration(clientDataJSON, attestationObject, challenge.Challenge)
+	if err != nil {
+		uc.logger.Warn("Passkey registration rejected", zap.String("user_id", userID), zap.Error(err))
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPasskey, err)
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+	if _, err := uc.passkeyRepo.FindByCredentialID(ctx, credentialID); err == nil {
+		return nil, ErrPasskeyAlreadyRegistered
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find passkey: %w", err)
+	}
+
+	count, err := uc.passkeyRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count passkeys: %w", err)
+	}
+	if count >= int64(uc.config.Limit()) {
+		return nil, ErrPasskeyLimitReached
+	}
+
+	passkey := &entity.Passkey{
+		UserID:         userID,
+		Name:           strings.TrimSpace(req.Name),
+		CredentialID:   credentialID,
+		PublicKey:      credential.PublicKey,
+		Algorithm:      int(credential.Algorithm),
+		SignCount:      int64(credential.SignCount),
+		AAGUID:         hex.EncodeToString(credential.AAGUID),
+		Transports:     strings.Join(req.Response.Transports, " "),
+		BackupEligible: credential.BackupEligible,
+		BackedUp:       credential.BackedUp,
+	}
+	if err := uc.passkeyRepo.Create(ctx, passkey); err != nil {
+		return nil, fmt.Errorf("failed to save passkey: %w", err)
+	}
+
+	uc.logger.Info("Passkey registered",
+		zap.String("user_id", userID),
+		zap.String("passkey_id", passkey.ID),
+		zap.Int("algorithm", passkey.Algorithm),
+	)
+	return passkey, nil
+}
+
+// List 列出用户的 passkey
+func (uc *PasskeyUseCase) List(ctx context.Context, userID string) ([]*entity.Passkey, error) {
+	passkeys, err := uc.passkeyRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	if passkeys == nil {
+		passkeys = []*entity.Passkey{}
+	}
+	return passkeys, nil
+}
+
+// Delete 删除用户的 passkey，已签发的只读会话不受影响 (可通过吊销会话结束)
+func (uc *PasskeyUseCase) Delete(ctx context.Context, userID, id string) error {
+	passkey, err := uc.passkeyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return fmt.Errorf("failed to find passkey: %w", err)
+	}
+	if passkey.UserID != userID {
+		return ErrPasskeyNotFound
+	}
+
+	if err := uc.passkeyRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+
+	uc.logger.Info("Passkey deleted", zap.String("user_id", userID), zap.String("passkey_id", id))
+	return nil
+}
+
+// LoginOptions 生成登录参数，与 /auth/nonce 共用 IP 锁定与限流
+func (uc *PasskeyUseCase) LoginOptions(ctx context.Context, client *dto.ClientInfo) (*dto.PasskeyRequestOptions, error) {
+	if !uc.config.Enabled() {
+		return nil, ErrPasskeyUnavailable
+	}
+	if err := uc.security.CheckNonce(ctx, "", client.IP); err != nil {
+		return nil, err
+	}
+
+	challenge, err := uc.newChallenge(ctx, entity.PasskeyChallengeLogin, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RPID:             uc.config.RPID,
+		Timeout:          uc.config.ChallengeLifetime().Milliseconds(),
+		UserVerification: "required",
+		AllowCredentials: []dto.PasskeyCredentialDescriptor{},
+	}, nil
+}
+
+// Login 校验 navigator.credentials.get() 的结果并创建只读会话
+// 失败计入 IP 维度的登录失败次数，与钱包登录共用锁定策略
+func (uc *PasskeyUseCase) Login(ctx context.Context, req *dto.PasskeyLoginRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
+	if !uc.config.Enabled() {
+		return nil, ErrPasskeyUnavailable
+	}
+	if err := uc.security.CheckVerify(ctx, "", client.IP); err != nil {
+		return nil, err
+	}
+
+	passkey, err := uc.verifyAssertion(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPasskey) {
+			uc.security.RecordFailure(ctx, "", client.IP, err)
+		}
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, passkey.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !user.IsActive() {
+		uc.logger.Warn("Passkey login rejected for disabled user",
+			zap.String("user_id", user.ID),
+			zap.String("status", string(user.Status)),
+		)
+		return nil, ErrAccountDisabled
+	}
+
+	uc.logger.Info("Passkey login", zap.String("user_id", user.ID), zap.String("passkey_id", passkey.ID))
+	return uc.auth.StartSession(ctx, user, client, entity.SessionScopeRead)
+}
+
+// verifyAssertion 消费挑战、查找凭证并校验签名与签名计数器，成功后记录使用
+func (uc *PasskeyUseCase) verifyAssertion(ctx context.Context, req *dto.PasskeyLoginRequest) (*entity.Passkey, error) {
+	clientDataJSON, err := decodeBase64URL(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	authenticatorData, err := decodeBase64URL(req.Response.AuthenticatorData)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+	signature, err := decodeBase64URL(req.Response.Signature)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	challenge, err := uc.consumeChallenge(ctx, clientDataJSON, entity.PasskeyChallengeLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := uc.passkeyRepo.FindByCredentialID(ctx, strings.TrimRight(req.ID, "="))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: unknown credential", ErrInvalidPasskey)
+		}
+		return nil, fmt.Errorf("failed to find passkey: %w", err)
+	}
+	if req.Response.UserHandle != "" && strings.TrimRight(req.Response.UserHandle, "=") != userHandle(passkey.UserID) {
+		return nil, fmt.Errorf("%w: user handle mismatch", ErrInvalidPasskey)
+	}
+
+	authData, err := uc.rp.VerifyAssertion(clientDataJSON, authenticatorData, signature, passkey.PublicKey, challenge.Challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPasskey, err)
+	}
+
+	signCount := int64(authData.SignCount)
+	if !signCountIncreased(passkey.SignCount, signCount) {
+		uc.logger.Warn("Passkey sign count did not increase, possible cloned authenticator",
+			zap.String("user_id", passkey.UserID),
+			zap.String("passkey_id", passkey.ID),
+			zap.Int64("stored", passkey.SignCount),
+			zap.Int64("received", signCount),
+		)
+		return nil, fmt.Errorf("%w: sign count did not increase", ErrInvalidPasskey)
+	}
+
+	if err := uc.passkeyRepo.RecordUse(ctx, passkey.ID, signCount, authData.BackedUp(), time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to update passkey: %w", err)
+	}
+	return passkey, nil
+}
+
+// signCountIncreased 支持计数器的认证器每次签名递增，不递增说明凭证可能被克隆
+// 同步型 passkey 的计数器始终为 0，两者均为 0 时视为不支持计数器
+func signCountIncreased(stored, received int64) bool {
+	if stored == 0 && received == 0 {
+		return true
+	}
+	return received > stored
+}
+
+// newChallenge 生成并保存挑战
+func (uc *PasskeyUseCase) newChallenge(ctx context.Context, purpose, userID, sessionID string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	if err := uc.challengeRepo.SavePasskeyChallenge(ctx, &entity.PasskeyChallenge{
+		Challenge: challenge,
+		Purpose:   purpose,
+		UserID:    userID,
+		SessionID: sessionID,
+		ExpiresAt: time.Now().Add(uc.config.ChallengeLifetime()),
+	}); err != nil {
+		return "", fmt.Errorf("failed to save challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// consumeChallenge 取出 clientDataJSON 中的挑战并删除，用途不符或已过期返回 ErrInvalidPasskey
+func (uc *PasskeyUseCase) consumeChallenge(ctx context.Context, clientDataJSON []byte, purpose string) (*entity.PasskeyChallenge, error) {
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPasskey, err)
+	}
+
+	challenge, err := uc.challengeRepo.ConsumePasskeyChallenge(ctx, clientData.Challenge)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, fmt.Errorf("%w: challenge not found or expired", ErrInvalidPasskey)
+		}
+		return nil, fmt.Errorf("failed to consume challenge: %w", err)
+	}
+	if challenge.Purpose != purpose || challenge.IsExpired() {
+		return nil, fmt.Errorf("%w: challenge not found or expired", ErrInvalidPasskey)
+	}
+	return challenge, nil
+}
+
+// credentialDescriptors 已注册的凭证，注册时放入 excludeCredentials 避免同一认证器重复注册
+func credentialDescriptors(passkeys []*entity.Passkey) []dto.PasskeyCredentialDescriptor {
+	descriptors := make([]dto.PasskeyCredentialDescriptor, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		descriptors = append(descriptors, dto.PasskeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         passkey.CredentialID,
+			Transports: passkey.TransportList(),
+		})
+	}
+	return descriptors
+}
+
+// userHandle WebAuthn user.id，为用户 ID 的 base64url
+func userHandle(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+// decodeBase64URL 解码 base64url，兼容带填充的输入
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
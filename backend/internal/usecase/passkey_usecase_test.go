@@ -0,0 +1,24 @@
+package usecase
+
+import "testing"
+
+func TestSignCountIncreased(t *testing.T) {
+	tests := []struct {
+		name             string
+		stored, received int64
+		want             bool
+	}{
+		{"synced passkey without counter", 0, 0, true},
+		{"first use of counting authenticator", 0, 1, true},
+		{"counter increased", 41, 42, true},
+		{"counter jumped", 5, 500, true},
+		{"counter repeated", 42, 42, false},
+		{"counter went backwards", 42, 41, false},
+		{"counter reset to zero", 42, 0, false},
+	}
+	for _, tt := range tests {
+		if got := signCountIncreased(tt.stored, tt.received); got != tt.want {
+			t.Errorf("%s: signCountIncreased(%d, %d) = %v, want %v", tt.name, tt.stored, tt.received, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/pkg/pow"
+	"go.uber.org/zap"
+)
+
+// 安全事件名称，写入 security logger 的 event 字段
+const (
+	EventVerifyFailed     = "auth.verify_failed"
+	EventLockout          = "auth.lockout"
+	EventLockedOutAttempt = "auth.locked_out_attempt"
+	EventNonceRateLimited = "auth.nonce_rate_limited"
+	EventLockoutCleared   = "auth.lockout_cleared"
+)
+
+var (
+	// ErrTooManyAttempts 地址或 IP 因多次验签失败被临时锁定，或请求过于频繁
+	ErrTooManyAttempts = errors.New("too many attempts, try again later")
+
+	// ErrInvalidLockoutScope 锁定维度不是 address 或 ip
+	ErrInvalidLockoutScope = errors.New("invalid lockout scope")
+
+	// ErrInvalidProofOfWork 缺少工作量证明或 solution 不满足难度要求
+	ErrInvalidProofOfWork = errors.New("invalid proof of work")
+)
+
+// SecurityUseCase 登录暴力破解防护：统计验签失败、指数退避锁定、nonce 限流、工作量证明并记录安全事件
+type SecurityUseCase struct {
+	attemptRepo   repository.LoginAttemptRepository
+	rateLimitRepo repository.RateLimitRepository
+	config        *config.LockoutConfig
+	powConfig     *config.PoWConfig
+	events        *zap.Logger
+}
+
+func NewSecurityUseCase(
+	attemptRepo repository.LoginAttemptRepository,
+	rateLimitRepo repository.RateLimitRepository,
+	cfg *config.LockoutConfig,
+	powCfg *config.PoWConfig,
+	logger *zap.Logger,
+) *SecurityUseCase {
+	return &SecurityUseCase{
+		attemptRepo:   attemptRepo,
+		rateLimitRepo: rateLimitRepo,
+		config:        cfg,
+		powConfig:     powCfg,
+		events:        logger.Named("security"),
+	}
+}
+
+// CheckNonce 请求 nonce 前检查锁定状态和 IP 限流
+// 地址维度按 (地址, IP) 锁定，他人从其他 IP 造成的失败不会阻止钱包持有者获取 nonce
+func (uc *SecurityUseCase) CheckNonce(ctx context.Context, address, ip string) error {
+	if err := uc.checkLockouts(ctx, "nonce", address, ip); err != nil {
+		return err
+	}
+
+	if ip == "" {
+		return nil
+	}
+	allowed, err := uc.rateLimitRepo.Allow(ctx, "nonce:"+ip, uc.config.NonceRate(), time.Minute)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if !allowed {
+		uc.events.Warn("Nonce rate limit exceeded",
+			zap.String("event", EventNonceRateLimited),
+			zap.String("address", address),
+			zap.String("ip", ip),
+		)
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// PoWDifficulty 返回本次签发 nonce 要求的工作量证明难度，未启用时为 0
+// 难度随该 IP 在统计窗口内的 nonce 请求数增长
+func (uc *SecurityUseCase) PoWDifficulty(ctx context.Context, ip string) (int, error) {
+	if !uc.powConfig.Enabled {
+		return 0, nil
+	}
+
+	var requests int64
+	if ip != "" {
+		count, err := uc.rateLimitRepo.Increment(ctx, "pow:"+ip, uc.powConfig.Window())
+		if err != nil {
+			return 0, fmt.Errorf("failed to count nonce requests: %w", err)
+		}
+		requests = count - 1
+	}
+	return uc.powConfig.Difficulty(requests), nil
+}
+
+// VerifyPoW 检查 challenge 要求的工作量证明，seed 为登录 nonce
+func (uc *SecurityUseCase) VerifyPoW(challenge *entity.LoginChallenge, solution string) error {
+	if challenge.PoWDifficulty <= 0 {
+		return nil
+	}
+	if !pow.Verify(challenge.Nonce, challenge.PoWDifficulty, solution) {
+		return ErrInvalidProofOfWork
+	}
+	return nil
+}
+
+// CheckVerify 验签前检查地址和 IP 是否被锁定
+func (uc *SecurityUseCase) CheckVerify(ctx context.Context, address, ip string) error {
+	return uc.checkLockouts(ctx, "verify", address, ip)
+}
+
+// RecordFailure 记录一次验签失败，达到阈值时锁定地址或 IP
+// address 必须是已消费的 challenge 签发给的地址 (不能直接使用客户端提交的地址)，未知时传空字符串只计入 IP
+// reason 只记录错误描述，不记录签名等请求内容
+func (uc *SecurityUseCase) RecordFailure(ctx context.Context, address, ip string, reason error) {
+	uc.events.Warn("Sign-in verification failed",
+		zap.String("event", EventVerifyFailed),
+		zap.String("address", address),
+		zap.String("ip", ip),
+		zap.String("reason", reason.Error()),
+	)
+
+	for _, target := range uc.targets(address, ip) {
+		lockout, err := uc.attemptRepo.RecordFailure(ctx, target.scope, target.key, uc.policy(target.scope))
+		if err != nil {
+			uc.events.Error("Failed to record sign-in failure",
+				zap.String("scope", string(target.scope)),
+				zap.String("key", target.key),
+				zap.Error(err),
+			)
+			continue
+		}
+		if lockout != nil {
+			uc.events.Warn("Sign-in locked out",
+				zap.String("event", EventLockout),
+				zap.String("scope", string(lockout.Scope)),
+				zap.String("key", lockout.Key),
+				zap.Int("level", lockout.Level),
+				zap.Time("locked_until", lockout.LockedUntil),
+			)
+		}
+	}
+}
+
+// RecordSuccess 登录成功后清除该地址在此 IP 上的失败计数 (IP 可能被多人共享，不清除)
+func (uc *SecurityUseCase) RecordSuccess(ctx context.Context, address, ip string) {
+	if err := uc.attemptRepo.ResetFailures(ctx, entity.LockoutScopeAddress, addressLockoutKey(address, ip)); err != nil {
+		uc.events.Error("Failed to reset sign-in failures", zap.String("address", address), zap.Error(err))
+	}
+}
+
+// ListLockouts 列出所有生效中的锁定 (管理后台)
+func (uc *SecurityUseCase) ListLockouts(ctx context.Context) ([]*entity.Lockout, error) {
+	lockouts, err := uc.attemptRepo.ListLockouts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lockouts: %w", err)
+	}
+	if lockouts == nil {
+		lockouts = []*entity.Lockout{}
+	}
+	return lockouts, nil
+}
+
+// Unlock 管理员解除锁定，同时清除失败计数与退避等级
+func (uc *SecurityUseCase) Unlock(ctx context.Context, adminID string, scope entity.LockoutScope, key string) error {
+	if scope != entity.LockoutScopeAddress && scope != entity.LockoutScopeIP {
+		return ErrInvalidLockoutScope
+	}
+	if scope == entity.LockoutScopeAddress {
+		key = normalizeLockoutKey(key)
+	}
+
+	if err := uc.attemptRepo.DeleteLockout(ctx, scope, key); err != nil {
+		return fmt.Errorf("failed to delete lockout: %w", err)
+	}
+
+	uc.events.Info("Lockout cleared by admin",
+		zap.String("event", EventLockoutCleared),
+		zap.String("scope", string(scope)),
+		zap.String("key", key),
+		zap.String("admin_id", adminID),
+	)
+	return nil
+}
+
+// checkLockouts 地址或 IP 任一被锁定时返回 ErrTooManyAttempts
+func (uc *SecurityUseCase) checkLockouts(ctx context.Context, action, address, ip string) error {
+	for _, target := range uc.targets(address, ip) {
+		lockout, err := uc.attemptRepo.FindLockout(ctx, target.scope, target.key)
+		if err != nil {
+			return fmt.Errorf("failed to check lockout: %w", err)
+		}
+		if lockout == nil {
+			continue
+		}
+
+		uc.events.Warn("Attempt while locked out",
+			zap.String("event", EventLockedOutAttempt),
+			zap.String("action", action),
+			zap.String("scope", string(lockout.Scope)),
+			zap.String("key", lockout.Key),
+			zap.Time("locked_until", lockout.LockedUntil),
+		)
+		return fmt.Errorf("%w (locked until %s)", ErrTooManyAttempts, lockout.LockedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+type lockoutTarget struct {
+	scope entity.LockoutScope
+	key   string
+}
+
+// targets 返回需要计数的维度，空值跳过
+func (uc *SecurityUseCase) targets(address, ip string) []lockoutTarget {
+	var targets []lockoutTarget
+	if key := addressLockoutKey(address, ip); key != "" {
+		targets = append(targets, lockoutTarget{scope: entity.LockoutScopeAddress, key: key})
+	}
+	if ip != "" {
+		targets = append(targets, lockoutTarget{scope: entity.LockoutScopeIP, key: ip})
+	}
+	return targets
+}
+
+// policy 返回维度对应的锁定策略，IP 可能被多人共享，阈值更高
+func (uc *SecurityUseCase) policy(scope entity.LockoutScope) repository.LockoutPolicy {
+	maxFailures := uc.config.AddressLimit()
+	if scope == entity.LockoutScopeIP {
+		maxFailures = uc.config.IPLimit()
+	}
+	return repository.LockoutPolicy{
+		MaxFailures: maxFailures,
+		Window:      uc.config.Window(),
+		BaseLockout: uc.config.BaseLockout(),
+		MaxLockout:  uc.config.MaxLockout(),
+		Decay:       2 * uc.config.MaxLockout(),
+	}
+}
+
+// addressLockoutKey 地址维度的锁定 key，形如 <address>@<ip>
+// 只按地址计数时任何人都能从轮换的 IP 提交无效签名，持续锁定他人的钱包
+func addressLockoutKey(address, ip string) string {
+	key := normalizeLockoutKey(address)
+	if key == "" || ip == "" {
+		return key
+	}
+	return key + "@" + ip
+}
+
+// normalizeLockoutKey EVM 地址大小写不敏感，统一小写避免通过改变大小写绕过计数；base58 地址区分大小写
+func normalizeLockoutKey(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/infrastructure/cache"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const ownerIP = "198.51.100.1"
+
+// newTestSecurity 使用 miniredis 上的真实锁定仓储：地址 3 次、IP 5 次失败后锁定 1 分钟
+func newTestSecurity(t *testing.T) (*SecurityUseCase, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	cfg := &config.LockoutConfig{
+		AddressMaxFailures: 3,
+		IPMaxFailures:      5,
+		WindowMins:         15,
+		BaseLockoutSecs:    60,
+		MaxLockoutMins:     10,
+	}
+	security := NewSecurityUseCase(
+		cache.NewRedisLoginAttemptRepository(rdb),
+		cache.NewRedisRateLimitRepository(rdb),
+		cfg,
+		&config.PoWConfig{},
+		zap.NewNop(),
+	)
+	return security, mr
+}
+
+func recordFailures(security *SecurityUseCase, address, ip string, n int) {
+	for i := 0; i < n; i++ {
+		security.RecordFailure(context.Background(), address, ip, errors.New("invalid signature"))
+	}
+}
+
+// findLockout 返回 scope 维度唯一的生效锁定
+func findLockout(t *testing.T, security *SecurityUseCase, scope entity.LockoutScope) *entity.Lockout {
+	t.Helper()
+	lockouts, err := security.ListLockouts(context.Background())
+	if err != nil {
+		t.Fatalf("ListLockouts: %v", err)
+	}
+	var found []*entity.Lockout
+	for _, lockout := range lockouts {
+		if lockout.Scope == scope {
+			found = append(found, lockout)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("%d %s lockouts, want 1", len(found), scope)
+	}
+	return found[0]
+}
+
+func TestSecurityLocksAddressAtThreshold(t *testing.T) {
+	security, _ := newTestSecurity(t)
+	ctx := context.Background()
+
+	recordFailures(security, testWallet, ownerIP, 2)
+	if err := security.CheckVerify(ctx, testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckVerify before threshold: %v", err)
+	}
+
+	// 大小写不同的 EVM 地址计入同一个 key
+	recordFailures(security, strings.ToLower(testWallet), ownerIP, 1)
+	if err := security.CheckVerify(ctx, testWallet, ownerIP); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("CheckVerify at threshold = %v, want ErrTooManyAttempts", err)
+	}
+	if err := security.CheckNonce(ctx, testWallet, ownerIP); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("CheckNonce while locked = %v, want ErrTooManyAttempts", err)
+	}
+}
+
+func TestSecurityAddressLockoutIsPerIP(t *testing.T) {
+	security, _ := newTestSecurity(t)
+	ctx := context.Background()
+
+	// 攻击者从轮换的 IP 提交无效签名
+	for i := 0; i < 10; i++ {
+		recordFailures(security, testWallet, fmt.Sprintf("203.0.113.%d", i), 3)
+	}
+
+	// 钱包持有者仍能获取 nonce 并登录
+	if err := security.CheckNonce(ctx, testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckNonce for owner = %v, want nil", err)
+	}
+	if err := security.CheckVerify(ctx, testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckVerify for owner = %v, want nil", err)
+	}
+	if err := security.CheckVerify(ctx, testWallet, "203.0.113.0"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("CheckVerify for attacker IP = %v, want ErrTooManyAttempts", err)
+	}
+}
+
+func TestSecurityLocksIPAcrossAddresses(t *testing.T) {
+	security, _ := newTestSecurity(t)
+
+	for i := 0; i < 5; i++ {
+		recordFailures(security, fmt.Sprintf("0x%040x", i), ownerIP, 1)
+	}
+	if err := security.CheckVerify(context.Background(), "", ownerIP); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("CheckVerify = %v, want IP lockout", err)
+	}
+}
+
+func TestSecurityLockoutBackoff(t *testing.T) {
+	security, mr := newTestSecurity(t)
+
+	for level, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute} {
+		recordFailures(security, testWallet, ownerIP, 3)
+
+		lockout := findLockout(t, security, entity.LockoutScopeAddress)
+		if lockout.Level != level+1 {
+			t.Errorf("level = %d, want %d", lockout.Level, level+1)
+		}
+		if d := time.Until(lockout.LockedUntil); d <= want-time.Second || d > want {
+			t.Errorf("lockout %d lasts %v, want %v", level+1, d, want)
+		}
+		// miniredis 只让 TTL 前进，锁定时间按真实时钟计算，这里只需让锁定 key 过期
+		mr.FastForward(want)
+	}
+}
+
+func TestSecurityRecordSuccessResetsFailures(t *testing.T) {
+	security, _ := newTestSecurity(t)
+	ctx := context.Background()
+
+	recordFailures(security, testWallet, ownerIP, 2)
+	security.RecordSuccess(ctx, testWallet, ownerIP)
+	recordFailures(security, testWallet, ownerIP, 2)
+
+	if err := security.CheckVerify(ctx, testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckVerify after success = %v, want nil", err)
+	}
+}
+
+func TestSecurityUnlock(t *testing.T) {
+	security, _ := newTestSecurity(t)
+	ctx := context.Background()
+
+	recordFailures(security, testWallet, ownerIP, 3)
+	lockout := findLockout(t, security, entity.LockoutScopeAddress)
+
+	// 管理员按列表中的 key 解锁，key 中的地址大小写不影响
+	key := strings.ToUpper(lockout.Key[:2]) + lockout.Key[2:]
+	if err := security.Unlock(ctx, "00000000-0000-0000-0000-000000000001", entity.LockoutScopeAddress, key); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := security.CheckVerify(ctx, testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckVerify after unlock = %v, want nil", err)
+	}
+
+	if err := security.Unlock(ctx, "00000000-0000-0000-0000-000000000001", "wallet", testWallet); !errors.Is(err, ErrInvalidLockoutScope) {
+		t.Fatalf("Unlock with invalid scope = %v, want ErrInvalidLockoutScope", err)
+	}
+}
+
+func TestVerifySignatureBogusNonceDoesNotCountAgainstAddress(t *testing.T) {
+	security, mr := newTestSecurity(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	auth := &AuthUseCase{
+		challenges: cache.NewRedisChallengeStore(rdb, repository.ChallengeLimits{}),
+		security:   security,
+		config:     &config.AuthConfig{},
+		logger:     zap.NewNop(),
+	}
+
+	// 未签发的 nonce 只计入攻击者的 IP，不计入请求中的地址
+	for i := 0; i < 10; i++ {
+		_, err := auth.VerifySignature(context.Background(), &dto.VerifyRequest{
+			Address:       testWallet,
+			Nonce:         fmt.Sprintf("bogus-%d", i),
+			Signature:     "0x00",
+			SignatureType: dto.SignatureTypeEIP712,
+		}, &dto.ClientInfo{IP: "203.0.113.9"})
+		if err == nil {
+			t.Fatal("VerifySignature with bogus nonce succeeded")
+		}
+	}
+
+	lockouts, err := security.ListLockouts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lockout := range lockouts {
+		if lockout.Scope == entity.LockoutScopeAddress {
+			t.Errorf("address locked out by bogus nonces: %+v", lockout)
+		}
+	}
+	if err := security.CheckNonce(context.Background(), testWallet, ownerIP); err != nil {
+		t.Fatalf("CheckNonce for owner = %v, want nil", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/dedata/dedata-backend/config"
 	"github.com/dedata/dedata-backend/internal/domain/entity"
 	"github.com/dedata/dedata-backend/internal/domain/repository"
 	"github.com/dedata/dedata-backend/internal/interface/dto"
@@ -12,16 +13,20 @@ import (
 )
 
 type UserUseCase struct {
-	userRepo    repository.UserRepository
-	profileRepo repository.ProfileRepository
-	checkinRepo repository.CheckInRepository
+	userRepo      repository.UserRepository
+	profileRepo   repository.ProfileRepository
+	checkinRepo   repository.CheckInRepository
+	checkinConfig *config.CheckInConfig
+	checkinLoc    *time.Location // 签到日切换时区，用于判断连续签到是否中断
 }
 
-func NewUserUseCase(userRepo repository.UserRepository, profileRepo repository.ProfileRepository, checkinRepo repository.CheckInRepository) *UserUseCase {
+func NewUserUseCase(userRepo repository.UserRepository, profileRepo repository.ProfileRepository, checkinRepo repository.CheckInRepository, checkinCfg *config.CheckInConfig, checkinLoc *time.Location) *UserUseCase {
 	return &UserUseCase{
-		userRepo:    userRepo,
-		profileRepo: profileRepo,
-		checkinRepo: checkinRepo,
+		userRepo:      userRepo,
+		profileRepo:   profileRepo,
+		checkinRepo:   checkinRepo,
+		checkinConfig: checkinCfg,
+		checkinLoc:    checkinLoc,
 	}
 }
 
@@ -39,6 +44,7 @@ func (uc *UserUseCase) GetLeaderboard(ctx context.Context, page, limit int) (*dt
 	}
 
 	// 构建响应数据
+	today := checkInDay(time.Now(), uc.checkinLoc)
 	leaderboardUsers := make([]dto.LeaderboardUser, len(users))
 	for i, user := range users {
 		// 计算全局排名：(page - 1) * limit + i + 1
@@ -55,11 +61,14 @@ func (uc *UserUseCase) GetLeaderboard(ctx context.Context, page, limit int) (*dt
 			Rank:             rank,
 			ID:               user.ID,
 			DID:              user.DID,
+			Account:          user.Account,
 			WalletAddress:    user.WalletAddress,
 			ChainID:          user.ChainID,
 			ProfileCompleted: user.ProfileCompleted,
 			TotalRewards:     user.TotalRewards,
 			TotalCheckins:    checkinCount,
+			CurrentStreak:    user.StreakAt(today, uc.checkinConfig.StreakGraceDays),
+			LongestStreak:    user.LongestStreak,
 		}
 
 		// 从关联的 Profile 中获取 displayName 和 avatar
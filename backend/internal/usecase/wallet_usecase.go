@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"github.com/dedata/dedata-backend/internal/interface/dto"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/dedata/dedata-backend/pkg/eip712"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWalletAlreadyLinked 钱包已绑定到其他账户 (或当前账户)
+	ErrWalletAlreadyLinked = errors.New("wallet is already linked to an account")
+
+	// ErrWalletNotFound 钱包未绑定到当前账户
+	ErrWalletNotFound = errors.New("wallet not found")
+
+	// ErrCannotUnlinkPrimary 主钱包不能解绑
+	ErrCannotUnlinkPrimary = errors.New("cannot unlink the primary wallet")
+
+	// ErrLinkChallengeNotFound 绑定挑战不存在、已使用或已过期
+	ErrLinkChallengeNotFound = errors.New("link challenge not found or expired")
+
+	// ErrLinkChallengeSession 绑定挑战不是由当前用户的当前会话发起
+	ErrLinkChallengeSession = errors.New("link challenge does not belong to this session")
+
+	// ErrUnsupportedSignatureType 签名类型不支持 (或不适用于该链家族)
+	ErrUnsupportedSignatureType = errors.New("unsupported signature type")
+
+	// ErrInvalidWalletSignature 新钱包签名与挑战地址不匹配
+	ErrInvalidWalletSignature = errors.New("invalid signature")
+)
+
+// WalletUseCase 多钱包绑定用例
+type WalletUseCase struct {
+	walletRepo  repository.WalletRepository
+	linkRepo    repository.WalletLinkRepository
+	userRepo    repository.UserRepository
+	chains      *chain.Registry
+	sigVerifier crypto.SignatureVerifier
+	config      *config.AuthConfig
+	logger      *zap.Logger
+}
+
+func NewWalletUseCase(
+	walletRepo repository.WalletRepository,
+	linkRepo repository.WalletLinkRepository,
+	userRepo repository.UserRepository,
+	chains *chain.Registry,
+	sigVerifier crypto.SignatureVerifier,
+	cfg *config.AuthConfig,
+	logger *zap.Logger,
+) *WalletUseCase {
+	return &WalletUseCase{
+		walletRepo:  walletRepo,
+		linkRepo:    linkRepo,
+		userRepo:    userRepo,
+		chains:      chains,
+		sigVerifier: sigVerifier,
+		config:      cfg,
+		logger:      logger,
+	}
+}
+
+// ListWallets 列出用户绑定的所有钱包
+func (uc *WalletUseCase) ListWallets(ctx context.Context, userID string) ([]*entity.UserWallet, error) {
+	return uc.walletRepo.FindByUserID(ctx, userID)
+}
+
+// CreateLinkChallenge 为当前会话生成绑定新钱包的挑战
+func (uc *WalletUseCase) CreateLinkChallenge(ctx context.Context, userID, sessionID string, req *dto.LinkWalletChallengeRequest) (*dto.LinkWalletChallengeResponse, error) {
+	// 1. 确定链家族，校验地址和链 ID
+	family, err := walletFamily(uc.chains, uc.config, req.Namespace, req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := family.NormalizeAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := walletChainID(uc.config, family.Namespace(), req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 钱包不能已被任何账户绑定
+	account, err := caip.NewAccountID(string(family.Namespace()), chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureUnlinked(ctx, userID, account); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// 3. 生成挑战并绑定到当前会话
+	nonce, err := crypto.GenerateNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	challenge := &entity.WalletLinkChallenge{
+		Nonce:     nonce,
+		UserID:    userID,
+		SessionID: sessionID,
+		DID:       user.DID,
+		Address:   address,
+		Namespace: string(family.Namespace()),
+		ChainID:   chainID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(uc.config.NonceLifetime()),
+	}
+	challenge.Message = uc.linkMessage(challenge)
+
+	if err := uc.linkRepo.SaveLinkChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to save link challenge: %w", err)
+	}
+
+	resp := &dto.LinkWalletChallengeResponse{
+		Nonce:     nonce,
+		Message:   challenge.Message,
+		IssuedAt:  challenge.IssuedAt.Format(time.RFC3339),
+		ExpiresAt: challenge.ExpiresAt.Format(time.RFC3339),
+	}
+	// EIP-712 仅适用于 EVM 钱包
+	if family.Namespace() == chain.EIP155 {
+		typedData := uc.linkTypedData(challenge)
+		resp.TypedData = &typedData
+	}
+	return resp, nil
+}
+
+// LinkWallet 验证新钱包对挑战的签名并完成绑定
+func (uc *WalletUseCase) LinkWallet(ctx context.Context, userID, sessionID string, req *dto.LinkWalletRequest) (*entity.UserWallet, error) {
+	// 1. 原子地取出挑战，保证只能使用一次
+	challenge, err := uc.linkRepo.ConsumeLinkChallenge(ctx, req.Nonce)
+	if err != nil {
+		if errors.Is(err, repository.ErrChallengeNotFound) {
+			return nil, ErrLinkChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to find link challenge: %w", err)
+	}
+	if challenge.IsExpired() {
+		return nil, ErrLinkChallengeNotFound
+	}
+
+	// 2. 挑战必须由同一用户的同一会话发起
+	if challenge.UserID != userID || challenge.SessionID != sessionID {
+		uc.logger.Warn("Link challenge used from another session",
+			zap.String("user_id", userID),
+			zap.String("challenge_user_id", challenge.UserID),
+		)
+		return nil, ErrLinkChallengeSession
+	}
+
+	// 3. 验证新钱包签名 (EVM 支持 EOA 和 EIP-1271 合约钱包，Solana 为 ed25519)
+	family, err := uc.chains.Get(chain.Namespace(challenge.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	var valid bool
+	switch {
+	case req.SignatureType == "" || req.SignatureType == dto.SignatureTypePersonalSign:
+		valid, err = family.VerifyMessage(ctx, challenge.ChainID, challenge.Message, req.Signature, challenge.Address)
+	case req.SignatureType == dto.SignatureTypeEIP712 && family.Namespace() == chain.EIP155:
+		valid, err = eip712.Verify(ctx, uc.sigVerifier, uc.linkTypedData(challenge), req.Signature, challenge.Address)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSignatureType, req.SignatureType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	if !valid {
+		uc.logger.Warn("Invalid link wallet signature", zap.String("expected_address", challenge.Address))
+		return nil, ErrInvalidWalletSignature
+	}
+
+	// 4. 再次检查，防止挑战期间钱包被其他账户绑定
+	account, err := caip.NewAccountID(challenge.Namespace, challenge.ChainID, challenge.Address)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.ensureUnlinked(ctx, userID, account); err != nil {
+		return nil, err
+	}
+
+	wallet := &entity.UserWallet{
+		UserID:      userID,
+		Account:     account.String(),
+		Address:     challenge.Address,
+		ChainID:     evmReference(challenge.Namespace, challenge.ChainID),
+		Namespace:   challenge.Namespace,
+		KeyVerified: uc.provesKeyControl(ctx, challenge, req),
+	}
+	if err := uc.walletRepo.Create(ctx, wallet); err != nil {
+		return nil, fmt.Errorf("failed to link wallet: %w", err)
+	}
+
+	uc.logger.Info("Wallet linked",
+		zap.String("user_id", userID),
+		zap.String("account", wallet.Account),
+	)
+
+	return wallet, nil
+}
+
+// UnlinkWallet 解绑钱包，主钱包不能解绑
+// id 为钱包的 CAIP-10 账户标识，不接受裸地址
+func (uc *WalletUseCase) UnlinkWallet(ctx context.Context, userID, id string) error {
+	// 同一地址可以绑定在多条链上，必须使用 CAIP-10 标识定位钱包
+	account, err := caip.ParseAccountID(id)
+	if err != nil {
+		return err
+	}
+	if family, err := uc.chains.Get(chain.Namespace(account.Chain.Namespace)); err == nil {
+		if address, err := family.NormalizeAddress(account.Address); err == nil {
+			account.Address = address
+		}
+	}
+
+	wallet, err := uc.walletRepo.FindByAccount(ctx, account.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWalletNotFound
+		}
+		return fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	if wallet.UserID != userID {
+		return ErrWalletNotFound
+	}
+	if wallet.IsPrimary {
+		return ErrCannotUnlinkPrimary
+	}
+
+	if err := uc.walletRepo.Delete(ctx, wallet.ID); err != nil {
+		return fmt.Errorf("failed to unlink wallet: %w", err)
+	}
+
+	uc.logger.Info("Wallet unlinked",
+		zap.String("user_id", userID),
+		zap.String("account", wallet.Account),
+	)
+
+	return nil
+}
+
+// ensureUnlinked 检查账户未被绑定 (包括历史用户的主钱包)
+// 同一地址在其他链上只能属于当前用户，不同链上的同一地址作为独立钱包绑定
+func (uc *WalletUseCase) ensureUnlinked(ctx context.Context, userID string, account caip.AccountID) error {
+	if _, err := uc.walletRepo.FindByAccount(ctx, account.String()); err == nil {
+		return ErrWalletAlreadyLinked
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	if wallet, err := uc.walletRepo.FindByAddress(ctx, account.Address); err == nil {
+		if wallet.UserID != userID || wallet.Account == "" {
+			return ErrWalletAlreadyLinked
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find wallet: %w", err)
+	}
+
+	if user, err := uc.userRepo.FindByAddress(ctx, account.Address); err == nil {
+		if user.ID != userID || user.Account == account.String() {
+			return ErrWalletAlreadyLinked
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return nil
+}
+
+// linkMessage 构造 personal_sign 绑定消息
+func (uc *WalletUseCase) linkMessage(challenge *entity.WalletLinkChallenge) string {
+	var b strings.Builder
+	b.WriteString(uc.config.Domain + " wants you to link this wallet to your DeData account:\n")
+	b.WriteString(challenge.Address + "\n")
+	b.WriteString("\n")
+	b.WriteString("Account: " + challenge.DID + "\n")
+	b.WriteString("URI: " + uc.config.URI + "\n")
+	b.WriteString("Chain ID: " + challenge.ChainID + "\n")
+	b.WriteString("Nonce: " + challenge.Nonce + "\n")
+	b.WriteString("Issued At: " + challenge.IssuedAt.Format(time.RFC3339) + "\n")
+	b.WriteString("Expiration Time: " + challenge.ExpiresAt.Format(time.RFC3339))
+	return b.String()
+}
+
+// linkTypedData 构造 EIP-712 绑定消息
+func (uc *WalletUseCase) linkTypedData(challenge *entity.WalletLinkChallenge) apitypes.TypedData {
+	domain := eip712.Domain{
+		Name:              uc.config.EIP712.Name,
+		Version:           uc.config.EIP712.Version,
+		ChainID:           evmReference(challenge.Namespace, challenge.ChainID),
+		VerifyingContract: uc.config.EIP712.VerifyingContract,
+	}
+
+	return eip712.NewTypedData(domain, eip712.LinkWalletSchema, apitypes.TypedDataMessage{
+		"account":        challenge.DID,
+		"wallet":         challenge.Address,
+		"chainId":        challenge.ChainID,
+		"nonce":          challenge.Nonce,
+		"issuedAt":       challenge.IssuedAt.Format(time.RFC3339),
+		"expirationTime": challenge.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// provesKeyControl 绑定签名是否证明持有地址私钥 (与 AuthUseCase.provesKeyControl 相同)
+// Solana 地址即 ed25519 公钥；EVM 仅当签名可通过 ecrecover 恢复出地址 (EOA) 时成立
+func (uc *WalletUseCase) provesKeyControl(ctx context.Context, challenge *entity.WalletLinkChallenge, req *dto.LinkWalletRequest) bool {
+	if challenge.Namespace != string(chain.EIP155) {
+		return true
+	}
+
+	eoa := crypto.NewECDSAVerifier()
+	var valid bool
+	var err error
+	if req.SignatureType == dto.SignatureTypeEIP712 {
+		valid, err = eip712.Verify(ctx, eoa, uc.linkTypedData(challenge), req.Signature, challenge.Address)
+	} else {
+		valid, err = crypto.VerifyPersonalSign(ctx, eoa, evmReference(challenge.Namespace, challenge.ChainID), challenge.Message, req.Signature, challenge.Address)
+	}
+	return err == nil && valid
+}
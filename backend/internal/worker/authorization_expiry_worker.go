@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// AuthorizationExpiryWorker 定期将到期的平台授权标记为 EXPIRED
+type AuthorizationExpiryWorker struct {
+	authorizationUC *usecase.AuthorizationUseCase
+	logger          *zap.Logger
+	interval        time.Duration
+}
+
+// NewAuthorizationExpiryWorker 创建授权过期 Worker
+func NewAuthorizationExpiryWorker(authorizationUC *usecase.AuthorizationUseCase, interval time.Duration, logger *zap.Logger) *AuthorizationExpiryWorker {
+	return &AuthorizationExpiryWorker{
+		authorizationUC: authorizationUC,
+		logger:          logger,
+		interval:        interval,
+	}
+}
+
+// Run 启动 Worker
+func (w *AuthorizationExpiryWorker) Run(ctx context.Context) {
+	w.logger.Info("AuthorizationExpiryWorker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("AuthorizationExpiryWorker stopped")
+			return
+		case <-ticker.C:
+			expired, err := w.authorizationUC.ExpireDue(ctx)
+			if err != nil {
+				w.logger.Error("Failed to expire authorizations", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				w.logger.Info("Expired authorizations", zap.Int64("count", expired))
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedata/dedata-backend/internal/domain/repository"
+	"go.uber.org/zap"
+)
+
+// ChallengeCleanupWorker 定期删除过期的登录挑战 (database 存储使用，Redis 存储由 TTL 清理)
+type ChallengeCleanupWorker struct {
+	challenges repository.ChallengeStore
+	logger     *zap.Logger
+	interval   time.Duration
+}
+
+// NewChallengeCleanupWorker 创建登录挑战清理 Worker
+func NewChallengeCleanupWorker(challenges repository.ChallengeStore, interval time.Duration, logger *zap.Logger) *ChallengeCleanupWorker {
+	return &ChallengeCleanupWorker{
+		challenges: challenges,
+		logger:     logger,
+		interval:   interval,
+	}
+}
+
+// Run 启动 Worker
+func (w *ChallengeCleanupWorker) Run(ctx context.Context) {
+	w.logger.Info("ChallengeCleanupWorker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("ChallengeCleanupWorker stopped")
+			return
+		case <-ticker.C:
+			if err := w.challenges.DeleteExpiredChallenges(ctx); err != nil {
+				w.logger.Error("Failed to delete expired challenges", zap.Error(err))
+			}
+		}
+	}
+}
@@ -3,30 +3,40 @@ package worker
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/dedata/dedata-backend/config"
 	"github.com/dedata/dedata-backend/internal/domain/entity"
 	"github.com/dedata/dedata-backend/internal/domain/repository"
 	"github.com/dedata/dedata-backend/internal/infrastructure/external"
+	"github.com/dedata/dedata-backend/pkg/caip"
+	"github.com/dedata/dedata-backend/pkg/chain"
+	"github.com/dedata/dedata-backend/pkg/reward"
 	"go.uber.org/zap"
 )
 
 // CheckinWorker 签到 Worker,负责异步发放 token
 type CheckinWorker struct {
-	checkinRepo repository.CheckInRepository
-	userRepo    repository.UserRepository
-	tokenIssuer external.TokenIssuer
-	config      *config.CheckInConfig
-	logger      *zap.Logger
-	interval    time.Duration
+	checkinRepo    repository.CheckInRepository
+	userRepo       repository.UserRepository
+	walletRepo     repository.WalletRepository
+	streakRuleRepo repository.StreakRuleRepository
+	tokenIssuer    external.TokenIssuer
+	rewardChain    caip.ChainID // 奖励 token 所在的链
+	config         *config.CheckInConfig
+	logger         *zap.Logger
+	interval       time.Duration
 }
 
 // NewCheckinWorker 创建签到 Worker
 func NewCheckinWorker(
 	checkinRepo repository.CheckInRepository,
 	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	streakRuleRepo repository.StreakRuleRepository,
 	tokenIssuer external.TokenIssuer,
+	rewardChainID int64,
 	cfg *config.CheckInConfig,
 	logger *zap.Logger,
 ) *CheckinWorker {
@@ -36,12 +46,15 @@ func NewCheckinWorker(
 	}
 
 	return &CheckinWorker{
-		checkinRepo: checkinRepo,
-		userRepo:    userRepo,
-		tokenIssuer: tokenIssuer,
-		config:      cfg,
-		logger:      logger,
-		interval:    interval,
+		checkinRepo:    checkinRepo,
+		userRepo:       userRepo,
+		walletRepo:     walletRepo,
+		streakRuleRepo: streakRuleRepo,
+		tokenIssuer:    tokenIssuer,
+		rewardChain:    caip.ChainID{Namespace: string(chain.EIP155), Reference: strconv.FormatInt(rewardChainID, 10)},
+		config:         cfg,
+		logger:         logger,
+		interval:       interval,
 	}
 }
 
@@ -120,7 +133,7 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 		logger.Warn("Max retry count reached, marking as issue_failed",
 			zap.Int("retry_count", checkin.RetryCount),
 		)
-		return w.checkinRepo.MarkFailed(ctx, checkin.ID, "max retry count reached")
+		return w.checkinRepo.MarkFailed(ctx, checkin.ID, "max retry count reached", w.config.StreakGraceDays)
 	}
 
 	// 2. 检查是否已经有交易哈希（服务重启恢复场景）
@@ -144,7 +157,11 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 					zap.String("tx_hash", *checkin.IssueTxHash),
 					zap.Uint64("block", status.BlockNumber),
 				)
-				rewardAmount := w.config.RewardAmount
+				// 发送交易时已保存奖励数量，旧记录按当前规则重新计算
+				rewardAmount, err := w.rewardAmount(ctx, checkin)
+				if err != nil {
+					return err
+				}
 				if err := w.checkinRepo.MarkSuccess(ctx, checkin.ID, *checkin.IssueTxHash, rewardAmount); err != nil {
 					return fmt.Errorf("failed to mark success: %w", err)
 				}
@@ -182,22 +199,34 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 		}
 	}
 
-	// 3. 更新状态为 issuing
+	// 3. 按连续签到天数计算奖励，读取规则失败时保持当前状态，下一轮重试
+	rewardAmount, err := w.rewardAmount(ctx, checkin)
+	if err != nil {
+		return err
+	}
+
+	// 4. 更新状态为 issuing
 	if err := w.checkinRepo.UpdateStatus(ctx, checkin.ID, entity.CheckInIssuing); err != nil {
 		return fmt.Errorf("failed to update status to issuing: %w", err)
 	}
 
-	// 4. 查找用户
+	// 5. 查找用户
 	user, err := w.userRepo.FindByID(ctx, checkin.UserID)
 	if err != nil {
 		// 标记为失败
-		w.checkinRepo.MarkFailed(ctx, checkin.ID, fmt.Sprintf("user not found: %v", err))
+		w.markFailed(ctx, checkin, fmt.Sprintf("user not found: %v", err))
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 
-	// 5. 发放 token
-	rewardAmount := w.config.RewardAmount
-	txHash, err := w.tokenIssuer.IssueToken(ctx, user.WalletAddress, rewardAmount)
+	// 6. 确定收款地址 (奖励为 EVM 链上的 token)
+	payoutAddress, err := w.payoutAddress(ctx, user)
+	if err != nil {
+		w.markFailed(ctx, checkin, err.Error())
+		return err
+	}
+
+	// 7. 发放 token
+	txHash, err := w.tokenIssuer.IssueToken(ctx, payoutAddress, rewardAmount)
 	if err != nil {
 		// 发放失败，增加重试次数
 		checkin.RetryCount++
@@ -211,8 +240,9 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 		return fmt.Errorf("failed to issue token: %w", err)
 	}
 
-	// 6. 立即保存 tx_hash，但保持 issuing 状态
+	// 8. 立即保存 tx_hash 与奖励数量，但保持 issuing 状态
 	checkin.IssueTxHash = &txHash
+	checkin.TokenAmount = &rewardAmount
 	checkin.Status = entity.CheckInIssuing
 	if err := w.checkinRepo.Update(ctx, checkin); err != nil {
 		logger.Error("Failed to save tx_hash",
@@ -225,6 +255,7 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 	logger.Info("Transaction sent and tx_hash saved, will check status in next poll",
 		zap.String("tx_hash", txHash),
 		zap.String("amount", rewardAmount),
+		zap.Int("streak_days", checkin.StreakDays),
 	)
 
 	// 立即检查交易状态
@@ -268,3 +299,85 @@ func (w *CheckinWorker) processOne(ctx context.Context, checkin *entity.CheckIn)
 func ptr(s string) *string {
 	return &s
 }
+
+// markFailed 标记为发放失败 (该签到日不再计入连续签到)，失败时只记录日志，下一轮会重新处理
+func (w *CheckinWorker) markFailed(ctx context.Context, checkin *entity.CheckIn, reason string) {
+	if err := w.checkinRepo.MarkFailed(ctx, checkin.ID, reason, w.config.StreakGraceDays); err != nil {
+		w.logger.Error("Failed to mark checkin as issue_failed",
+			zap.String("checkinID", checkin.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// rewardAmount 返回签到的奖励数量：已保存时直接使用 (与链上交易一致)，
+// 否则按签到时的连续签到天数匹配 checkin_streak_rules，未命中规则时为 reward_amount
+func (w *CheckinWorker) rewardAmount(ctx context.Context, checkin *entity.CheckIn) (string, error) {
+	if checkin.TokenAmount != nil && *checkin.TokenAmount != "" {
+		return *checkin.TokenAmount, nil
+	}
+
+	rules, err := w.streakRuleRepo.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load streak rules: %w", err)
+	}
+	rule := entity.MatchStreakRule(rules, checkin.StreakDays)
+	if rule == nil {
+		return w.config.RewardAmount, nil
+	}
+	amount, err := reward.Calculate(w.config.RewardAmount, rule.Multiplier, rule.Bonus)
+	if err != nil {
+		return "", fmt.Errorf("invalid streak reward rule (min_days %d): %w", rule.MinDays, err)
+	}
+	return amount, nil
+}
+
+// payoutAddress 返回接收奖励的 EVM 地址，按 CAIP-10 标识选择钱包:
+// 优先使用奖励链上的钱包 (主钱包优先)，没有时退回其他 EVM 链上已证明持有私钥的钱包 (EOA 地址跨链通用)；
+// 其他链上的合约钱包在奖励链上可能不受用户控制，不作为收款地址
+func (w *CheckinWorker) payoutAddress(ctx context.Context, user *entity.User) (string, error) {
+	wallets, err := w.walletRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find wallets: %w", err)
+	}
+	if len(wallets) == 0 && (user.Namespace == "" || user.Namespace == string(chain.EIP155)) {
+		// 兼容尚未写入 user_wallets 的历史用户
+		return user.WalletAddress, nil
+	}
+
+	var onRewardChain, otherChain *entity.UserWallet
+	for _, wallet := range wallets {
+		account, ok := walletAccount(wallet)
+		if !ok || account.Chain.Namespace != string(chain.EIP155) {
+			continue
+		}
+		if account.Chain == w.rewardChain {
+			if onRewardChain == nil || wallet.IsPrimary {
+				onRewardChain = wallet
+			}
+		} else if wallet.KeyVerified && (otherChain == nil || wallet.IsPrimary) {
+			otherChain = wallet
+		}
+	}
+
+	switch {
+	case onRewardChain != nil:
+		return onRewardChain.Address, nil
+	case otherChain != nil:
+		return otherChain.Address, nil
+	default:
+		return "", fmt.Errorf("no %s wallet linked to receive rewards, link a wallet on that chain", w.rewardChain)
+	}
+}
+
+// walletAccount 返回钱包的 CAIP-10 标识，尚未回填的历史 EVM 钱包按 chain_id 推导
+func walletAccount(wallet *entity.UserWallet) (caip.AccountID, bool) {
+	if account, err := caip.ParseAccountID(wallet.Account); err == nil {
+		return account, true
+	}
+	if wallet.Namespace != "" && wallet.Namespace != string(chain.EIP155) {
+		return caip.AccountID{}, false
+	}
+	account, err := caip.NewAccountID(string(chain.EIP155), strconv.FormatInt(wallet.ChainID, 10), wallet.Address)
+	return account, err == nil
+}
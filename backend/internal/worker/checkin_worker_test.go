@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/internal/domain/entity"
+	"github.com/dedata/dedata-backend/internal/infrastructure/database"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB 连接 TEST_DATABASE_URL 指向的已执行迁移的 PostgreSQL，未设置时跳过
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+	return db
+}
+
+func randomAddress(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return common.BytesToAddress(b).Hex()
+}
+
+// createTestUser 创建主钱包在链 1 上的测试用户，测试结束时删除 (钱包和迁移记录级联删除)
+func createTestUser(t *testing.T, db *gorm.DB) *entity.User {
+	t.Helper()
+	address := randomAddress(t)
+	user := &entity.User{
+		DID:           "did:pkh:eip155:1:" + strings.ToLower(address),
+		Account:       "eip155:1:" + address,
+		WalletAddress: address,
+		ChainID:       1,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() { db.Delete(&entity.User{}, "id = ?", user.ID) })
+
+	wallet := &entity.UserWallet{
+		UserID:    user.ID,
+		Account:   user.Account,
+		Address:   address,
+		ChainID:   1,
+		IsPrimary: true,
+	}
+	if err := db.Create(wallet).Error; err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	return user
+}
+
+// 迁移到其他链上的新钱包后，只有签名证明持有私钥 (EOA) 的新主钱包可以在奖励链上收款
+func TestPayoutAddressAfterMigration(t *testing.T) {
+	db := testDB(t)
+	userRepo := database.NewGormUserRepository(db)
+	walletRepo := database.NewGormWalletRepository(db)
+	migrationRepo := database.NewGormMigrationRepository(db)
+	w := NewCheckinWorker(nil, userRepo, walletRepo, nil, nil, 80002, &config.CheckInConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	for _, keyVerified := range []bool{true, false} {
+		user := createTestUser(t, db)
+		migration := &entity.WalletMigration{
+			UserID:      user.ID,
+			FromAddress: user.WalletAddress,
+			ToAddress:   randomAddress(t),
+			ChainID:     137,
+			Mode:        entity.MigrationHandover,
+			KeyVerified: keyVerified,
+		}
+		if err := migrationRepo.Execute(ctx, migration, ""); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+
+		migrated, err := userRepo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		address, err := w.payoutAddress(ctx, migrated)
+		if keyVerified {
+			if err != nil || address != migration.ToAddress {
+				t.Errorf("payoutAddress after EOA migration = %q, %v; want %q", address, err, migration.ToAddress)
+			}
+		} else if err == nil {
+			t.Errorf("payoutAddress after contract wallet migration = %q, want error", address)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+-- Rollback: Remove SIWE fields from login_challenges
+ALTER TABLE login_challenges
+    DROP COLUMN IF EXISTS chain_id,
+    DROP COLUMN IF EXISTS domain,
+    DROP COLUMN IF EXISTS uri,
+    DROP COLUMN IF EXISTS issued_at;
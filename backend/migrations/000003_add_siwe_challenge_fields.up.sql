@@ -0,0 +1,11 @@
+-- Bind EIP-4361 (Sign-In with Ethereum) fields to login challenges
+ALTER TABLE login_challenges
+    ADD COLUMN IF NOT EXISTS chain_id BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS domain VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS uri VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+COMMENT ON COLUMN login_challenges.chain_id IS 'Chain ID bound into the SIWE message';
+COMMENT ON COLUMN login_challenges.domain IS 'Domain bound into the SIWE message';
+COMMENT ON COLUMN login_challenges.uri IS 'URI bound into the SIWE message';
+COMMENT ON COLUMN login_challenges.issued_at IS 'Issued At timestamp bound into the SIWE message';
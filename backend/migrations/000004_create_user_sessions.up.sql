@@ -0,0 +1,18 @@
+-- Login sessions (one per refresh token family)
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_user_active ON user_sessions (user_id, expires_at)
+    WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE user_sessions IS 'Login sessions; id is the sid claim shared by a refresh token family';
+COMMENT ON COLUMN user_sessions.last_seen_at IS 'Last login or token refresh time';
+COMMENT ON COLUMN user_sessions.revoked_at IS 'Set when the user logs out or revokes the session';
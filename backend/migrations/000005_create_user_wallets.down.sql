@@ -0,0 +1,2 @@
+-- Rollback: Drop user_wallets table
+DROP TABLE IF EXISTS user_wallets;
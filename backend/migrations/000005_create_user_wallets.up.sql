@@ -0,0 +1,20 @@
+-- Wallets linked to a user account (login from any of them resolves to the same user and DID)
+CREATE TABLE IF NOT EXISTS user_wallets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    address VARCHAR(42) NOT NULL,
+    chain_id BIGINT NOT NULL DEFAULT 1,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_user_wallets_address ON user_wallets (LOWER(address));
+CREATE INDEX idx_user_wallets_user_id ON user_wallets (user_id);
+
+-- Backfill the existing wallet of every user as its primary wallet
+INSERT INTO user_wallets (user_id, address, chain_id, is_primary, created_at)
+SELECT id, wallet_address, chain_id, TRUE, created_at FROM users
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE user_wallets IS 'Wallets linked to a user account';
+COMMENT ON COLUMN user_wallets.is_primary IS 'Primary wallet, mirrors users.wallet_address';
@@ -0,0 +1,2 @@
+-- Rollback: Drop wallet_migrations table
+DROP TABLE IF EXISTS wallet_migrations;
@@ -0,0 +1,27 @@
+-- History of accounts moved to a new wallet address
+CREATE TABLE IF NOT EXISTS wallet_migrations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_address VARCHAR(42) NOT NULL,
+    to_address VARCHAR(42) NOT NULL,
+    chain_id BIGINT NOT NULL DEFAULT 1,
+    mode VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    unlocks_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_wallet_migrations_user_id ON wallet_migrations (user_id, created_at DESC);
+CREATE INDEX idx_wallet_migrations_status ON wallet_migrations (status);
+
+-- At most one pending migration per user
+CREATE UNIQUE INDEX idx_wallet_migrations_user_pending ON wallet_migrations (user_id)
+    WHERE status = 'PENDING';
+
+COMMENT ON TABLE wallet_migrations IS 'Account migrations from one primary wallet to another';
+COMMENT ON COLUMN wallet_migrations.mode IS 'HANDOVER: signed by old and new wallet; RECOVERY: new wallet only, time-locked and admin-approved';
+COMMENT ON COLUMN wallet_migrations.unlocks_at IS 'Earliest time an admin may approve a RECOVERY migration';
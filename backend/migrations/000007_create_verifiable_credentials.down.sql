@@ -0,0 +1,3 @@
+-- Rollback: Drop verifiable_credentials table
+DROP TABLE IF EXISTS verifiable_credentials;
+DROP SEQUENCE IF EXISTS credential_status_index_seq;
@@ -0,0 +1,23 @@
+-- Verifiable Credentials issued to users (VC-JWT)
+CREATE SEQUENCE IF NOT EXISTS credential_status_index_seq;
+
+CREATE TABLE IF NOT EXISTS verifiable_credentials (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject_did VARCHAR(255) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    subject JSONB NOT NULL,
+    token TEXT NOT NULL,
+    status_index BIGINT NOT NULL UNIQUE,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_verifiable_credentials_user_id ON verifiable_credentials (user_id, issued_at DESC);
+CREATE INDEX idx_verifiable_credentials_revoked ON verifiable_credentials (status_index)
+    WHERE revoked_at IS NOT NULL;
+
+COMMENT ON TABLE verifiable_credentials IS 'Signed VC-JWT credentials asserting check-in and reward facts';
+COMMENT ON COLUMN verifiable_credentials.status_index IS 'Global revocation index: list = index / 131072, bit = index % 131072';
@@ -0,0 +1,19 @@
+-- Fails while accounts with Solana addresses (longer than 42 characters) exist
+DELETE FROM login_challenges WHERE chain_namespace <> 'eip155';
+
+DROP INDEX IF EXISTS idx_user_wallets_address;
+CREATE UNIQUE INDEX idx_user_wallets_address ON user_wallets (LOWER(address));
+
+ALTER TABLE login_challenges
+    DROP COLUMN IF EXISTS chain_namespace,
+    ALTER COLUMN chain_id TYPE BIGINT USING chain_id::BIGINT,
+    ALTER COLUMN chain_id SET DEFAULT 1,
+    ALTER COLUMN wallet_address TYPE VARCHAR(42);
+
+ALTER TABLE user_wallets
+    DROP COLUMN IF EXISTS chain_namespace,
+    ALTER COLUMN address TYPE VARCHAR(42);
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS chain_namespace,
+    ALTER COLUMN wallet_address TYPE VARCHAR(42);
@@ -0,0 +1,26 @@
+-- Chain family (CAIP-2 namespace) of login challenges, users and wallets, so that
+-- non-EVM wallets (Solana base58 addresses, up to 44 characters) can sign in
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS chain_namespace VARCHAR(16) NOT NULL DEFAULT 'eip155',
+    ALTER COLUMN wallet_address TYPE VARCHAR(64);
+
+ALTER TABLE user_wallets
+    ADD COLUMN IF NOT EXISTS chain_namespace VARCHAR(16) NOT NULL DEFAULT 'eip155',
+    ALTER COLUMN address TYPE VARCHAR(64);
+
+ALTER TABLE login_challenges
+    ADD COLUMN IF NOT EXISTS chain_namespace VARCHAR(16) NOT NULL DEFAULT 'eip155',
+    ALTER COLUMN wallet_address TYPE VARCHAR(64),
+    ALTER COLUMN chain_id DROP DEFAULT,
+    ALTER COLUMN chain_id TYPE VARCHAR(64) USING chain_id::TEXT;
+
+-- base58 addresses are case-sensitive, only EVM addresses are matched case-insensitively
+DROP INDEX IF EXISTS idx_user_wallets_address;
+CREATE UNIQUE INDEX idx_user_wallets_address ON user_wallets (
+    (CASE WHEN chain_namespace = 'eip155' THEN LOWER(address) ELSE address END)
+);
+
+COMMENT ON COLUMN users.chain_namespace IS 'CAIP-2 namespace of the wallet used to register';
+COMMENT ON COLUMN user_wallets.chain_namespace IS 'CAIP-2 namespace of the wallet';
+COMMENT ON COLUMN login_challenges.chain_namespace IS 'CAIP-2 namespace of the signing wallet';
+COMMENT ON COLUMN login_challenges.chain_id IS 'CAIP-2 reference bound into the sign-in message';
@@ -0,0 +1,11 @@
+-- Fails while the same address is linked on more than one chain
+DROP INDEX IF EXISTS idx_users_account;
+DROP INDEX IF EXISTS idx_user_wallets_account;
+DROP INDEX IF EXISTS idx_user_wallets_address;
+CREATE UNIQUE INDEX idx_user_wallets_address ON user_wallets (
+    (CASE WHEN chain_namespace = 'eip155' THEN LOWER(address) ELSE address END)
+);
+
+ALTER TABLE user_wallets DROP COLUMN IF EXISTS key_verified;
+ALTER TABLE user_wallets DROP COLUMN IF EXISTS account;
+ALTER TABLE users DROP COLUMN IF EXISTS account;
@@ -0,0 +1,35 @@
+-- CAIP-10 account identifiers (<namespace>:<reference>:<address>) for users and wallets.
+-- The same address on different chains is a different wallet; it can only belong to one user.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS account VARCHAR(200);
+ALTER TABLE user_wallets ADD COLUMN IF NOT EXISTS account VARCHAR(200);
+
+-- Whether the wallet's signature proved control of the private key (EOA / Solana).
+-- Only such wallets may receive rewards on a chain other than their own; EIP-1271 contract
+-- wallets may not exist, or belong to someone else, at the same address on another chain.
+ALTER TABLE user_wallets ADD COLUMN IF NOT EXISTS key_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Backfill EVM wallets from the chain they signed in with. Solana wallets did not record
+-- their cluster and are filled in on the next sign-in.
+UPDATE user_wallets
+SET account = 'eip155:' || chain_id || ':' || address
+WHERE chain_namespace = 'eip155' AND account IS NULL;
+
+-- Solana addresses are ed25519 public keys. Existing EVM wallets are unknown and get
+-- marked the next time their owner signs in with an EOA signature.
+UPDATE user_wallets SET key_verified = TRUE WHERE chain_namespace = 'solana';
+
+-- Users take the account and chain of their primary wallet (chain_id was hard-coded to 1)
+UPDATE users u
+SET account = w.account,
+    chain_id = w.chain_id
+FROM user_wallets w
+WHERE w.user_id = u.id AND w.is_primary AND w.account IS NOT NULL AND u.account IS NULL;
+
+DROP INDEX IF EXISTS idx_user_wallets_address;
+CREATE INDEX idx_user_wallets_address ON user_wallets (LOWER(address));
+CREATE UNIQUE INDEX idx_user_wallets_account ON user_wallets (account);
+CREATE UNIQUE INDEX idx_users_account ON users (account);
+
+COMMENT ON COLUMN users.account IS 'CAIP-10 account id of the primary wallet';
+COMMENT ON COLUMN user_wallets.account IS 'CAIP-10 account id of the wallet';
+COMMENT ON COLUMN user_wallets.key_verified IS 'Signature proved control of the private key (EOA / Solana); required to receive rewards on another chain';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_login_challenges_outstanding_ip;
+DROP INDEX IF EXISTS idx_login_challenges_outstanding_address;
+
+ALTER TABLE login_challenges DROP COLUMN IF EXISTS ip_address;
@@ -0,0 +1,10 @@
+-- Client IP of the nonce request, used to cap outstanding (unused, unexpired) challenges per IP
+-- when the PostgreSQL challenge store is used instead of Redis
+ALTER TABLE login_challenges ADD COLUMN IF NOT EXISTS ip_address VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_login_challenges_outstanding_address
+    ON login_challenges (wallet_address, expires_at) WHERE used = FALSE;
+CREATE INDEX IF NOT EXISTS idx_login_challenges_outstanding_ip
+    ON login_challenges (ip_address, expires_at) WHERE used = FALSE;
+
+COMMENT ON COLUMN login_challenges.ip_address IS 'Client IP that requested the nonce';
@@ -0,0 +1 @@
+ALTER TABLE login_challenges DROP COLUMN IF EXISTS pow_difficulty;
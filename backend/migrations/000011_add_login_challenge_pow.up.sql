@@ -0,0 +1,4 @@
+-- Proof-of-work difficulty (leading zero bits of SHA-256) required to redeem a login challenge
+ALTER TABLE login_challenges ADD COLUMN IF NOT EXISTS pow_difficulty INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN login_challenges.pow_difficulty IS 'Required hashcash difficulty in bits, 0 when proof-of-work is disabled';
@@ -0,0 +1,2 @@
+-- Rollback: Drop authorizations table
+DROP TABLE IF EXISTS authorizations;
@@ -0,0 +1,25 @@
+-- Platform authorizations granted by users (consent signed by the primary wallet)
+CREATE TABLE IF NOT EXISTS authorizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(50) NOT NULL,
+    scope VARCHAR(200),
+    status VARCHAR(20) DEFAULT 'ACTIVE',
+    signed_by VARCHAR(200),
+    message TEXT,
+    signature TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_authorizations_user_id ON authorizations (user_id, created_at DESC);
+CREATE UNIQUE INDEX idx_authorizations_active ON authorizations (user_id, platform)
+    WHERE status = 'ACTIVE';
+CREATE INDEX idx_authorizations_expires_at ON authorizations (expires_at)
+    WHERE status = 'ACTIVE';
+
+COMMENT ON TABLE authorizations IS 'Data access granted by users to partner platforms';
+COMMENT ON COLUMN authorizations.scope IS 'Space separated scopes, e.g. profile:read checkins:read';
+COMMENT ON COLUMN authorizations.signed_by IS 'CAIP-10 account id of the wallet that signed the consent message';
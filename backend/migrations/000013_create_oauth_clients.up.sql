@@ -0,0 +1,15 @@
+-- Third-party applications using "Log in with DeData" (OAuth2 / OpenID Connect)
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    secret_hash VARCHAR(64),
+    redirect_uris TEXT NOT NULL,
+    scope VARCHAR(200) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE oauth_clients IS 'OAuth2 clients; grants are stored in authorizations with platform = client id';
+COMMENT ON COLUMN oauth_clients.secret_hash IS 'SHA-256 of the client secret, empty for public clients (PKCE only)';
+COMMENT ON COLUMN oauth_clients.redirect_uris IS 'Space separated redirect URIs, matched exactly';
@@ -0,0 +1,22 @@
+-- Scoped API keys for server-to-server integrations (only the SHA-256 of the key is stored)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    key_hash VARCHAR(64) NOT NULL,
+    scope VARCHAR(200) NOT NULL,
+    platform VARCHAR(50),
+    rate_limit INTEGER DEFAULT 0,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(64),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE api_keys IS 'Scoped API keys; the full key is ddk_<8 hex>_<secret> and is shown once';
+COMMENT ON COLUMN api_keys.prefix IS 'Public part of the key used for lookup, e.g. ddk_1a2b3c4d';
+COMMENT ON COLUMN api_keys.platform IS 'Partner platform the key acts for, required by the authorizations:read scope';
+COMMENT ON COLUMN api_keys.rate_limit IS 'Requests per minute, 0 uses api_key.default_rate_limit';
@@ -0,0 +1,3 @@
+-- Rollback: Drop TOTP tables
+DROP TABLE IF EXISTS user_totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,24 @@
+-- TOTP second factor for admin accounts (the secret is AES-256-GCM encrypted by the application)
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_encrypted TEXT NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN user_totp.secret_encrypted IS 'base64(nonce || ciphertext), key from totp.encryption_key';
+COMMENT ON COLUMN user_totp.confirmed_at IS 'NULL until the first code is verified; unconfirmed enrollments are not enforced';
+COMMENT ON COLUMN user_totp.last_used_step IS 'Last accepted 30s time step, codes at or before it are rejected as replays';
+
+-- One-time recovery codes (only the SHA-256 of each code is stored)
+CREATE TABLE IF NOT EXISTS user_totp_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_totp_recovery_codes_user_id ON user_totp_recovery_codes(user_id);
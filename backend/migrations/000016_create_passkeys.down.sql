@@ -0,0 +1,3 @@
+-- Rollback: Drop passkeys table and session scope
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS scope;
+DROP TABLE IF EXISTS passkeys;
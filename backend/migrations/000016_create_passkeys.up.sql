@@ -0,0 +1,26 @@
+-- WebAuthn passkeys for password-less sign-in of returning users
+CREATE TABLE IF NOT EXISTS passkeys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100),
+    credential_id VARCHAR(1400) NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    algorithm INTEGER NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    aaguid VARCHAR(32),
+    transports VARCHAR(100),
+    backup_eligible BOOLEAN DEFAULT FALSE,
+    backed_up BOOLEAN DEFAULT FALSE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_passkeys_user_id ON passkeys(user_id);
+
+COMMENT ON COLUMN passkeys.credential_id IS 'WebAuthn credential ID, base64url without padding';
+COMMENT ON COLUMN passkeys.public_key IS 'Credential public key as a COSE_Key';
+
+-- Sessions started with a passkey are read-only
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS scope VARCHAR(16) NOT NULL DEFAULT 'full';
+
+COMMENT ON COLUMN user_sessions.scope IS 'full: wallet signature sign-in, read: passkey sign-in (no payments or wallet changes)';
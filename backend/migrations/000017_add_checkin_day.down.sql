@@ -0,0 +1,6 @@
+-- Rollback: Drop daily check-in slots
+DROP INDEX IF EXISTS idx_check_ins_user_day_slot;
+
+ALTER TABLE check_ins
+    DROP COLUMN IF EXISTS day_slot,
+    DROP COLUMN IF EXISTS checkin_day;
@@ -0,0 +1,23 @@
+-- Paid check-ins occupy one of the user's daily slots (checkin.daily_limit per day)
+ALTER TABLE check_ins
+    ADD COLUMN IF NOT EXISTS checkin_day DATE,
+    ADD COLUMN IF NOT EXISTS day_slot SMALLINT;
+
+-- Backfill paid rows; historical duplicates on the same day get increasing slots
+UPDATE check_ins c
+SET checkin_day = s.checkin_day,
+    day_slot = s.day_slot
+FROM (
+    SELECT id,
+           DATE(created_at) AS checkin_day,
+           ROW_NUMBER() OVER (PARTITION BY user_id, DATE(created_at) ORDER BY created_at) AS day_slot
+    FROM check_ins
+    WHERE status IN ('payment_success', 'issuing', 'success', 'issue_failed')
+) s
+WHERE c.id = s.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_check_ins_user_day_slot ON check_ins (user_id, checkin_day, day_slot)
+    WHERE checkin_day IS NOT NULL;
+
+COMMENT ON COLUMN check_ins.checkin_day IS 'Day the paid check-in counts towards, set when the payment is verified';
+COMMENT ON COLUMN check_ins.day_slot IS 'Slot within the day (1..checkin.daily_limit), unique per user and day';
@@ -0,0 +1,2 @@
+-- Rollback: Drop check-in day timezone tracking
+DROP TABLE IF EXISTS checkin_settings;
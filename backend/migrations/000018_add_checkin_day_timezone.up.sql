@@ -0,0 +1,12 @@
+-- Timezone the stored check_ins.checkin_day values were computed in.
+-- NULL means unknown (000017 backfilled with the database session timezone); the API recomputes
+-- checkin_day and day_slot at startup whenever this differs from checkin.timezone.
+CREATE TABLE IF NOT EXISTS checkin_settings (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    day_timezone TEXT
+);
+
+INSERT INTO checkin_settings (id, day_timezone) VALUES (1, NULL)
+ON CONFLICT (id) DO NOTHING;
+
+COMMENT ON COLUMN checkin_settings.day_timezone IS 'IANA timezone (checkin.timezone) used to compute check_ins.checkin_day';
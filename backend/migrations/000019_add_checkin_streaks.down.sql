@@ -0,0 +1,13 @@
+-- Rollback: Drop check-in streak tracking
+ALTER TABLE checkin_settings
+    DROP COLUMN IF EXISTS streak_grace_days;
+
+DROP TABLE IF EXISTS checkin_streak_rules;
+
+ALTER TABLE check_ins
+    DROP COLUMN IF EXISTS streak_days;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_streak_day,
+    DROP COLUMN IF EXISTS longest_streak,
+    DROP COLUMN IF EXISTS current_streak;
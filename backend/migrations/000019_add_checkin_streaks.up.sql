@@ -0,0 +1,30 @@
+-- Consecutive check-in day tracking for streak rewards
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS current_streak INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS longest_streak INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_streak_day DATE;
+
+ALTER TABLE check_ins
+    ADD COLUMN IF NOT EXISTS streak_days INTEGER NOT NULL DEFAULT 0;
+
+-- Streak reward rules, managed through the admin API.
+-- Reward = checkin.reward_amount * multiplier + bonus, using the rule with the highest min_days
+-- not above the check-in's streak; amounts are decimal strings validated by the API.
+CREATE TABLE IF NOT EXISTS checkin_streak_rules (
+    min_days INTEGER PRIMARY KEY CHECK (min_days >= 1),
+    multiplier VARCHAR(78) NOT NULL DEFAULT '1',
+    bonus VARCHAR(78) NOT NULL DEFAULT '0',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Grace days (checkin.streak_grace_days) the stored streaks were computed with.
+-- NULL means not computed yet: the API recomputes streaks from past check-ins at startup
+-- whenever this differs from the config, which also backfills existing users.
+ALTER TABLE checkin_settings
+    ADD COLUMN IF NOT EXISTS streak_grace_days INTEGER;
+
+COMMENT ON COLUMN users.current_streak IS 'Consecutive check-in days as of last_streak_day; broken once more than 1 + checkin.streak_grace_days days pass';
+COMMENT ON COLUMN users.last_streak_day IS 'Latest check-in day counted towards the streak';
+COMMENT ON COLUMN check_ins.streak_days IS 'Streak length including this check-in, selects the checkin_streak_rules reward';
+COMMENT ON TABLE checkin_streak_rules IS 'Streak reward rules: reward_amount * multiplier + bonus once the streak reaches min_days';
+COMMENT ON COLUMN checkin_settings.streak_grace_days IS 'checkin.streak_grace_days used to compute users.current_streak / longest_streak';
@@ -0,0 +1,3 @@
+-- Rollback: Drop migration key verification flag
+ALTER TABLE wallet_migrations
+    DROP COLUMN IF EXISTS key_verified;
@@ -0,0 +1,7 @@
+-- Whether the new wallet's signature recovered via ecrecover (EOA). The migration copies it to
+-- user_wallets.key_verified so the new primary wallet can receive rewards on other EVM chains;
+-- pending RECOVERY migrations keep it until an admin approves them.
+ALTER TABLE wallet_migrations
+    ADD COLUMN IF NOT EXISTS key_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN wallet_migrations.key_verified IS 'New wallet signature proved control of the private key (EOA); copied to user_wallets.key_verified';
@@ -0,0 +1,4 @@
+-- Fails while migrations with Solana addresses (longer than 42 characters) exist
+ALTER TABLE wallet_migrations
+    ALTER COLUMN from_address TYPE VARCHAR(42),
+    ALTER COLUMN to_address TYPE VARCHAR(42);
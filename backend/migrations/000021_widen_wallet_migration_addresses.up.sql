@@ -0,0 +1,5 @@
+-- Migration addresses must fit any address stored in users.wallet_address, which
+-- has been VARCHAR(64) since non-EVM wallets were added
+ALTER TABLE wallet_migrations
+    ALTER COLUMN from_address TYPE VARCHAR(64),
+    ALTER COLUMN to_address TYPE VARCHAR(64);
@@ -0,0 +1,71 @@
+package base58
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Bitcoin / Solana 使用的 base58 字母表
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ErrInvalidCharacter 输入包含字母表以外的字符
+var ErrInvalidCharacter = errors.New("base58: invalid character")
+
+var (
+	radix   = big.NewInt(58)
+	indexes [256]int
+)
+
+func init() {
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		indexes[alphabet[i]] = i
+	}
+}
+
+// Encode 编码为 base58 字符串，前导 0 字节编码为 '1'
+func Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+	out := make([]byte, 0, len(b)*138/100+1)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, alphabet[0])
+	}
+
+	// 反转为大端序
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Decode 解码 base58 字符串
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	for i := zeros; i < len(s); i++ {
+		v := indexes[s[i]]
+		if v < 0 {
+			return nil, ErrInvalidCharacter
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
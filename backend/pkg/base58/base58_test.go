@@ -0,0 +1,60 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// Bitcoin Core base58_encode_decode.json 测试向量
+var vectors = []struct {
+	hex     string
+	encoded string
+}{
+	{"", ""},
+	{"61", "2g"},
+	{"626262", "a3gV"},
+	{"636363", "aPEr"},
+	{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+	{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	{"516b6fcd0f", "ABnLTmg"},
+	{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+	{"572e4794", "3EFU7m"},
+	{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+	{"10c8511e", "Rt5zm"},
+	{"00000000000000000000", "1111111111"},
+	{"000000287fb4cd", "111233QC4"},
+}
+
+func TestEncode(t *testing.T) {
+	for _, v := range vectors {
+		b, _ := hex.DecodeString(v.hex)
+		if got := Encode(b); got != v.encoded {
+			t.Errorf("Encode(%s) = %q, want %q", v.hex, got, v.encoded)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, v := range vectors {
+		want, _ := hex.DecodeString(v.hex)
+		got, err := Decode(v.encoded)
+		if err != nil {
+			t.Errorf("Decode(%q): %v", v.encoded, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Decode(%q) = %x, want %s", v.encoded, got, v.hex)
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidCharacters(t *testing.T) {
+	// 0、O、I、l 不在字母表中，非 ASCII 字符同样拒绝
+	for _, s := range []string{"0", "O", "I", "l", "3SEo3LW0PntC", "abc+", "2g ", "ü"} {
+		if _, err := Decode(s); !errors.Is(err, ErrInvalidCharacter) {
+			t.Errorf("Decode(%q) error = %v, want ErrInvalidCharacter", s, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package caip
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// CAIP-2 链标识与 CAIP-10 账户标识:
+//
+//	chain_id:   <namespace>:<reference>            例如 eip155:137
+//	account_id: <namespace>:<reference>:<address>  例如 eip155:137:0xAbC...
+//
+// 本包只做语法校验，地址的规范化 (EIP-55 checksum 等) 由 pkg/chain 中对应的链家族完成
+
+var (
+	// ErrInvalidChainID CAIP-2 标识语法不合法
+	ErrInvalidChainID = errors.New("invalid caip-2 chain id")
+
+	// ErrInvalidAccountID CAIP-10 标识语法不合法
+	ErrInvalidAccountID = errors.New("invalid caip-10 account id")
+)
+
+var (
+	namespacePattern = regexp.MustCompile(`^[-a-z0-9]{3,8}$`)
+	referencePattern = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,32}$`)
+	addressPattern   = regexp.MustCompile(`^[-.%a-zA-Z0-9]{1,128}$`)
+)
+
+// ChainID CAIP-2 链标识
+type ChainID struct {
+	Namespace string
+	Reference string
+}
+
+// NewChainID 校验并创建链标识
+func NewChainID(namespace, reference string) (ChainID, error) {
+	if !namespacePattern.MatchString(namespace) || !referencePattern.MatchString(reference) {
+		return ChainID{}, ErrInvalidChainID
+	}
+	return ChainID{Namespace: namespace, Reference: reference}, nil
+}
+
+// ParseChainID 解析 "<namespace>:<reference>"
+func ParseChainID(s string) (ChainID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return ChainID{}, ErrInvalidChainID
+	}
+	return NewChainID(parts[0], parts[1])
+}
+
+func (c ChainID) String() string {
+	return c.Namespace + ":" + c.Reference
+}
+
+// AccountID CAIP-10 账户标识
+type AccountID struct {
+	Chain   ChainID
+	Address string
+}
+
+// NewAccountID 校验并创建账户标识
+func NewAccountID(namespace, reference, address string) (AccountID, error) {
+	chain, err := NewChainID(namespace, reference)
+	if err != nil {
+		return AccountID{}, ErrInvalidAccountID
+	}
+	if !addressPattern.MatchString(address) {
+		return AccountID{}, ErrInvalidAccountID
+	}
+	return AccountID{Chain: chain, Address: address}, nil
+}
+
+// ParseAccountID 解析 "<namespace>:<reference>:<address>"
+func ParseAccountID(s string) (AccountID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return AccountID{}, ErrInvalidAccountID
+	}
+	return NewAccountID(parts[0], parts[1], parts[2])
+}
+
+func (a AccountID) String() string {
+	return a.Chain.String() + ":" + a.Address
+}
@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dedata/dedata-backend/pkg/caip"
+)
+
+// Namespace 链家族，取值为 CAIP-2 namespace
+type Namespace string
+
+const (
+	EIP155 Namespace = "eip155" // 以太坊及 EVM 兼容链，reference 为十进制链 ID
+	Solana Namespace = "solana" // Solana，reference 为 genesis hash 前 32 个字符
+)
+
+var (
+	// ErrUnsupportedNamespace 未注册的链家族
+	ErrUnsupportedNamespace = errors.New("unsupported chain namespace")
+
+	// ErrInvalidAddress 地址不属于任何已注册的链家族
+	ErrInvalidAddress = errors.New("invalid wallet address")
+)
+
+// Family 一种链家族的地址规范化与签名验证
+type Family interface {
+	// Namespace CAIP-2 namespace
+	Namespace() Namespace
+
+	// Blockchain 登录消息头部中的链名称 ("... sign in with your Ethereum account:")
+	Blockchain() string
+
+	// NormalizeAddress 校验地址并返回规范形式，地址不合法时返回 ErrInvalidAddress
+	NormalizeAddress(address string) (string, error)
+
+	// VerifyMessage 验证钱包对原始消息的签名，chainID 为 CAIP-2 reference
+	VerifyMessage(ctx context.Context, chainID, message, signature, address string) (bool, error)
+}
+
+// Registry 已启用的链家族
+type Registry struct {
+	families []Family
+}
+
+// NewRegistry 创建链家族注册表，Detect 按注册顺序匹配地址格式
+func NewRegistry(families ...Family) *Registry {
+	return &Registry{families: families}
+}
+
+// Get 按 namespace 获取链家族
+func (r *Registry) Get(namespace Namespace) (Family, error) {
+	for _, f := range r.families {
+		if f.Namespace() == namespace {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedNamespace, namespace)
+}
+
+// Detect 根据地址格式推断链家族 (0x hex 为 EVM，32 字节 base58 为 Solana)
+func (r *Registry) Detect(address string) (Family, error) {
+	for _, f := range r.families {
+		if _, err := f.NormalizeAddress(address); err == nil {
+			return f, nil
+		}
+	}
+	return nil, ErrInvalidAddress
+}
+
+// Account 构造规范化的 CAIP-10 账户标识，地址按链家族规范化 (EVM 为 EIP-55 checksum)
+func (r *Registry) Account(namespace Namespace, reference, address string) (caip.AccountID, error) {
+	family, err := r.Get(namespace)
+	if err != nil {
+		return caip.AccountID{}, err
+	}
+	if namespace == EIP155 {
+		if id, err := strconv.ParseInt(reference, 10, 64); err != nil || id <= 0 {
+			return caip.AccountID{}, caip.ErrInvalidAccountID
+		}
+	}
+	normalized, err := family.NormalizeAddress(address)
+	if err != nil {
+		return caip.AccountID{}, err
+	}
+	return caip.NewAccountID(string(namespace), reference, normalized)
+}
+
+// ParseAccount 解析 CAIP-10 标识并规范化地址
+func (r *Registry) ParseAccount(s string) (caip.AccountID, error) {
+	id, err := caip.ParseAccountID(s)
+	if err != nil {
+		return caip.AccountID{}, err
+	}
+	return r.Account(Namespace(id.Chain.Namespace), id.Chain.Reference, id.Address)
+}
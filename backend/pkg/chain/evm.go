@@ -0,0 +1,45 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EVMFamily 以太坊及 EVM 兼容链，签名为 personal_sign (EIP-191)，支持 EIP-1271 合约钱包
+type EVMFamily struct {
+	verifier crypto.SignatureVerifier
+}
+
+// NewEVM 创建 EVM 链家族
+func NewEVM(verifier crypto.SignatureVerifier) *EVMFamily {
+	return &EVMFamily{verifier: verifier}
+}
+
+func (e *EVMFamily) Namespace() Namespace {
+	return EIP155
+}
+
+func (e *EVMFamily) Blockchain() string {
+	return "Ethereum"
+}
+
+// NormalizeAddress 返回 EIP-55 checksum 地址
+func (e *EVMFamily) NormalizeAddress(address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", ErrInvalidAddress
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
+
+// VerifyMessage 验证 personal_sign 签名 (0x hex)
+func (e *EVMFamily) VerifyMessage(ctx context.Context, chainID, message, signature, address string) (bool, error) {
+	id, err := strconv.ParseInt(chainID, 10, 64)
+	if err != nil || id <= 0 {
+		return false, fmt.Errorf("invalid chain id %q", chainID)
+	}
+	return crypto.VerifyPersonalSign(ctx, e.verifier, id, message, signature, address)
+}
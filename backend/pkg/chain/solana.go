@@ -0,0 +1,78 @@
+package chain
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/dedata/dedata-backend/pkg/base58"
+)
+
+// Solana 集群的 CAIP-2 reference
+const (
+	SolanaMainnet = "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
+	SolanaDevnet  = "EtWTRABZaYq6iMfeYKouRu166VU2xqa1"
+	SolanaTestnet = "4uhcVJyU9pJkvQyS88uRDiswHXSCkY3z"
+)
+
+// SolanaFamily Solana 钱包，地址即 ed25519 公钥 (base58)，签名为 signMessage 对原始消息字节的 ed25519 签名
+type SolanaFamily struct{}
+
+// NewSolana 创建 Solana 链家族
+func NewSolana() *SolanaFamily {
+	return &SolanaFamily{}
+}
+
+func (s *SolanaFamily) Namespace() Namespace {
+	return Solana
+}
+
+func (s *SolanaFamily) Blockchain() string {
+	return "Solana"
+}
+
+// NormalizeAddress 地址必须是 32 字节公钥的 base58 编码，base58 区分大小写，原样返回
+func (s *SolanaFamily) NormalizeAddress(address string) (string, error) {
+	if _, err := solanaPublicKey(address); err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// VerifyMessage 验证 ed25519 签名，签名支持 base58 (钱包适配器常用) 或 base64 编码
+// Solana 签名与链无关，chainID 仅由调用方与 challenge 比对
+func (s *SolanaFamily) VerifyMessage(_ context.Context, _, message, signature, address string) (bool, error) {
+	publicKey, err := solanaPublicKey(address)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := decodeSolanaSignature(signature)
+	if err != nil {
+		return false, err
+	}
+
+	return ed25519.Verify(publicKey, []byte(message), sig), nil
+}
+
+func solanaPublicKey(address string) (ed25519.PublicKey, error) {
+	if len(address) < 32 || len(address) > 44 {
+		return nil, ErrInvalidAddress
+	}
+	b, err := base58.Decode(address)
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, ErrInvalidAddress
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+func decodeSolanaSignature(signature string) ([]byte, error) {
+	if b, err := base58.Decode(signature); err == nil && len(b) == ed25519.SignatureSize {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(signature); err == nil && len(b) == ed25519.SignatureSize {
+		return b, nil
+	}
+	return nil, fmt.Errorf("invalid signature format")
+}
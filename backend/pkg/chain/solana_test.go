@@ -0,0 +1,121 @@
+package chain
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dedata/dedata-backend/pkg/base58"
+	"github.com/dedata/dedata-backend/pkg/crypto"
+)
+
+// newSolanaWallet 生成 ed25519 密钥，返回 base58 地址和私钥
+func newSolanaWallet(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return base58.Encode(pub), priv
+}
+
+func TestSolanaVerifyMessage(t *testing.T) {
+	address, key := newSolanaWallet(t)
+	other, _ := newSolanaWallet(t)
+
+	message := "dedata.io wants you to sign in with your Solana account:\n" + address
+	sig := ed25519.Sign(key, []byte(message))
+
+	tests := []struct {
+		name      string
+		message   string
+		signature string
+		address   string
+		want      bool
+	}{
+		{"base58 signature", message, base58.Encode(sig), address, true},
+		{"base64 signature", message, base64.StdEncoding.EncodeToString(sig), address, true},
+		{"other address", message, base58.Encode(sig), other, false},
+		{"tampered message", message + "\n", base58.Encode(sig), address, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := NewSolana().VerifyMessage(context.Background(), SolanaMainnet, tt.message, tt.signature, tt.address)
+			if err != nil {
+				t.Fatalf("VerifyMessage: %v", err)
+			}
+			if valid != tt.want {
+				t.Fatalf("VerifyMessage = %v, want %v", valid, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolanaVerifyMessageRejectsMalformedSignature(t *testing.T) {
+	address, key := newSolanaWallet(t)
+	sig := ed25519.Sign(key, []byte("message"))
+
+	for name, signature := range map[string]string{
+		"short":         base58.Encode(sig[:63]),
+		"long":          base58.Encode(append(sig, 0)),
+		"bad character": "0" + base58.Encode(sig)[1:],
+		"hex":           "0x" + strings.Repeat("ab", 64),
+	} {
+		if _, err := NewSolana().VerifyMessage(context.Background(), SolanaMainnet, "message", signature, address); err == nil {
+			t.Errorf("%s: VerifyMessage accepted signature %q", name, signature)
+		}
+	}
+}
+
+func TestSolanaNormalizeAddress(t *testing.T) {
+	address, _ := newSolanaWallet(t)
+
+	if got, err := NewSolana().NormalizeAddress(address); err != nil || got != address {
+		t.Fatalf("NormalizeAddress(%q) = %q, %v", address, got, err)
+	}
+
+	for name, bad := range map[string]string{
+		"empty":         "",
+		"31 bytes":      base58.Encode(make([]byte, 31)),
+		"33 bytes":      base58.Encode(append([]byte{1}, make([]byte, 32)...)),
+		"bad character": "0" + address[1:],
+		"evm address":   "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		if _, err := NewSolana().NormalizeAddress(bad); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("%s: NormalizeAddress(%q) error = %v, want ErrInvalidAddress", name, bad, err)
+		}
+	}
+}
+
+func TestRegistryDetect(t *testing.T) {
+	registry := NewRegistry(NewEVM(crypto.NewECDSAVerifier()), NewSolana())
+	solanaAddress, _ := newSolanaWallet(t)
+
+	tests := []struct {
+		address string
+		want    Namespace
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", EIP155},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", EIP155},
+		{solanaAddress, Solana},
+	}
+	for _, tt := range tests {
+		family, err := registry.Detect(tt.address)
+		if err != nil {
+			t.Fatalf("Detect(%q): %v", tt.address, err)
+		}
+		if family.Namespace() != tt.want {
+			t.Fatalf("Detect(%q) = %s, want %s", tt.address, family.Namespace(), tt.want)
+		}
+	}
+
+	if _, err := registry.Detect("not-a-wallet"); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("Detect error = %v, want ErrInvalidAddress", err)
+	}
+	if _, err := NewRegistry(NewEVM(crypto.NewECDSAVerifier())).Get(Solana); !errors.Is(err, ErrUnsupportedNamespace) {
+		t.Fatalf("Get error = %v, want ErrUnsupportedNamespace", err)
+	}
+}
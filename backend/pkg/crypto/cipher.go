@@ -0,0 +1,58 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// SecretCipher 使用 AES-256-GCM 加密需要落库的敏感数据 (例如 TOTP 密钥)
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher 使用 base64 编码的 32 字节密钥创建加密器
+func NewSecretCipher(encodedKey string) (*SecretCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt 加密并返回 base64(nonce || ciphertext)，associatedData 绑定密文的归属 (例如用户 ID)
+func (c *SecretCipher) Encrypt(plaintext, associatedData []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, associatedData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 的输出，associatedData 必须与加密时一致
+func (c *SecretCipher) Decrypt(encoded string, associatedData []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	return c.aead.Open(nil, sealed[:size], sealed[size:], associatedData)
+}
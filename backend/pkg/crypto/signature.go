@@ -3,6 +3,7 @@ package crypto
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"strings"
@@ -14,13 +15,24 @@ import (
 
 // GenerateNonce 生成随机 nonce
 func GenerateNonce() (string, error) {
-	b := make([]byte, 32)
+	return RandomHex(32)
+}
+
+// RandomHex 生成 n 字节随机数的 hex 字符串
+func RandomHex(n int) (string, error) {
+	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
 	return hex.EncodeToString(b), nil
 }
 
+// HashToken 计算不透明令牌的 SHA-256 摘要 (hex)，服务端只保存摘要
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // VerifySignature 验证以太坊签名
 // message: 原始消息
 // signature: 签名 (0x... 格式)
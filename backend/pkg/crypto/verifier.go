@@ -0,0 +1,165 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EIP-1271 isValidSignature(bytes32,bytes) ABI
+const eip1271ABI = `[{"constant":true,"inputs":[{"name":"_hash","type":"bytes32"},{"name":"_signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+
+// EIP1271MagicValue isValidSignature 验证通过时返回的魔数 (bytes4(keccak256("isValidSignature(bytes32,bytes)")))
+var EIP1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+// ErrUnsupportedChain 合约钱包验证所需的链上客户端未配置
+var ErrUnsupportedChain = errors.New("no RPC client configured for chain")
+
+// SignatureVerifier 签名验证接口
+// hash 为待验证的摘要 (personal_sign 时为 EIP-191 哈希)
+type SignatureVerifier interface {
+	VerifyHash(ctx context.Context, chainID int64, hash common.Hash, signature []byte, address common.Address) (bool, error)
+}
+
+// VerifyPersonalSign 使用给定 verifier 验证 personal_sign 签名
+func VerifyPersonalSign(ctx context.Context, v SignatureVerifier, chainID int64, message, signature, address string) (bool, error) {
+	address = strings.TrimSpace(address)
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("invalid address format")
+	}
+
+	sigBytes, err := hexutil.Decode(signature)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature format: %w", err)
+	}
+
+	hash := common.BytesToHash(accounts.TextHash([]byte(message)))
+	return v.VerifyHash(ctx, chainID, hash, sigBytes, common.HexToAddress(address))
+}
+
+// ECDSAVerifier 仅支持 EOA 的 ECDSA 签名验证
+type ECDSAVerifier struct{}
+
+// NewECDSAVerifier 创建 ECDSA 验证器
+func NewECDSAVerifier() *ECDSAVerifier {
+	return &ECDSAVerifier{}
+}
+
+// VerifyHash 通过 ecrecover 验证签名
+func (v *ECDSAVerifier) VerifyHash(_ context.Context, _ int64, hash common.Hash, signature []byte, address common.Address) (bool, error) {
+	recovered, err := recoverHash(hash, signature)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(recovered.Bytes(), address.Bytes()), nil
+}
+
+// ContractWalletVerifier 支持 EIP-1271 合约钱包 (Safe 等) 的签名验证
+// 先尝试 ECDSA 恢复，不匹配时若地址为合约则调用 isValidSignature
+type ContractWalletVerifier struct {
+	callers map[int64]bind.ContractCaller
+	abi     abi.ABI
+}
+
+// NewContractWalletVerifier 创建合约钱包验证器
+// callers: chainID -> 链上只读客户端 (ethclient.Client 或 simulated backend)，每条允许登录的 EVM 链都需要一个
+func NewContractWalletVerifier(callers map[int64]bind.ContractCaller) (*ContractWalletVerifier, error) {
+	parsed, err := abi.JSON(strings.NewReader(eip1271ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EIP-1271 ABI: %w", err)
+	}
+	return &ContractWalletVerifier{
+		callers: callers,
+		abi:     parsed,
+	}, nil
+}
+
+// Supports 是否配置了 chainID 的链上客户端
+func (v *ContractWalletVerifier) Supports(chainID int64) bool {
+	_, ok := v.callers[chainID]
+	return ok
+}
+
+// VerifyHash 验证签名 (EOA 或 EIP-1271 合约钱包)
+func (v *ContractWalletVerifier) VerifyHash(ctx context.Context, chainID int64, hash common.Hash, signature []byte, address common.Address) (bool, error) {
+	// 1. EOA 签名: ECDSA 恢复匹配则直接通过
+	if len(signature) == 65 {
+		if recovered, err := recoverHash(hash, signature); err == nil && recovered == address {
+			return true, nil
+		}
+	}
+
+	// 2. 合约钱包: 需要该链的客户端，未配置时返回错误而不是当作签名无效
+	caller, ok := v.callers[chainID]
+	if !ok {
+		return false, fmt.Errorf("%w: %d", ErrUnsupportedChain, chainID)
+	}
+
+	code, err := caller.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	// 3. 调用 isValidSignature(bytes32,bytes)
+	data, err := v.abi.Pack("isValidSignature", hash, signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature: %w", err)
+	}
+
+	result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		// 合约 revert 视为签名无效，节点不可用等其他错误原样返回
+		if isRevert(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to call isValidSignature: %w", err)
+	}
+	if len(result) < 4 {
+		return false, nil
+	}
+
+	return bytes.Equal(result[:4], EIP1271MagicValue[:]), nil
+}
+
+// isRevert 判断 eth_call 的错误是否为合约 revert (JSON-RPC 错误码 3，或部分节点只返回 "execution reverted")
+func isRevert(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+// recoverHash 从摘要和 65 字节签名中恢复签名者地址
+func recoverHash(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+
+	// 处理 v 值 (某些钱包会返回 27/28, 需要转换为 0/1)，不修改调用方的切片
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
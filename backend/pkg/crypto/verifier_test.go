@@ -0,0 +1,140 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+const testChainID = 1337
+
+var (
+	approverAddress = common.HexToAddress("0x0000000000000000000000000000000000001271")
+	revertAddress   = common.HexToAddress("0x0000000000000000000000000000000000001272")
+	approvedHash    = crypto.Keccak256Hash([]byte("approved"))
+)
+
+// approverCode 最小的 EIP-1271 合约运行时代码：
+// isValidSignature 的 hash 参数等于 approvedHash 时返回魔数，否则返回 0x00000000
+func approverCode(hash common.Hash) []byte {
+	code := []byte{
+		0x60, 0x04, 0x35, // PUSH1 4 CALLDATALOAD
+		0x7f, // PUSH32 hash
+	}
+	code = append(code, hash.Bytes()...)
+	code = append(code,
+		0x14,             // EQ
+		0x60, 0x2d, 0x57, // PUSH1 0x2d JUMPI
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN mem[0:32] (全 0)
+		0x5b,                         // JUMPDEST (0x2d)
+		0x63, 0x16, 0x26, 0xba, 0x7e, // PUSH4 magic
+		0x60, 0xe0, 0x1b, // PUSH1 224 SHL
+		0x60, 0x00, 0x52, // PUSH1 0 MSTORE
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN mem[0:32]
+	)
+	return code
+}
+
+// revertCode 任何调用都 revert 的合约
+var revertCode = []byte{0x60, 0x00, 0x60, 0x00, 0xfd}
+
+func newSimulatedVerifier(t *testing.T) *ContractWalletVerifier {
+	t.Helper()
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		approverAddress: {Code: approverCode(approvedHash), Balance: big.NewInt(0)},
+		revertAddress:   {Code: revertCode, Balance: big.NewInt(0)},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	v, err := NewContractWalletVerifier(map[int64]bind.ContractCaller{testChainID: backend.Client()})
+	if err != nil {
+		t.Fatalf("NewContractWalletVerifier: %v", err)
+	}
+	return v
+}
+
+func TestContractWalletVerifier(t *testing.T) {
+	v := newSimulatedVerifier(t)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	eoa := crypto.PubkeyToAddress(key.PublicKey)
+	eoaSig, err := crypto.Sign(approvedHash.Bytes(), key)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	contractSig := []byte{0x01, 0x02, 0x03}
+
+	tests := []struct {
+		name      string
+		chainID   int64
+		hash      common.Hash
+		signature []byte
+		address   common.Address
+		want      bool
+	}{
+		{"eoa signature", testChainID, approvedHash, eoaSig, eoa, true},
+		{"eoa signature for other address", testChainID, approvedHash, eoaSig, common.HexToAddress("0x1234"), false},
+		{"contract returns magic value", testChainID, approvedHash, contractSig, approverAddress, true},
+		{"contract returns wrong value", testChainID, crypto.Keccak256Hash([]byte("other")), contractSig, approverAddress, false},
+		{"contract reverts", testChainID, approvedHash, contractSig, revertAddress, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyHash(context.Background(), tt.chainID, tt.hash, tt.signature, tt.address)
+			if err != nil {
+				t.Fatalf("VerifyHash returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("VerifyHash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContractWalletVerifierUnsupportedChain(t *testing.T) {
+	v := newSimulatedVerifier(t)
+
+	// 未配置客户端的链不能静默返回 false，否则合约钱包登录看似支持却总是失败
+	valid, err := v.VerifyHash(context.Background(), 1, approvedHash, []byte{0x01, 0x02, 0x03}, approverAddress)
+	if !errors.Is(err, ErrUnsupportedChain) {
+		t.Fatalf("VerifyHash = %v, %v, want ErrUnsupportedChain", valid, err)
+	}
+	if v.Supports(1) || !v.Supports(testChainID) {
+		t.Fatalf("Supports reports wrong chains")
+	}
+}
+
+// failingCaller 模拟节点不可用：地址有代码，但 eth_call 返回传输错误
+type failingCaller struct{}
+
+func (failingCaller) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x00}, nil
+}
+
+func (failingCaller) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, errors.New("dial tcp: connection refused")
+}
+
+func TestContractWalletVerifierRPCError(t *testing.T) {
+	v, err := NewContractWalletVerifier(map[int64]bind.ContractCaller{testChainID: failingCaller{}})
+	if err != nil {
+		t.Fatalf("NewContractWalletVerifier: %v", err)
+	}
+
+	valid, err := v.VerifyHash(context.Background(), testChainID, approvedHash, []byte{0x01}, approverAddress)
+	if err == nil {
+		t.Fatalf("VerifyHash = %v, want RPC error", valid)
+	}
+}
@@ -0,0 +1,80 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dedata/dedata-backend/pkg/base58"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// did:dedata 标识符格式:
+//
+//	did:dedata:<注册时主钱包地址>
+//
+// EVM 地址使用小写 hex，Solana 地址 (base58，区分大小写) 原样保留。
+// 标识符在账户创建时确定，之后迁移主钱包或绑定新钱包都不会改变 DID，
+// 当前控制 DID 的钱包以 DID Document 中的 verificationMethod 为准
+
+const (
+	// Method DeData DID method 名称
+	Method = "dedata"
+
+	prefix = "did:" + Method + ":"
+)
+
+var (
+	// ErrInvalidDID DID 语法不合法
+	ErrInvalidDID = errors.New("invalid did")
+
+	// ErrMethodNotSupported 不是 did:dedata
+	ErrMethodNotSupported = errors.New("did method not supported")
+)
+
+// Format 根据钱包地址生成 did:dedata 标识符
+func Format(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return prefix + strings.ToLower(address)
+	}
+	return prefix + address
+}
+
+// Parse 解析并规范化 did:dedata 标识符，返回规范形式
+func Parse(did string) (string, error) {
+	parts := strings.SplitN(did, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" || parts[1] == "" || parts[2] == "" {
+		return "", ErrInvalidDID
+	}
+	if parts[1] != Method {
+		return "", ErrMethodNotSupported
+	}
+	isEVM := common.IsHexAddress(parts[2]) && strings.HasPrefix(parts[2], "0x")
+	if !isEVM {
+		if key, err := base58.Decode(parts[2]); err != nil || len(key) != 32 {
+			return "", ErrInvalidDID
+		}
+	}
+	return Format(parts[2]), nil
+}
+
+// WebDID 根据服务的 base URL 生成 did:web 标识符，例如
+// "https://api.dedata.io" -> "did:web:api.dedata.io"，端口中的冒号按规范编码为 %3A
+func WebDID(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid base url: %q", baseURL)
+	}
+
+	id := "did:web:" + strings.ReplaceAll(u.Host, ":", "%3A")
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		id += ":" + strings.ReplaceAll(path, "/", ":")
+	}
+	return id, nil
+}
+
+// BlockchainAccountID 返回 CAIP-10 账户标识，例如 "eip155:137:0xAbC..."
+func BlockchainAccountID(chainID int64, address string) string {
+	return fmt.Sprintf("eip155:%d:%s", chainID, common.HexToAddress(address).Hex())
+}
@@ -0,0 +1,127 @@
+package did
+
+import "time"
+
+// JSON-LD context
+const (
+	ContextDIDv1         = "https://www.w3.org/ns/did/v1"
+	ContextSecp256k1     = "https://w3id.org/security/suites/secp256k1recovery-2020/v2"
+	ContextJWS2020       = "https://w3id.org/security/suites/jws-2020/v1"
+	ContextEd25519       = "https://w3id.org/security/suites/ed25519-2018/v1"
+	ContextDIDResolution = "https://w3id.org/did-resolution/v1"
+)
+
+// Verification method 类型
+const (
+	TypeSecp256k1Recovery = "EcdsaSecp256k1RecoveryMethod2020" // 以太坊钱包 (ecrecover)
+	TypeEd25519           = "Ed25519VerificationKey2018"       // Solana 钱包 (地址即公钥)
+	TypeJSONWebKey        = "JsonWebKey2020"
+)
+
+// Content type
+const (
+	MediaTypeDIDLDJSON  = "application/did+ld+json"
+	MediaTypeResolution = `application/ld+json;profile="https://w3id.org/did-resolution"`
+)
+
+// Document W3C DID Document (DID Core 1.0)
+type Document struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	AlsoKnownAs        []string             `json:"alsoKnownAs,omitempty"`
+	Controller         string               `json:"controller,omitempty"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Authentication     []string             `json:"authentication"`
+	AssertionMethod    []string             `json:"assertionMethod"`
+	Service            []Service            `json:"service,omitempty"`
+}
+
+// VerificationMethod 验证方法，EVM 钱包使用 blockchainAccountId，Solana 钱包使用 publicKeyBase58，服务端密钥使用 publicKeyJwk
+type VerificationMethod struct {
+	ID                  string      `json:"id"`
+	Type                string      `json:"type"`
+	Controller          string      `json:"controller"`
+	BlockchainAccountID string      `json:"blockchainAccountId,omitempty"`
+	PublicKeyBase58     string      `json:"publicKeyBase58,omitempty"`
+	PublicKeyJwk        interface{} `json:"publicKeyJwk,omitempty"`
+}
+
+// Service 服务端点
+type Service struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// DocumentMetadata DID Document 元数据
+type DocumentMetadata struct {
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Deactivated bool       `json:"deactivated,omitempty"`
+}
+
+// ResolutionMetadata 解析过程元数据，失败时 Error 为 invalidDid / notFound / methodNotSupported
+type ResolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ResolutionResult DID Resolution 结果
+type ResolutionResult struct {
+	Context               string             `json:"@context"`
+	DIDDocument           *Document          `json:"didDocument"`
+	DIDDocumentMetadata   DocumentMetadata   `json:"didDocumentMetadata"`
+	DIDResolutionMetadata ResolutionMetadata `json:"didResolutionMetadata"`
+}
+
+// 解析错误码 (DID Resolution 规范)
+const (
+	ErrorInvalidDID         = "invalidDid"
+	ErrorNotFound           = "notFound"
+	ErrorMethodNotSupported = "methodNotSupported"
+)
+
+// NewDocument 创建空的 DID Document
+func NewDocument(id string, contexts ...string) *Document {
+	return &Document{
+		Context:            append([]string{ContextDIDv1}, contexts...),
+		ID:                 id,
+		VerificationMethod: []VerificationMethod{},
+		Authentication:     []string{},
+		AssertionMethod:    []string{},
+	}
+}
+
+// AddVerificationMethod 添加验证方法，并同时用于 authentication 和 assertionMethod
+func (d *Document) AddVerificationMethod(vm VerificationMethod) {
+	d.VerificationMethod = append(d.VerificationMethod, vm)
+	d.Authentication = append(d.Authentication, vm.ID)
+	d.AssertionMethod = append(d.AssertionMethod, vm.ID)
+}
+
+// AddService 添加服务端点
+func (d *Document) AddService(fragment, serviceType, endpoint string) {
+	d.Service = append(d.Service, Service{
+		ID:              d.ID + "#" + fragment,
+		Type:            serviceType,
+		ServiceEndpoint: endpoint,
+	})
+}
+
+// NewResolutionResult 包装成功的解析结果
+func NewResolutionResult(doc *Document, meta DocumentMetadata) *ResolutionResult {
+	return &ResolutionResult{
+		Context:               ContextDIDResolution,
+		DIDDocument:           doc,
+		DIDDocumentMetadata:   meta,
+		DIDResolutionMetadata: ResolutionMetadata{ContentType: MediaTypeDIDLDJSON},
+	}
+}
+
+// NewResolutionError 包装失败的解析结果
+func NewResolutionError(code string) *ResolutionResult {
+	return &ResolutionResult{
+		Context:               ContextDIDResolution,
+		DIDResolutionMetadata: ResolutionMetadata{Error: code},
+	}
+}
@@ -0,0 +1,29 @@
+package did
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound DID 不存在
+var ErrNotFound = errors.New("did not found")
+
+// Resolver 将 DID 解析为 DID Document
+// 返回 ErrInvalidDID / ErrMethodNotSupported / ErrNotFound 时调用方应转换为对应的解析错误码
+type Resolver interface {
+	Resolve(ctx context.Context, did string) (*ResolutionResult, error)
+}
+
+// ErrorCode 将解析错误转换为 DID Resolution 错误码，未知错误返回空字符串
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidDID):
+		return ErrorInvalidDID
+	case errors.Is(err, ErrMethodNotSupported):
+		return ErrorMethodNotSupported
+	case errors.Is(err, ErrNotFound):
+		return ErrorNotFound
+	default:
+		return ""
+	}
+}
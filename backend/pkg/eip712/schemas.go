@@ -0,0 +1,75 @@
+package eip712
+
+import "github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+// Schema 一种签名动作的结构定义，修改字段时应同时提升 Domain.Version
+type Schema struct {
+	PrimaryType string
+	Fields      []apitypes.Type
+}
+
+// LoginSchema 钱包登录，字段与 EIP-4361 消息一一对应
+var LoginSchema = Schema{
+	PrimaryType: "Login",
+	Fields: []apitypes.Type{
+		{Name: "domain", Type: "string"},
+		{Name: "wallet", Type: "address"},
+		{Name: "statement", Type: "string"},
+		{Name: "uri", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "nonce", Type: "string"},
+		{Name: "issuedAt", Type: "string"},
+		{Name: "expirationTime", Type: "string"},
+	},
+}
+
+// LinkWalletSchema 将新钱包绑定到已有账户
+var LinkWalletSchema = Schema{
+	PrimaryType: "LinkWallet",
+	Fields: []apitypes.Type{
+		{Name: "account", Type: "string"},
+		{Name: "wallet", Type: "address"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "nonce", Type: "string"},
+		{Name: "issuedAt", Type: "string"},
+		{Name: "expirationTime", Type: "string"},
+	},
+}
+
+// ConsentSchema 用户授权第三方平台访问数据
+var ConsentSchema = Schema{
+	PrimaryType: "Consent",
+	Fields: []apitypes.Type{
+		{Name: "wallet", Type: "address"},
+		{Name: "platform", Type: "string"},
+		{Name: "scope", Type: "string"},
+		{Name: "nonce", Type: "string"},
+		{Name: "expiresAt", Type: "string"},
+	},
+}
+
+// CheckInIntentSchema 用户签到意图
+var CheckInIntentSchema = Schema{
+	PrimaryType: "CheckInIntent",
+	Fields: []apitypes.Type{
+		{Name: "wallet", Type: "address"},
+		{Name: "day", Type: "string"},
+		{Name: "nonce", Type: "string"},
+	},
+}
+
+// MigrateWalletSchema 将账户迁移到新钱包地址 (mode: HANDOVER 新旧钱包都签名，RECOVERY 仅新钱包签名)
+var MigrateWalletSchema = Schema{
+	PrimaryType: "MigrateWallet",
+	Fields: []apitypes.Type{
+		{Name: "account", Type: "string"},
+		{Name: "fromWallet", Type: "address"},
+		{Name: "fromChainId", Type: "uint256"},
+		{Name: "toWallet", Type: "address"},
+		{Name: "mode", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "nonce", Type: "string"},
+		{Name: "issuedAt", Type: "string"},
+		{Name: "expirationTime", Type: "string"},
+	},
+}
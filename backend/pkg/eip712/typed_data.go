@@ -0,0 +1,92 @@
+package eip712
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Domain EIP-712 domain separator 参数
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           int64
+	VerifyingContract string // 可选
+}
+
+// types 返回 EIP712Domain 的类型定义，字段须与 apitypes.TypedDataDomain.Map() 的非空字段一致
+func (d Domain) types() []apitypes.Type {
+	fields := []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	}
+	if d.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	return fields
+}
+
+func (d Domain) toAPI() apitypes.TypedDataDomain {
+	domain := apitypes.TypedDataDomain{
+		Name:    d.Name,
+		Version: d.Version,
+		ChainId: math.NewHexOrDecimal256(d.ChainID),
+	}
+	if d.VerifyingContract != "" {
+		domain.VerifyingContract = common.HexToAddress(d.VerifyingContract).Hex()
+	}
+	return domain
+}
+
+// NewTypedData 根据 domain 和 schema 构造待签名的 typed data
+func NewTypedData(domain Domain, schema Schema, message apitypes.TypedDataMessage) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain":     domain.types(),
+			schema.PrimaryType: schema.Fields,
+		},
+		PrimaryType: schema.PrimaryType,
+		Domain:      domain.toAPI(),
+		Message:     message,
+	}
+}
+
+// Hash 计算 typed data 的 EIP-712 摘要
+func Hash(td apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(td)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Verify 验证 eth_signTypedData_v4 签名 (支持 EOA 和 EIP-1271 合约钱包)
+func Verify(ctx context.Context, v crypto.SignatureVerifier, td apitypes.TypedData, signature, address string) (bool, error) {
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("invalid address format")
+	}
+
+	sigBytes, err := hexutil.Decode(signature)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature format: %w", err)
+	}
+
+	hash, err := Hash(td)
+	if err != nil {
+		return false, err
+	}
+
+	chainID := (*big.Int)(td.Domain.ChainId)
+	if chainID == nil || !chainID.IsInt64() {
+		return false, fmt.Errorf("invalid domain chain id")
+	}
+
+	return v.VerifyHash(ctx, chainID.Int64(), hash, sigBytes, common.HexToAddress(address))
+}
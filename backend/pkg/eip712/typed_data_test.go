@@ -0,0 +1,102 @@
+package eip712
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dedata/dedata-backend/pkg/crypto"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 规范中的 Mail 示例 (https://eips.ethereum.org/EIPS/eip-712#specification-of-the-eth_signtypeddata-json-rpc)
+const (
+	mailDigest    = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	mailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+	cowAddress    = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+	bobAddress    = "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"
+)
+
+var mailSchema = Schema{
+	PrimaryType: "Mail",
+	Fields: []apitypes.Type{
+		{Name: "from", Type: "Person"},
+		{Name: "to", Type: "Person"},
+		{Name: "contents", Type: "string"},
+	},
+}
+
+// mailTypedData 通过 NewTypedData 构造规范示例，Person 为嵌套类型需要另外加入
+func mailTypedData(chainID int64) apitypes.TypedData {
+	td := NewTypedData(Domain{
+		Name:              "Ether Mail",
+		Version:           "1",
+		ChainID:           chainID,
+		VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+	}, mailSchema, apitypes.TypedDataMessage{
+		"from":     map[string]interface{}{"name": "Cow", "wallet": cowAddress},
+		"to":       map[string]interface{}{"name": "Bob", "wallet": bobAddress},
+		"contents": "Hello, Bob!",
+	})
+	td.Types["Person"] = []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "wallet", Type: "address"},
+	}
+	return td
+}
+
+func TestHashMailExample(t *testing.T) {
+	hash, err := Hash(mailTypedData(1))
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if hash.Hex() != mailDigest {
+		t.Fatalf("Hash = %s, want %s", hash.Hex(), mailDigest)
+	}
+}
+
+func TestVerifyMailExample(t *testing.T) {
+	// 规范示例的签名私钥为 keccak256("cow")
+	key, err := ethcrypto.ToECDSA(ethcrypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatalf("ToECDSA: %v", err)
+	}
+	if cow := ethcrypto.PubkeyToAddress(key.PublicKey); cow != common.HexToAddress(cowAddress) {
+		t.Fatalf("cow address = %s, want %s", cow.Hex(), cowAddress)
+	}
+
+	tests := []struct {
+		name    string
+		td      apitypes.TypedData
+		address string
+		want    bool
+	}{
+		{"signer", mailTypedData(1), cowAddress, true},
+		{"other address", mailTypedData(1), bobAddress, false},
+		{"other chain", mailTypedData(137), cowAddress, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := Verify(context.Background(), crypto.NewECDSAVerifier(), tt.td, mailSignature, tt.address)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if valid != tt.want {
+				t.Fatalf("Verify = %v, want %v", valid, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsMalformedInput(t *testing.T) {
+	ctx := context.Background()
+	v := crypto.NewECDSAVerifier()
+
+	if _, err := Verify(ctx, v, mailTypedData(1), mailSignature, "cow"); err == nil {
+		t.Fatal("Verify accepted an invalid address")
+	}
+	if _, err := Verify(ctx, v, mailTypedData(1), "not-hex", cowAddress); err == nil {
+		t.Fatal("Verify accepted a non-hex signature")
+	}
+}
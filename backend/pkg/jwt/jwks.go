@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// JWK RFC 7517 JSON Web Key (仅包含公钥参数)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK 导出公钥
+func (k *Key) JWK() JWK {
+	jwk := JWK{
+		Kid: k.ID,
+		Alg: k.Method.Alg(),
+		Use: "sig",
+	}
+
+	switch pub := k.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		// 非压缩编码: 0x04 || X || Y
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return jwk
+		}
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
@@ -2,70 +2,229 @@ package jwt
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dedata/dedata-backend/config"
+	"github.com/dedata/dedata-backend/pkg/crypto"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // Claims JWT 声明
 type Claims struct {
-	UserID  string `json:"userId"`
-	Address string `json:"address"`
-	DID     string `json:"did"`
-	Role    string `json:"role"`
+	UserID    string `json:"userId"`
+	Address   string `json:"address"`
+	DID       string `json:"did"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`             // 登录会话 ID (同一 refresh token family 共享)
+	Scope     string `json:"scope,omitempty"` // 会话权限范围: full (钱包签名登录) | read (passkey 登录)，缺省视为 full
 	jwt.RegisteredClaims
 }
 
 type JWTManager struct {
 	secret     string
-	expireHour int
+	issuer     string
+	signingKey *Key            // 为 nil 时回退到 HS256
+	verifyKeys map[string]*Key // kid -> key
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
 // NewJWTManager 创建 JWT 管理器
-func NewJWTManager(cfg *config.JWTConfig) *JWTManager {
-	return &JWTManager{
+// 配置了 keys 时使用非对称签名 (ES256 / EdDSA)，否则回退到 HS256 共享密钥
+func NewJWTManager(cfg *config.JWTConfig) (*JWTManager, error) {
+	accessTTL := time.Duration(cfg.AccessExpireMinute) * time.Minute
+	if accessTTL == 0 {
+		accessTTL = 15 * time.Minute // 默认15分钟
+	}
+	refreshTTL := time.Duration(cfg.RefreshExpireHour) * time.Hour
+	if refreshTTL == 0 {
+		refreshTTL = 30 * 24 * time.Hour // 默认30天
+	}
+
+	m := &JWTManager{
 		secret:     cfg.Secret,
-		expireHour: cfg.ExpireHour,
+		issuer:     cfg.Issuer,
+		verifyKeys: make(map[string]*Key),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+
+	for _, kc := range cfg.Keys {
+		key, err := LoadKey(kc.ID, kc.PrivateKeyFile, kc.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := m.verifyKeys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id: %s", key.ID)
+		}
+		m.verifyKeys[key.ID] = key
+	}
+
+	if len(m.verifyKeys) > 0 {
+		key, ok := m.verifyKeys[cfg.SigningKeyID]
+		if !ok {
+			return nil, fmt.Errorf("signing key %q not found in jwt keys", cfg.SigningKeyID)
+		}
+		if !key.CanSign() {
+			return nil, fmt.Errorf("signing key %q has no private key", cfg.SigningKeyID)
+		}
+		m.signingKey = key
+	} else if m.secret == "" {
+		return nil, fmt.Errorf("jwt secret or keys must be configured")
 	}
+
+	return m, nil
+}
+
+// AccessTTL access token 有效期
+func (m *JWTManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// RefreshTTL refresh token 有效期
+func (m *JWTManager) RefreshTTL() time.Duration {
+	return m.refreshTTL
 }
 
-// GenerateToken 生成 JWT token
-func (m *JWTManager) GenerateToken(userID, address, did, role string) (string, error) {
+// GenerateToken 生成 access token，每个 token 带唯一 jti 以便吊销
+func (m *JWTManager) GenerateToken(userID, address, did, role, sessionID, scope string) (string, error) {
+	jti, err := crypto.RandomHex(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate jti: %w", err)
+	}
+
 	now := time.Now()
 	claims := &Claims{
-		UserID:  userID,
-		Address: address,
-		DID:     did,
-		Role:    role,
+		UserID:    userID,
+		Address:   address,
+		DID:       did,
+		Role:      role,
+		SessionID: sessionID,
+		Scope:     scope,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour * time.Duration(m.expireHour))),
+			ID:        jti,
+			Issuer:    m.issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secret))
+	return m.SignToken(claims)
+}
+
+// Issuer token 签发者 (iss)
+func (m *JWTManager) Issuer() string {
+	return m.issuer
+}
+
+// HasSigningKey 是否配置了非对称签发密钥 (ES256 / EdDSA)，第三方只能通过 JWKS 验证这类签名
+func (m *JWTManager) HasSigningKey() bool {
+	return m.signingKey != nil
+}
+
+// SigningAlg 当前签发算法，HS256 模式下为 "HS256"
+func (m *JWTManager) SigningAlg() string {
+	if m.signingKey == nil {
+		return jwt.SigningMethodHS256.Alg()
+	}
+	return m.signingKey.Method.Alg()
+}
+
+// SignToken 使用当前签发密钥签名任意声明，头部 kid 与 JWKS 一致 (例如 OpenID Connect ID token)
+func (m *JWTManager) SignToken(claims jwt.Claims) (string, error) {
+	if m.signingKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.secret))
+	}
+
+	token := jwt.NewWithClaims(m.signingKey.Method, claims)
+	token.Header["kid"] = m.signingKey.ID
+	return token.SignedString(m.signingKey.PrivateKey)
 }
 
 // ValidateToken 验证 JWT token
 func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名算法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(m.secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.keyFunc)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// access token 不带 aud，带 aud 的是签发给第三方应用的 ID token，不能用于调用 API
+		if len(claims.Audience) > 0 {
+			return nil, fmt.Errorf("unexpected audience")
+		}
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// keyFunc 按 token 头部的 kid 选择验证公钥
+// 配置了非对称密钥后不再接受 HS256 token
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	if m.signingKey == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(m.secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.verifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PublicKey, nil
+}
+
+// SignWithKeyID 使用当前签发密钥签名任意声明，头部 kid 为 keyIDPrefix + 密钥 ID
+// 用于 Verifiable Credential 等以 DID URL 作为 kid 的令牌，HS256 模式下不可用
+func (m *JWTManager) SignWithKeyID(claims jwt.Claims, keyIDPrefix string) (string, error) {
+	if m.signingKey == nil {
+		return "", fmt.Errorf("an asymmetric signing key is required")
+	}
+
+	token := jwt.NewWithClaims(m.signingKey.Method, claims)
+	token.Header["kid"] = keyIDPrefix + m.signingKey.ID
+	return token.SignedString(m.signingKey.PrivateKey)
+}
+
+// ParseWithKeyID 校验 SignWithKeyID 签发的令牌，kid 必须带 keyIDPrefix，因此 access token 不会被接受
+func (m *JWTManager) ParseWithKeyID(tokenString string, claims jwt.Claims, keyIDPrefix string) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if !strings.HasPrefix(kid, keyIDPrefix) {
+			return nil, fmt.Errorf("unknown key id: %q", kid)
+		}
+		key, ok := m.verifyKeys[strings.TrimPrefix(kid, keyIDPrefix)]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.PublicKey, nil
+	})
+	return err
+}
+
+// JWKS 返回所有验证公钥，供第三方服务独立验证 token
+// HS256 模式下返回空集合
+func (m *JWTManager) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(m.verifyKeys))}
+	for _, key := range m.verifyKeys {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+	return jwks
+}
@@ -0,0 +1,189 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dedata/dedata-backend/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKey 生成私钥并写入 PEM 文件，返回私钥与公钥文件路径
+func writeKey(t *testing.T, ed bool) (string, string) {
+	t.Helper()
+	var signer crypto.Signer
+	var err error
+	if ed {
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privPath, pubPath := filepath.Join(dir, "private.pem"), filepath.Join(dir, "public.pem")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privPath, pubPath
+}
+
+func newManager(t *testing.T, cfg *config.JWTConfig) *JWTManager {
+	t.Helper()
+	m, err := NewJWTManager(cfg)
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+	return m
+}
+
+func mustKey(t *testing.T, privateKeyFile string) *Key {
+	t.Helper()
+	key, err := LoadKey("key", privateKeyFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func generate(t *testing.T, m *JWTManager) string {
+	t.Helper()
+	token, err := m.GenerateToken("user-1", "0xabc", "did:pkh:eip155:1:0xabc", "user", "session-1", "full")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+// sign 直接使用 golang-jwt 签名，header 为附加的头部字段
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, header map[string]interface{}, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	for k, v := range header {
+		token.Header[k] = v
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return s
+}
+
+func TestValidateToken(t *testing.T) {
+	k1Priv, k1Pub := writeKey(t, false)
+	k2Priv, _ := writeKey(t, true)
+	k3Priv, _ := writeKey(t, false)
+
+	// 轮换前只有 k1；轮换后 k2 签发，k1 只保留公钥用于验证旧 token
+	before := newManager(t, &config.JWTConfig{
+		SigningKeyID: "k1",
+		Keys:         []config.JWTKeyConfig{{ID: "k1", PrivateKeyFile: k1Priv}},
+	})
+	after := newManager(t, &config.JWTConfig{
+		SigningKeyID: "k2",
+		Keys: []config.JWTKeyConfig{
+			{ID: "k1", PublicKeyFile: k1Pub},
+			{ID: "k2", PrivateKeyFile: k2Priv},
+		},
+	})
+	// unknown 的 kid 不在 after 中；forged 使用 after 中的 kid，但密钥不同
+	unknown := newManager(t, &config.JWTConfig{
+		SigningKeyID: "k3",
+		Keys:         []config.JWTKeyConfig{{ID: "k3", PrivateKeyFile: k3Priv}},
+	})
+	forged := newManager(t, &config.JWTConfig{
+		SigningKeyID: "k2",
+		Keys:         []config.JWTKeyConfig{{ID: "k2", PrivateKeyFile: k3Priv}},
+	})
+	hs256 := newManager(t, &config.JWTConfig{Secret: "shared-secret"})
+
+	k1Key, k2Key := mustKey(t, k1Priv), mustKey(t, k2Priv)
+	k1PubPEM, err := os.ReadFile(k1Pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := func() *Claims {
+		now := time.Now()
+		return &Claims{
+			UserID: "user-1",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		}
+	}
+	withAudience := claims()
+	withAudience.Audience = jwt.ClaimStrings{"client-1"}
+
+	tests := []struct {
+		name    string
+		m       *JWTManager
+		token   string
+		wantErr bool
+	}{
+		{"current key", after, generate(t, after), false},
+		{"rotated key kept for verification", after, generate(t, before), false},
+		{"unknown kid", after, generate(t, unknown), true},
+		{"known kid signed by another key", after, generate(t, forged), true},
+		{"missing kid", after, sign(t, jwt.SigningMethodES256, k1Key.PrivateKey, nil, claims()), true},
+		{"kid of a key with another algorithm", after, sign(t, jwt.SigningMethodES256, k1Key.PrivateKey, map[string]interface{}{"kid": "k2"}, claims()), true},
+		// alg confusion：以公钥 PEM 作为 HMAC 密钥签名
+		{"HS256 with public key as secret", after, sign(t, jwt.SigningMethodHS256, k1PubPEM, map[string]interface{}{"kid": "k1"}, claims()), true},
+		{"HS256 token after switching to asymmetric keys", after, generate(t, hs256), true},
+		{"HS256 mode", hs256, generate(t, hs256), false},
+		{"asymmetric token in HS256 mode", hs256, generate(t, after), true},
+		{"token with aud", after, sign(t, jwt.SigningMethodEdDSA, k2Key.PrivateKey, map[string]interface{}{"kid": "k2"}, withAudience), true},
+		{"signed claims without aud", after, sign(t, jwt.SigningMethodEdDSA, k2Key.PrivateKey, map[string]interface{}{"kid": "k2"}, claims()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.m.ValidateToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateTokenSetsKeyID(t *testing.T) {
+	priv, _ := writeKey(t, true)
+	m := newManager(t, &config.JWTConfig{
+		SigningKeyID: "k1",
+		Keys:         []config.JWTKeyConfig{{ID: "k1", PrivateKeyFile: priv}},
+	})
+
+	token, _, err := jwt.NewParser().ParseUnverified(generate(t, m), &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Header["kid"] != "k1" || token.Method.Alg() != "EdDSA" {
+		t.Errorf("header = %v, want kid k1 and alg EdDSA", token.Header)
+	}
+	if jwks := m.JWKS(); len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "k1" {
+		t.Errorf("JWKS = %+v, want the k1 key", jwks)
+	}
+}
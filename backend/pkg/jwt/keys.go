@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key 非对称签名密钥
+// 仅有公钥的 Key 用于轮换期间继续验证旧 token
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer // 可为 nil
+	PublicKey  crypto.PublicKey
+}
+
+// CanSign 是否持有私钥
+func (k *Key) CanSign() bool {
+	return k.PrivateKey != nil
+}
+
+// LoadKey 从 PEM 文件加载密钥
+// privateKeyFile 与 publicKeyFile 至少提供一个；提供私钥时公钥由私钥推导
+func LoadKey(id, privateKeyFile, publicKeyFile string) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+
+	if privateKeyFile != "" {
+		data, err := os.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key %s: %w", id, err)
+		}
+		return ParsePrivateKeyPEM(id, data)
+	}
+
+	if publicKeyFile != "" {
+		data, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key %s: %w", id, err)
+		}
+		return ParsePublicKeyPEM(id, data)
+	}
+
+	return nil, fmt.Errorf("key %s: private_key_file or public_key_file is required", id)
+}
+
+// ParsePrivateKeyPEM 解析 PKCS#8 / SEC 1 格式的 P-256 或 Ed25519 私钥
+func ParsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: invalid PEM data", id)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: failed to parse private key: %w", id, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key %s: unsupported private key type %T", id, parsed)
+	}
+
+	key, err := newKey(id, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.PrivateKey = signer
+	return key, nil
+}
+
+// ParsePublicKeyPEM 解析 PKIX 格式的 P-256 或 Ed25519 公钥
+func ParsePublicKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: invalid PEM data", id)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: failed to parse public key: %w", id, err)
+	}
+
+	return newKey(id, parsed)
+}
+
+// newKey 根据公钥类型确定签名算法
+func newKey(id string, pub crypto.PublicKey) (*Key, error) {
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("key %s: only P-256 curve is supported for ECDSA", id)
+		}
+		return &Key{ID: id, Method: jwt.SigningMethodES256, PublicKey: k}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported public key type %T", id, pub)
+	}
+}
@@ -0,0 +1,53 @@
+// Package pow 实现 hashcash 风格的工作量证明：
+// 客户端寻找 solution，使 SHA-256(seed ":" solution) 的前 difficulty 位均为 0
+package pow
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+)
+
+// Algorithm 返回给客户端的算法标识
+const Algorithm = "sha256"
+
+// MaxSolutionLength solution 的最大长度，避免对超长输入做哈希
+const MaxSolutionLength = 64
+
+// Verify 检查 solution 是否满足难度要求，difficulty <= 0 时总是通过
+func Verify(seed string, difficulty int, solution string) bool {
+	if difficulty <= 0 {
+		return true
+	}
+	if solution == "" || len(solution) > MaxSolutionLength {
+		return false
+	}
+	return LeadingZeroBits(hash(seed, solution)) >= difficulty
+}
+
+// Solve 以十进制计数器作为 solution 暴力求解，供客户端和调试使用
+func Solve(seed string, difficulty int) string {
+	for counter := uint64(0); ; counter++ {
+		solution := strconv.FormatUint(counter, 10)
+		if Verify(seed, difficulty, solution) {
+			return solution
+		}
+	}
+}
+
+// LeadingZeroBits 返回摘要开头连续 0 的位数
+func LeadingZeroBits(digest []byte) int {
+	n := 0
+	for _, b := range digest {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+func hash(seed, solution string) []byte {
+	sum := sha256.Sum256([]byte(seed + ":" + solution))
+	return sum[:]
+}
@@ -0,0 +1,96 @@
+package pow
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLeadingZeroBits(t *testing.T) {
+	cases := []struct {
+		digest []byte
+		want   int
+	}{
+		{[]byte{0xff}, 0},
+		{[]byte{0x80, 0x00}, 0},
+		{[]byte{0x01}, 7},
+		{[]byte{0x00, 0x80}, 8},
+		{[]byte{0x00, 0x0f}, 12},
+		{[]byte{0x00, 0x00, 0x01}, 23},
+		{[]byte{0x00, 0x00}, 16},
+		{nil, 0},
+	}
+	for _, c := range cases {
+		if got := LeadingZeroBits(c.digest); got != c.want {
+			t.Errorf("LeadingZeroBits(%x) = %d, want %d", c.digest, got, c.want)
+		}
+	}
+}
+
+func TestVerifyZeroDifficultyAlwaysPasses(t *testing.T) {
+	// difficulty <= 0 表示未启用工作量证明，空 solution 也通过
+	for _, solution := range []string{"", "anything", strings.Repeat("x", MaxSolutionLength+1)} {
+		if !Verify("seed", 0, solution) {
+			t.Errorf("Verify(difficulty=0, %q) = false, want true", solution)
+		}
+		if !Verify("seed", -1, solution) {
+			t.Errorf("Verify(difficulty=-1, %q) = false, want true", solution)
+		}
+	}
+}
+
+func TestSolveOutputVerifies(t *testing.T) {
+	// 8 位对齐到字节边界，5 和 13 不是 8 的倍数，需要按位比较
+	for _, difficulty := range []int{1, 5, 8, 13} {
+		seed := "dedata-test-seed"
+		solution := Solve(seed, difficulty)
+		if !Verify(seed, difficulty, solution) {
+			t.Fatalf("Verify(Solve(difficulty=%d)) = false", difficulty)
+		}
+		if got := LeadingZeroBits(hash(seed, solution)); got < difficulty {
+			t.Errorf("difficulty %d: solution %q has %d leading zero bits", difficulty, solution, got)
+		}
+	}
+}
+
+func TestVerifyRejectsInsufficientWork(t *testing.T) {
+	seed := "dedata-test-seed"
+	solution := Solve(seed, 8)
+	zeros := LeadingZeroBits(hash(seed, solution))
+
+	// 满足自身的零位数，但不满足更高的难度
+	if !Verify(seed, zeros, solution) {
+		t.Errorf("Verify(difficulty=%d) = false, want true", zeros)
+	}
+	if Verify(seed, zeros+1, solution) {
+		t.Errorf("Verify(difficulty=%d) = true, want false", zeros+1)
+	}
+}
+
+func TestVerifyRejectsEmptyAndOversizedSolutions(t *testing.T) {
+	if Verify("seed", 1, "") {
+		t.Error("Verify accepted an empty solution")
+	}
+
+	// 长度上限检查先于哈希：即使哈希满足难度，超长 solution 也会被拒绝
+	if !Verify("seed", 1, findSolution(t, MaxSolutionLength)) {
+		t.Errorf("Verify rejected a %d-byte solution", MaxSolutionLength)
+	}
+	if long := findSolution(t, MaxSolutionLength+1); Verify("seed", 1, long) {
+		t.Errorf("Verify accepted a %d-byte solution", len(long))
+	}
+}
+
+// findSolution 寻找长度为 length 且哈希满足 1 位难度的 solution
+func findSolution(t *testing.T, length int) string {
+	t.Helper()
+	prefix := strings.Repeat("a", length-4)
+	for counter := 0; counter < 10000; counter++ {
+		solution := prefix + fmt.Sprintf("%04d", counter)
+		if LeadingZeroBits(hash("seed", solution)) >= 1 {
+			return solution
+		}
+	}
+	t.Fatalf("no %d-byte solution found", length)
+	return ""
+}
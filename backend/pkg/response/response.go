@@ -72,6 +72,15 @@ func Forbidden(c *gin.Context, message string) {
 	})
 }
 
+// ForbiddenWithData 403 错误(带数据)，例如提示客户端需要先完成二次验证
+func ForbiddenWithData(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusForbidden, Response{
+		Code:    http.StatusForbidden,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // NotFound 404 错误
 func NotFound(c *gin.Context, message string) {
 	c.JSON(http.StatusNotFound, Response{
@@ -80,6 +89,39 @@ func NotFound(c *gin.Context, message string) {
 	})
 }
 
+// Conflict 409 错误
+func Conflict(c *gin.Context, message string) {
+	c.JSON(http.StatusConflict, Response{
+		Code:    http.StatusConflict,
+		Message: message,
+	})
+}
+
+// ConflictWithData 409 错误(带数据)，例如提示客户端今日签到次数已用完
+func ConflictWithData(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusConflict, Response{
+		Code:    http.StatusConflict,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// UnprocessableEntity 422 错误
+func UnprocessableEntity(c *gin.Context, message string) {
+	c.JSON(http.StatusUnprocessableEntity, Response{
+		Code:    http.StatusUnprocessableEntity,
+		Message: message,
+	})
+}
+
+// TooManyRequests 429 错误
+func TooManyRequests(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code:    http.StatusTooManyRequests,
+		Message: message,
+	})
+}
+
 // PaymentRequired 402 错误
 func PaymentRequired(c *gin.Context, message string, data interface{}) {
 	c.JSON(http.StatusPaymentRequired, Response{
@@ -0,0 +1,81 @@
+// Package reward 以十进制字符串计算 token 奖励数量，避免浮点误差
+// 数量单位为 token (非最小单位)，最多保留 Decimals 位小数
+package reward
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimals 奖励 token 的精度
+const Decimals = 18
+
+// Calculate 返回 base * multiplier + bonus，multiplier 为空视为 "1"，bonus 为空视为 "0"
+// 结果超过 Decimals 位的小数部分被截断，结果为负时返回错误
+func Calculate(base, multiplier, bonus string) (string, error) {
+	if multiplier == "" {
+		multiplier = "1"
+	}
+	if bonus == "" {
+		bonus = "0"
+	}
+
+	b, err := parse(base)
+	if err != nil {
+		return "", err
+	}
+	m, err := parse(multiplier)
+	if err != nil {
+		return "", err
+	}
+	x, err := parse(bonus)
+	if err != nil {
+		return "", err
+	}
+
+	amount := new(big.Rat).Mul(b, m)
+	amount.Add(amount, x)
+	if amount.Sign() < 0 {
+		return "", fmt.Errorf("negative reward amount: %s", amount.FloatString(Decimals))
+	}
+	return Format(ToBaseUnits(amount)), nil
+}
+
+// ParseBaseUnits 将 token 数量 (例如 "12.5") 转换为最小单位，小数位超过 Decimals 时返回错误
+func ParseBaseUnits(amount string) (*big.Int, error) {
+	r, err := parse(amount)
+	if err != nil {
+		return nil, err
+	}
+	units := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale()))
+	if !units.IsInt() {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", amount, Decimals)
+	}
+	return units.Num(), nil
+}
+
+// ToBaseUnits 将 token 数量转换为最小单位，多余的小数位被截断
+func ToBaseUnits(amount *big.Rat) *big.Int {
+	units := new(big.Rat).Mul(amount, new(big.Rat).SetInt(scale()))
+	return new(big.Int).Quo(units.Num(), units.Denom())
+}
+
+// Format 将最小单位格式化为 token 数量，去掉小数部分末尾的 0
+func Format(units *big.Int) string {
+	s := new(big.Rat).SetFrac(units, scale()).FloatString(Decimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func parse(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return nil, fmt.Errorf("invalid decimal amount: %q", s)
+	}
+	return r, nil
+}
+
+func scale() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(Decimals), nil)
+}
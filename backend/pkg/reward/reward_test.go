@@ -0,0 +1,60 @@
+package reward
+
+import "testing"
+
+func TestCalculate(t *testing.T) {
+	cases := []struct {
+		base, multiplier, bonus string
+		want                    string
+	}{
+		{"10", "", "", "10"},
+		{"10", "1.5", "", "15"},
+		{"10", "2", "50", "70"},
+		{"10.5", "1", "0.25", "10.75"},
+		{"0.000000000000000001", "0.5", "", "0"}, // 超过 18 位的小数被截断
+		{"1", "0", "0", "0"},
+		{" 3 ", " 2 ", " 1 ", "7"},
+	}
+	for _, c := range cases {
+		got, err := Calculate(c.base, c.multiplier, c.bonus)
+		if err != nil {
+			t.Errorf("Calculate(%q, %q, %q) error: %v", c.base, c.multiplier, c.bonus, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Calculate(%q, %q, %q) = %s, want %s", c.base, c.multiplier, c.bonus, got, c.want)
+		}
+	}
+}
+
+func TestCalculateRejectsInvalidAmounts(t *testing.T) {
+	cases := [][3]string{
+		{"abc", "1", "0"},
+		{"10", "1/2", "0"},
+		{"10", "1e2", "0"},
+		{"10", "1", "-11"}, // 结果为负
+		{"", "1", "0"},
+	}
+	for _, c := range cases {
+		if got, err := Calculate(c[0], c[1], c[2]); err == nil {
+			t.Errorf("Calculate(%q, %q, %q) = %s, want error", c[0], c[1], c[2], got)
+		}
+	}
+}
+
+func TestParseBaseUnits(t *testing.T) {
+	units, err := ParseBaseUnits("12.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := units.String(); got != "12500000000000000000" {
+		t.Errorf("ParseBaseUnits(12.5) = %s", got)
+	}
+	if got := Format(units); got != "12.5" {
+		t.Errorf("Format = %s, want 12.5", got)
+	}
+
+	if _, err := ParseBaseUnits("0.0000000000000000001"); err == nil {
+		t.Error("ParseBaseUnits accepted more than 18 decimal places")
+	}
+}
@@ -0,0 +1,277 @@
+package siwe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EIP-4361 (Sign-In with Ethereum) 消息格式，按 CAIP-122 推广到其他链 (例如 Solana):
+//
+//	${domain} wants you to sign in with your ${blockchain} account:
+//	${address}
+//
+//	${statement}
+//
+//	URI: ${uri}
+//	Version: ${version}
+//	Chain ID: ${chain-id}
+//	Nonce: ${nonce}
+//	Issued At: ${issued-at}
+//	Expiration Time: ${expiration-time}
+//	Not Before: ${not-before}
+//	Request ID: ${request-id}
+//	Resources:
+//	- ${resources[0]}
+
+const (
+	// Version 当前支持的 SIWE 消息版本
+	Version = "1"
+
+	// Ethereum EIP-4361 的链名称，Blockchain 为空时使用
+	Ethereum = "Ethereum"
+
+	headerInfix  = " wants you to sign in with your "
+	headerSuffix = " account:"
+
+	tagURI            = "URI: "
+	tagVersion        = "Version: "
+	tagChainID        = "Chain ID: "
+	tagNonce          = "Nonce: "
+	tagIssuedAt       = "Issued At: "
+	tagExpirationTime = "Expiration Time: "
+	tagNotBefore      = "Not Before: "
+	tagRequestID      = "Request ID: "
+	tagResources      = "Resources:"
+)
+
+// Message SIWE 消息
+type Message struct {
+	Domain         string
+	Blockchain     string // 头部中的链名称，默认 Ethereum
+	Address        string // Ethereum 为 EIP-55 checksum 地址，其他链由调用方校验
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        string // CAIP-2 reference，Ethereum 为十进制链 ID
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// String 按 EIP-4361 格式输出待签名消息
+func (m *Message) String() string {
+	var b strings.Builder
+
+	b.WriteString(m.Domain + headerInfix + m.blockchain() + headerSuffix + "\n")
+	b.WriteString(m.Address + "\n")
+	b.WriteString("\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(tagURI + m.URI + "\n")
+	b.WriteString(tagVersion + m.Version + "\n")
+	b.WriteString(tagChainID + m.ChainID + "\n")
+	b.WriteString(tagNonce + m.Nonce + "\n")
+	b.WriteString(tagIssuedAt + formatTime(m.IssuedAt))
+	if m.ExpirationTime != nil {
+		b.WriteString("\n" + tagExpirationTime + formatTime(*m.ExpirationTime))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\n" + tagNotBefore + formatTime(*m.NotBefore))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\n" + tagRequestID + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\n" + tagResources)
+		for _, r := range m.Resources {
+			b.WriteString("\n- " + r)
+		}
+	}
+
+	return b.String()
+}
+
+// Parse 解析 EIP-4361 消息
+func Parse(raw string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 8 {
+		return nil, fmt.Errorf("siwe: message too short")
+	}
+
+	m := &Message{}
+
+	// 1. 头部: domain + 链名称 + 地址
+	header := strings.TrimSuffix(lines[0], headerSuffix)
+	sep := strings.Index(header, headerInfix)
+	if header == lines[0] || sep < 0 {
+		return nil, fmt.Errorf("siwe: invalid message header")
+	}
+	m.Domain = header[:sep]
+	if m.Domain == "" {
+		return nil, fmt.Errorf("siwe: missing domain")
+	}
+	m.Blockchain = header[sep+len(headerInfix):]
+	if m.Blockchain == "" || strings.Contains(m.Blockchain, " ") {
+		return nil, fmt.Errorf("siwe: invalid blockchain name")
+	}
+
+	m.Address = lines[1]
+	if m.Blockchain == Ethereum {
+		if !common.IsHexAddress(m.Address) || common.HexToAddress(m.Address).Hex() != m.Address {
+			return nil, fmt.Errorf("siwe: address must be an EIP-55 checksum address")
+		}
+	} else if m.Address == "" {
+		return nil, fmt.Errorf("siwe: missing address")
+	}
+
+	if lines[2] != "" {
+		return nil, fmt.Errorf("siwe: expected empty line after address")
+	}
+
+	// 2. 可选的 statement
+	i := 3
+	if !strings.HasPrefix(lines[i], tagURI) {
+		if lines[i] != "" {
+			m.Statement = lines[i]
+			i++
+		}
+		if i >= len(lines) || lines[i] != "" {
+			return nil, fmt.Errorf("siwe: expected empty line after statement")
+		}
+		i++
+	}
+
+	// 3. 必填字段 (顺序固定)
+	next := func(tag string) (string, error) {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], tag) {
+			return "", fmt.Errorf("siwe: missing %q", strings.TrimSpace(strings.TrimSuffix(tag, ": ")))
+		}
+		v := strings.TrimPrefix(lines[i], tag)
+		i++
+		return v, nil
+	}
+	optional := func(tag string) (string, bool) {
+		if i < len(lines) && strings.HasPrefix(lines[i], tag) {
+			v := strings.TrimPrefix(lines[i], tag)
+			i++
+			return v, true
+		}
+		return "", false
+	}
+
+	var err error
+	if m.URI, err = next(tagURI); err != nil {
+		return nil, err
+	}
+	if m.Version, err = next(tagVersion); err != nil {
+		return nil, err
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("siwe: unsupported version %q", m.Version)
+	}
+
+	if m.ChainID, err = next(tagChainID); err != nil {
+		return nil, err
+	}
+	if !isAlphanumeric(m.ChainID) || m.ChainID == "" {
+		return nil, fmt.Errorf("siwe: invalid chain id %q", m.ChainID)
+	}
+	if m.Blockchain == Ethereum {
+		if id, err := strconv.ParseInt(m.ChainID, 10, 64); err != nil || id <= 0 {
+			return nil, fmt.Errorf("siwe: invalid chain id %q", m.ChainID)
+		}
+	}
+
+	if m.Nonce, err = next(tagNonce); err != nil {
+		return nil, err
+	}
+	if !isAlphanumeric(m.Nonce) || len(m.Nonce) < 8 {
+		return nil, fmt.Errorf("siwe: invalid nonce")
+	}
+
+	issuedAt, err := next(tagIssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if m.IssuedAt, err = parseTime(issuedAt); err != nil {
+		return nil, fmt.Errorf("siwe: invalid issued at: %w", err)
+	}
+
+	// 4. 可选字段
+	if v, ok := optional(tagExpirationTime); ok {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("siwe: invalid expiration time: %w", err)
+		}
+		m.ExpirationTime = &t
+	}
+	if v, ok := optional(tagNotBefore); ok {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("siwe: invalid not before: %w", err)
+		}
+		m.NotBefore = &t
+	}
+	if v, ok := optional(tagRequestID); ok {
+		m.RequestID = v
+	}
+	if i < len(lines) && lines[i] == tagResources {
+		i++
+		for i < len(lines) && strings.HasPrefix(lines[i], "- ") {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], "- "))
+			i++
+		}
+	}
+
+	if i != len(lines) {
+		return nil, fmt.Errorf("siwe: unexpected content at line %d", i+1)
+	}
+
+	return m, nil
+}
+
+// ValidAt 检查消息在给定时间是否处于有效期内
+func (m *Message) ValidAt(t time.Time) error {
+	if m.ExpirationTime != nil && !t.Before(*m.ExpirationTime) {
+		return fmt.Errorf("siwe: message has expired")
+	}
+	if m.NotBefore != nil && t.Before(*m.NotBefore) {
+		return fmt.Errorf("siwe: message is not yet valid")
+	}
+	return nil
+}
+
+func (m *Message) blockchain() string {
+	if m.Blockchain == "" {
+		return Ethereum
+	}
+	return m.Blockchain
+}
+
+// formatTime 统一使用 UTC 秒级 RFC3339 格式
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package siwe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func fullMessage() *Message {
+	issuedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expiresAt := issuedAt.Add(5 * time.Minute)
+	notBefore := issuedAt.Add(-time.Minute)
+	return &Message{
+		Domain:         "dedata.io",
+		Blockchain:     Ethereum,
+		Address:        testAddress,
+		Statement:      "Sign in to DeData",
+		URI:            "https://dedata.io/login",
+		Version:        Version,
+		ChainID:        "137",
+		Nonce:          "a1B2c3D4e5F6",
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expiresAt,
+		NotBefore:      &notBefore,
+		RequestID:      "req-42",
+		Resources:      []string{"https://dedata.io/terms", "ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq"},
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	want := fullMessage()
+
+	got, err := Parse(want.String())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse(String()) = %+v, want %+v", got, want)
+	}
+	if got.String() != want.String() {
+		t.Fatalf("String() changed after round trip:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+}
+
+func TestSolanaMessageRoundTrip(t *testing.T) {
+	want := fullMessage()
+	want.Blockchain = "Solana"
+	want.Address = "7S3P4HxJpyyigGzodYwHtCxZyUQe9JiBMHyRWXArAaKv"
+	want.ChainID = "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp"
+
+	raw := want.String()
+	if !strings.HasPrefix(raw, "dedata.io wants you to sign in with your Solana account:\n") {
+		t.Fatalf("unexpected header:\n%s", raw)
+	}
+
+	got, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse(String()) = %+v, want %+v", got, want)
+	}
+}
+
+func TestMessageWithoutOptionalFields(t *testing.T) {
+	raw := "dedata.io wants you to sign in with your Ethereum account:\n" +
+		testAddress + "\n" +
+		"\n" +
+		"\n" +
+		"URI: https://dedata.io\n" +
+		"Version: 1\n" +
+		"Chain ID: 1\n" +
+		"Nonce: abcdefgh\n" +
+		"Issued At: 2026-01-02T03:04:05Z"
+
+	m, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Statement != "" || m.ExpirationTime != nil || m.NotBefore != nil || m.RequestID != "" || m.Resources != nil {
+		t.Fatalf("optional fields should be empty: %+v", m)
+	}
+	if m.String() != raw {
+		t.Fatalf("String() = %q, want %q", m.String(), raw)
+	}
+}
+
+func TestParseRejectsMalformedMessages(t *testing.T) {
+	valid := fullMessage().String()
+
+	tests := []struct {
+		name    string
+		replace [2]string
+	}{
+		{"lowercase address", [2]string{testAddress, strings.ToLower(testAddress)}},
+		{"short address", [2]string{testAddress, testAddress[:40]}},
+		{"not hex address", [2]string{testAddress, "0xZZAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}},
+		{"wrong header", [2]string{"sign in with your Ethereum account:", "sign in:"}},
+		{"missing blockchain", [2]string{"your Ethereum account:", "your  account:"}},
+		{"unsupported version", [2]string{"Version: 1", "Version: 2"}},
+		{"invalid chain id", [2]string{"Chain ID: 137", "Chain ID: polygon"}},
+		{"short nonce", [2]string{"Nonce: a1B2c3D4e5F6", "Nonce: abc"}},
+		{"non-alphanumeric nonce", [2]string{"Nonce: a1B2c3D4e5F6", "Nonce: a1B2-c3D4e5F6"}},
+		{"invalid issued at", [2]string{"Issued At: 2026-01-02T03:04:05Z", "Issued At: yesterday"}},
+		{"missing uri", [2]string{"URI: https://dedata.io/login\n", ""}},
+		{"trailing content", [2]string{"Request ID: req-42", "Request ID: req-42\nextra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := strings.Replace(valid, tt.replace[0], tt.replace[1], 1)
+			if raw == valid {
+				t.Fatalf("replacement %q not found", tt.replace[0])
+			}
+			if _, err := Parse(raw); err == nil {
+				t.Fatalf("Parse accepted malformed message:\n%s", raw)
+			}
+		})
+	}
+}
+
+func TestValidAt(t *testing.T) {
+	m := fullMessage()
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantErr bool
+	}{
+		{"within validity", m.IssuedAt, false},
+		{"before not before", m.NotBefore.Add(-time.Second), true},
+		{"at expiration", *m.ExpirationTime, true},
+		{"after expiration", m.ExpirationTime.Add(time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.ValidAt(tt.at)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidAt(%s) error = %v, wantErr %v", tt.at, err, tt.wantErr)
+			}
+		})
+	}
+}
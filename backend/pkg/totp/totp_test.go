@@ -0,0 +1,144 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA-1 密钥 "12345678901234567890" 的 base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 附录 B 的 SHA-1 测试向量，取 8 位验证码的后 6 位
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(T=%d): %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("Code(T=%d) = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := Code(strings.ToLower(rfcSecret)+" ", Step(time.Unix(59, 0)))
+	if err != nil || got != "287082" {
+		t.Errorf("Code(lowercase secret) = %q, %v, want 287082", got, err)
+	}
+}
+
+func TestCodeRejectsInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code accepted an invalid secret")
+	}
+}
+
+func TestValidateReturnsMatchedStep(t *testing.T) {
+	for _, v := range rfcVectors {
+		now := time.Unix(v.unix, 0)
+		step, ok := Validate(rfcSecret, v.code, now, 1)
+		if !ok || step != Step(now) {
+			t.Errorf("Validate(T=%d) = %d, %v, want %d, true", v.unix, step, ok, Step(now))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	// T=1111111111 所在时间步覆盖 [1111111110, 1111111139]
+	code := "050471"
+	codeStep := Step(time.Unix(1111111111, 0))
+
+	tests := []struct {
+		name string
+		unix int64
+		skew int
+		ok   bool
+	}{
+		{"first second of step", 1111111110, 0, true},
+		{"last second of step", 1111111139, 0, true},
+		{"next step, no skew", 1111111140, 0, false},
+		{"next step within skew", 1111111140, 1, true},
+		{"last second of skew window", 1111111169, 1, true},
+		{"two steps later", 1111111170, 1, false},
+		{"two steps later, wider skew", 1111111170, 2, true},
+		{"previous step, no skew", 1111111109, 0, false},
+		{"previous step within skew", 1111111109, 1, true},
+		{"two steps earlier", 1111111079, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, code, time.Unix(tt.unix, 0), tt.skew)
+			if ok != tt.ok {
+				t.Fatalf("Validate(T=%d, skew=%d) = %v, want %v", tt.unix, tt.skew, ok, tt.ok)
+			}
+			// 返回验证码所属的时间步，而不是当前时间步，调用方据此拒绝重放
+			if ok && step != codeStep {
+				t.Errorf("Validate(T=%d) step = %d, want %d", tt.unix, step, codeStep)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "94287082", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("Validate(%q) = true, want false", code)
+		}
+	}
+	// 前后空白被忽略
+	if _, ok := Validate(rfcSecret, " 287082 ", now, 0); !ok {
+		t.Error("Validate rejected a code with surrounding spaces")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if a == b {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+	// 20 字节无填充 base32 编码为 32 个字符
+	if len(a) != 32 {
+		t.Errorf("len(secret) = %d, want 32", len(a))
+	}
+	if _, err := Code(a, 1); err != nil {
+		t.Errorf("generated secret is not usable: %v", err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("DeData", "did:dedata:0xabc", rfcSecret)
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("uri = %s, want otpauth://totp/...", uri)
+	}
+	if u.Path != "/DeData:did:dedata:0xabc" {
+		t.Errorf("label = %q", u.Path)
+	}
+	q := u.Query()
+	if q.Get("secret") != rfcSecret || q.Get("issuer") != "DeData" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Errorf("query = %v", q)
+	}
+}
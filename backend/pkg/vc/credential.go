@@ -0,0 +1,59 @@
+package vc
+
+import "github.com/golang-jwt/jwt/v5"
+
+// W3C Verifiable Credentials Data Model 1.1，使用 VC-JWT 编码:
+// iss / sub / jti / nbf / exp 由 JWT 注册声明承载，vc 声明中不再重复
+
+const (
+	ContextV1         = "https://www.w3.org/2018/credentials/v1"
+	ContextStatusList = "https://w3id.org/vc/status-list/2021/v1"
+
+	TypeVerifiableCredential = "VerifiableCredential"
+	TypeStatusList           = "StatusList2021Credential"
+
+	StatusEntryType         = "StatusList2021Entry"
+	StatusPurposeRevocation = "revocation"
+)
+
+// Credential vc 声明
+type Credential struct {
+	Context           []string               `json:"@context"`
+	Type              []string               `json:"type"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	CredentialStatus  *Status                `json:"credentialStatus,omitempty"`
+}
+
+// Status StatusList2021Entry，指向吊销状态列表中的一位
+type Status struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+// Claims VC-JWT 声明
+type Claims struct {
+	VC Credential `json:"vc"`
+	jwt.RegisteredClaims
+}
+
+// New 创建带基础 context 和 type 的 credential
+func New(credentialType string, subject map[string]interface{}) Credential {
+	return Credential{
+		Context:           []string{ContextV1},
+		Type:              []string{TypeVerifiableCredential, credentialType},
+		CredentialSubject: subject,
+	}
+}
+
+// HasType 是否包含指定类型
+func (c *Credential) HasType(credentialType string) bool {
+	for _, t := range c.Type {
+		if t == credentialType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package vc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+)
+
+// StatusListSize 每个状态列表的位数 (StatusList2021 规定最少 16KB，保证群体隐私)
+const StatusListSize = 131072
+
+// StatusList 吊销状态位图，第 i 位为 1 表示对应 credential 已吊销 (高位在前)
+type StatusList []byte
+
+// NewStatusList 创建全 0 的状态列表
+func NewStatusList() StatusList {
+	return make(StatusList, StatusListSize/8)
+}
+
+// Locate 将全局状态序号拆分为列表编号和列表内序号
+func Locate(index int64) (list int64, offset int) {
+	return index / StatusListSize, int(index % StatusListSize)
+}
+
+// Set 将第 i 位置 1
+func (l StatusList) Set(i int) error {
+	if i < 0 || i >= len(l)*8 {
+		return fmt.Errorf("status list index out of range: %d", i)
+	}
+	l[i/8] |= 1 << (7 - uint(i%8))
+	return nil
+}
+
+// IsSet 第 i 位是否为 1
+func (l StatusList) IsSet(i int) bool {
+	if i < 0 || i >= len(l)*8 {
+		return false
+	}
+	return l[i/8]&(1<<(7-uint(i%8))) != 0
+}
+
+// Encode GZIP 压缩后 base64url 编码，作为 encodedList
+func (l StatusList) Encode() (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(l); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
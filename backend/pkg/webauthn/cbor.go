@@ -0,0 +1,181 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// errMalformedCBOR CBOR 数据无法解析
+var errMalformedCBOR = errors.New("malformed cbor")
+
+// maxCBORDepth 嵌套层数上限，attestation object 与 COSE key 都只有两三层
+const maxCBORDepth = 8
+
+// decodeCBOR 解析 WebAuthn 用到的 CBOR 子集 (RFC 8949)，返回值与消耗的字节数
+// 整数解码为 int64，字节串为 []byte，文本为 string，数组为 []interface{}，map 为 map[interface{}]interface{}
+// 不支持不定长编码 (CTAP2 规范要求使用确定性编码)
+func decodeCBOR(data []byte) (interface{}, int, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v, d.pos, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) value(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, errMalformedCBOR
+	}
+	if d.pos >= len(d.data) {
+		return nil, errMalformedCBOR
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	// 浮点数与简单值的参数不是长度，单独处理
+	if major == 7 {
+		return d.simple(info)
+	}
+
+	arg, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, errMalformedCBOR
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errMalformedCBOR
+		}
+		return -1 - int64(arg), nil
+	case 2, 3:
+		raw, err := d.take(arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	case 4:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errMalformedCBOR
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errMalformedCBOR
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errMalformedCBOR
+			}
+			val, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = val
+		}
+		return m, nil
+	default: // 6: tag，忽略标签取内部值
+		return d.value(depth + 1)
+	}
+}
+
+// argument 读取头部参数 (长度或整数值)
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		b, err := d.take(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(b[0]), nil
+	case info == 25:
+		b, err := d.take(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err := d.take(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err := d.take(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	default:
+		return 0, errMalformedCBOR
+	}
+}
+
+// simple 解析 major type 7：false / true / null / undefined 与单、双精度浮点数
+func (d *cborDecoder) simple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 26:
+		b, err := d.take(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.take(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	default:
+		return nil, errMalformedCBOR
+	}
+}
+
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errMalformedCBOR
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
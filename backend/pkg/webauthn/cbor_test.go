@@ -0,0 +1,103 @@
+package webauthn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// RFC 8949 附录 A 中 WebAuthn 用到的子集
+var cborVectors = []struct {
+	hex  string
+	want interface{}
+}{
+	{"00", int64(0)},
+	{"17", int64(23)},
+	{"1818", int64(24)},
+	{"1903e8", int64(1000)},
+	{"1a000f4240", int64(1000000)},
+	{"1b000000e8d4a51000", int64(1000000000000)},
+	{"20", int64(-1)},
+	{"3863", int64(-100)},
+	{"390100", int64(-257)},
+	{"40", []byte(nil)},
+	{"4401020304", []byte{1, 2, 3, 4}},
+	{"60", ""},
+	{"6449455446", "IETF"},
+	{"80", []interface{}{}},
+	{"83010203", []interface{}{int64(1), int64(2), int64(3)}},
+	{"8301820203820405", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+	{"a0", map[interface{}]interface{}{}},
+	{"a201020304", map[interface{}]interface{}{int64(1): int64(2), int64(3): int64(4)}},
+	{"a26161016162820203", map[interface{}]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+	{"f4", false},
+	{"f5", true},
+	{"f6", nil},
+	{"fa47c35000", float64(100000)},
+	{"fb3ff199999999999a", 1.1},
+	{"c11a514b67b0", int64(1363896240)}, // tag 1 (epoch time)，忽略标签
+}
+
+func TestDecodeCBORVectors(t *testing.T) {
+	for _, v := range cborVectors {
+		data, _ := hex.DecodeString(v.hex)
+		got, n, err := decodeCBOR(data)
+		if err != nil {
+			t.Errorf("decodeCBOR(%s): %v", v.hex, err)
+			continue
+		}
+		if n != len(data) {
+			t.Errorf("decodeCBOR(%s) consumed %d bytes, want %d", v.hex, n, len(data))
+		}
+		if !reflect.DeepEqual(got, v.want) {
+			t.Errorf("decodeCBOR(%s) = %#v, want %#v", v.hex, got, v.want)
+		}
+	}
+}
+
+func TestDecodeCBORReportsConsumedBytes(t *testing.T) {
+	// COSE key 之后紧跟扩展数据时，只消耗第一个值
+	data, _ := hex.DecodeString("a2010203040a0b")
+	_, n, err := decodeCBOR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Errorf("consumed %d bytes, want 5", n)
+	}
+}
+
+func TestDecodeCBORRejectsMalformedInput(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x81}, maxCBORDepth+2)
+	deep = append(deep, 0x00)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated argument", []byte{0x19, 0x03}},
+		{"truncated byte string", []byte{0x44, 0x01, 0x02}},
+		{"truncated text string", []byte{0x64, 'I', 'E'}},
+		{"truncated array", []byte{0x83, 0x01, 0x02}},
+		{"truncated map value", []byte{0xa1, 0x01}},
+		{"reserved additional info", []byte{0x1c}},
+		{"indefinite length array", []byte{0x9f, 0x01, 0xff}},
+		{"indefinite length byte string", []byte{0x5f, 0x41, 0x01, 0xff}},
+		{"byte string map key", []byte{0xa1, 0x41, 0x01, 0x01}},
+		{"array length beyond input", []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"byte string length beyond input", []byte{0x5b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"unsigned integer overflows int64", []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"unsupported simple value", []byte{0xf8, 0x20}},
+		{"nesting too deep", deep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeCBOR(tt.data); !errors.Is(err, errMalformedCBOR) {
+				t.Errorf("decodeCBOR(%x) error = %v, want errMalformedCBOR", tt.data, err)
+			}
+		})
+	}
+}
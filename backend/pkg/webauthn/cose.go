@@ -0,0 +1,117 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+)
+
+// COSE 算法标识 (RFC 9053)，与 pubKeyCredParams 中的 alg 一致
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms 按优先级排列的可接受算法
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key 参数 (RFC 9052 7.1)
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	coseCurve     = -1 // OKP / EC2: crv；RSA: n
+	coseX         = -2 // OKP / EC2: x；RSA: e
+	coseY         = -3
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+
+	// minRSABits RSA 公钥的最小长度
+	minRSABits = 2048
+)
+
+// publicKey 解析后的凭证公钥
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey 解析 COSE_Key 编码的公钥
+func parsePublicKey(cose []byte) (*publicKey, error) {
+	v, n, err := decodeCBOR(cose)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(cose) {
+		return nil, errMalformedCBOR
+	}
+	m, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	kty, _ := m[int64(coseKeyType)].(int64)
+	alg, _ := m[int64(coseAlgorithm)].(int64)
+
+	switch {
+	case kty == coseKeyTypeEC2 && alg == AlgES256:
+		crv, _ := m[int64(coseCurve)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		y, _ := m[int64(coseY)].([]byte)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: pub}, nil
+
+	case kty == coseKeyTypeOKP && alg == AlgEdDSA:
+		crv, _ := m[int64(coseCurve)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == coseKeyTypeRSA && alg == AlgRS256:
+		nBytes, _ := m[int64(coseCurve)].([]byte)
+		eBytes, _ := m[int64(coseX)].([]byte)
+		if len(eBytes) == 0 || len(eBytes) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		e := new(big.Int).SetBytes(eBytes)
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(e.Int64())}
+		if pub.N.BitLen() < minRSABits || pub.E < 3 {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: pub}, nil
+	}
+
+	return nil, ErrUnsupportedKey
+}
+
+// verify 校验签名；ES256 签名为 ASN.1 DER 编码
+func (k *publicKey) verify(data, signature []byte) bool {
+	switch pub := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(pub, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
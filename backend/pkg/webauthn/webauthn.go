@@ -0,0 +1,284 @@
+// Package webauthn 实现 WebAuthn Level 2 依赖方 (Relying Party) 的注册与断言校验
+// 只接受 "none" 证明 (不校验 attestation statement，凭证的可信度来自已登录的钱包会话)，
+// 凭证算法支持 ES256 / EdDSA / RS256，并始终要求用户验证 (UV)
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// clientDataJSON 中的 type
+const (
+	TypeCreate = "webauthn.create"
+	TypeGet    = "webauthn.get"
+)
+
+// ChallengeSize challenge 随机字节数
+const ChallengeSize = 32
+
+// authenticator data flags (WebAuthn 6.1)
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+	flagExtensionData  = 0x80
+)
+
+var (
+	// ErrInvalidClientData clientDataJSON 无法解析，或 type / challenge / origin 不匹配
+	ErrInvalidClientData = errors.New("invalid client data")
+
+	// ErrInvalidAuthenticatorData authenticator data 无法解析，或 RP ID / 用户在场 / 用户验证标志不满足要求
+	ErrInvalidAuthenticatorData = errors.New("invalid authenticator data")
+
+	// ErrUnsupportedKey 凭证公钥的算法或参数不受支持
+	ErrUnsupportedKey = errors.New("unsupported credential public key")
+
+	// ErrInvalidSignature 断言签名校验失败
+	ErrInvalidSignature = errors.New("invalid assertion signature")
+)
+
+// RelyingParty 依赖方配置
+type RelyingParty struct {
+	ID      string   // RP ID，前端页面的有效域名，例如 app.dedata.io
+	Origins []string // 允许的页面 origin，例如 https://app.dedata.io
+}
+
+// ClientData 浏览器生成的 clientDataJSON
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"` // base64url (无填充)
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// AuthenticatorData 认证器数据，注册时包含新凭证的 ID 与公钥
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte // COSE_Key
+}
+
+// UserVerified 认证器是否完成了用户验证 (生物识别 / PIN)
+func (a *AuthenticatorData) UserVerified() bool {
+	return a.Flags&flagUserVerified != 0
+}
+
+// BackupEligible 凭证是否可以同步备份 (多设备 passkey)
+func (a *AuthenticatorData) BackupEligible() bool {
+	return a.Flags&flagBackupEligible != 0
+}
+
+// BackedUp 凭证当前是否已同步备份
+func (a *AuthenticatorData) BackedUp() bool {
+	return a.Flags&flagBackedUp != 0
+}
+
+// Credential 注册成功的凭证
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key，断言时用于验签
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	BackupEligible bool
+	BackedUp       bool
+}
+
+// NewChallenge 生成 base64url 编码的随机 challenge
+func NewChallenge() (string, error) {
+	b := make([]byte, ChallengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ParseClientData 解析 clientDataJSON，调用方据此取出 challenge 查找服务端状态
+func ParseClientData(raw []byte) (*ClientData, error) {
+	var cd ClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidClientData, err)
+	}
+	if cd.Type == "" || cd.Challenge == "" || cd.Origin == "" {
+		return nil, ErrInvalidClientData
+	}
+	return &cd, nil
+}
+
+// VerifyRegistration 校验 navigator.credentials.create() 的结果 (WebAuthn 7.1)
+// challenge 为服务端签发并已消费的 challenge
+func (rp *RelyingParty) VerifyRegistration(clientDataJSON, attestationObject []byte, challenge string) (*Credential, error) {
+	if err := rp.checkClientData(clientDataJSON, TypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	v, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthenticatorData, err)
+	}
+	att, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrInvalidAuthenticatorData
+	}
+	rawAuthData, ok := att["authData"].([]byte)
+	if !ok {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	authData, err := rp.checkAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.CredentialID == nil {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrInvalidAuthenticatorData)
+	}
+
+	key, err := parsePublicKey(authData.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:             authData.CredentialID,
+		PublicKey:      authData.PublicKey,
+		Algorithm:      key.alg,
+		SignCount:      authData.SignCount,
+		AAGUID:         authData.AAGUID,
+		BackupEligible: authData.BackupEligible(),
+		BackedUp:       authData.BackedUp(),
+	}, nil
+}
+
+// VerifyAssertion 校验 navigator.credentials.get() 的结果 (WebAuthn 7.2)
+// publicKey 为注册时保存的 COSE_Key；签名计数器的回退检查由调用方根据返回的 SignCount 完成
+func (rp *RelyingParty) VerifyAssertion(clientDataJSON, authenticatorData, signature, publicKey []byte, challenge string) (*AuthenticatorData, error) {
+	if err := rp.checkClientData(clientDataJSON, TypeGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := rp.checkAuthenticatorData(authenticatorData)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := make([]byte, 0, len(authenticatorData)+len(clientDataHash))
+	signed = append(signed, authenticatorData...)
+	signed = append(signed, clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return nil, ErrInvalidSignature
+	}
+	return authData, nil
+}
+
+// checkClientData 校验 type、challenge 与 origin
+func (rp *RelyingParty) checkClientData(raw []byte, typ, challenge string) error {
+	cd, err := ParseClientData(raw)
+	if err != nil {
+		return err
+	}
+	if cd.Type != typ {
+		return fmt.Errorf("%w: unexpected type %q", ErrInvalidClientData, cd.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrInvalidClientData)
+	}
+	if cd.CrossOrigin {
+		return fmt.Errorf("%w: cross-origin requests are not allowed", ErrInvalidClientData)
+	}
+	for _, origin := range rp.Origins {
+		if cd.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: origin %q is not allowed", ErrInvalidClientData, cd.Origin)
+}
+
+// checkAuthenticatorData 解析 authenticator data 并校验 RP ID 摘要、用户在场与用户验证
+func (rp *RelyingParty) checkAuthenticatorData(raw []byte) (*AuthenticatorData, error) {
+	authData, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData.RPIDHash, rpIDHash[:]) {
+		return nil, fmt.Errorf("%w: rp id mismatch", ErrInvalidAuthenticatorData)
+	}
+	if authData.Flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrInvalidAuthenticatorData)
+	}
+	if !authData.UserVerified() {
+		return nil, fmt.Errorf("%w: user not verified", ErrInvalidAuthenticatorData)
+	}
+	return authData, nil
+}
+
+// parseAuthenticatorData 解析 rpIdHash(32) || flags(1) || signCount(4) || [attestedCredentialData] || [extensions]
+func parseAuthenticatorData(raw []byte) (*AuthenticatorData, error) {
+	const headerLen = 37
+	if len(raw) < headerLen {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidAuthenticatorData)
+	}
+
+	authData := &AuthenticatorData{
+		RPIDHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[headerLen:]
+
+	if authData.Flags&flagAttestedData != 0 {
+		// aaguid(16) || credentialIdLength(2) || credentialId || credentialPublicKey (COSE_Key)
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: truncated attested credential data", ErrInvalidAuthenticatorData)
+		}
+		authData.AAGUID = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: invalid credential id", ErrInvalidAuthenticatorData)
+		}
+		authData.CredentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid credential public key", ErrInvalidAuthenticatorData)
+		}
+		authData.PublicKey = rest[:n]
+		rest = rest[n:]
+	}
+
+	if authData.Flags&flagExtensionData != 0 {
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid extensions", ErrInvalidAuthenticatorData)
+		}
+		rest = rest[n:]
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", ErrInvalidAuthenticatorData)
+	}
+	return authData, nil
+}
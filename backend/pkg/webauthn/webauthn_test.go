@@ -0,0 +1,334 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+const (
+	testRPID      = "app.dedata.io"
+	testOrigin    = "https://app.dedata.io"
+	testChallenge = "dGVzdC1jaGFsbGVuZ2UtMzItYnl0ZXMtbG9uZy4uLi4"
+)
+
+var testRP = &RelyingParty{ID: testRPID, Origins: []string{testOrigin, "https://dedata.io"}}
+
+// cborPair / cborMap 保持键的顺序，测试中按 CTAP2 规范的确定性顺序编码
+type cborPair struct {
+	key, value interface{}
+}
+
+type cborMap []cborPair
+
+// encodeCBOR 编码测试用到的 CBOR 子集
+func encodeCBOR(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+		default:
+			b := []byte{major<<5 | 26, 0, 0, 0, 0}
+			binary.BigEndian.PutUint32(b[1:], uint32(n))
+			return b
+		}
+	}
+
+	switch v := v.(type) {
+	case int:
+		return encodeCBOR(int64(v))
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case cborMap:
+		out := head(5, uint64(len(v)))
+		for _, p := range v {
+			out = append(out, encodeCBOR(p.key)...)
+			out = append(out, encodeCBOR(p.value)...)
+		}
+		return out
+	}
+	panic("unsupported cbor value")
+}
+
+func ec2Key(pub *ecdsa.PublicKey) []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	pub.X.FillBytes(x)
+	pub.Y.FillBytes(y)
+	return encodeCBOR(cborMap{{1, 2}, {3, -7}, {-1, 1}, {-2, x}, {-3, y}})
+}
+
+func okpKey(pub ed25519.PublicKey) []byte {
+	return encodeCBOR(cborMap{{1, 1}, {3, -8}, {-1, 6}, {-2, []byte(pub)}})
+}
+
+func rsaKey(pub *rsa.PublicKey) []byte {
+	return encodeCBOR(cborMap{{1, 3}, {3, -257}, {-1, pub.N.Bytes()}, {-2, big.NewInt(int64(pub.E)).Bytes()}})
+}
+
+// authData 构造 authenticator data；credentialID 非空时附带 attested credential data
+func authData(rpID string, flags byte, signCount uint32, credentialID, coseKey []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte{}, rpIDHash[:]...)
+	out = append(out, flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[33:], signCount)
+	if credentialID != nil {
+		aaguid := make([]byte, 16)
+		aaguid[0] = 0xad
+		out = append(out, aaguid...)
+		out = append(out, byte(len(credentialID)>>8), byte(len(credentialID)))
+		out = append(out, credentialID...)
+		out = append(out, coseKey...)
+	}
+	return out
+}
+
+func clientData(typ, challenge, origin string, crossOrigin bool) []byte {
+	b, _ := json.Marshal(ClientData{Type: typ, Challenge: challenge, Origin: origin, CrossOrigin: crossOrigin})
+	return b
+}
+
+func attestationObject(authData []byte) []byte {
+	return encodeCBOR(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", authData}})
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, authData, clientDataJSON []byte) []byte {
+	t.Helper()
+	hash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	credentialID := []byte("credential-id-0001")
+	flags := byte(flagUserPresent | flagUserVerified | flagBackupEligible | flagBackedUp | flagAttestedData)
+	data := authData(testRPID, flags, 7, credentialID, ec2Key(&key.PublicKey))
+
+	cred, err := testRP.VerifyRegistration(clientData(TypeCreate, testChallenge, testOrigin, false), attestationObject(data), testChallenge)
+	if err != nil {
+		t.Fatalf("VerifyRegistration: %v", err)
+	}
+	if string(cred.ID) != string(credentialID) {
+		t.Errorf("ID = %q, want %q", cred.ID, credentialID)
+	}
+	if cred.Algorithm != AlgES256 || cred.SignCount != 7 || cred.AAGUID[0] != 0xad {
+		t.Errorf("credential = alg %d, signCount %d, aaguid %x", cred.Algorithm, cred.SignCount, cred.AAGUID)
+	}
+	if !cred.BackupEligible || !cred.BackedUp {
+		t.Error("backup flags were not reported")
+	}
+	if _, err := parsePublicKey(cred.PublicKey); err != nil {
+		t.Errorf("stored public key does not parse: %v", err)
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	flags := byte(flagUserPresent | flagUserVerified | flagAttestedData)
+	valid := authData(testRPID, flags, 0, []byte("id"), ec2Key(&key.PublicKey))
+	create := clientData(TypeCreate, testChallenge, testOrigin, false)
+
+	// 不在曲线上的点
+	offCurve := encodeCBOR(cborMap{{1, 2}, {3, -7}, {-1, 1}, {-2, make([]byte, 32)}, {-3, make([]byte, 32)}})
+	// 1024 位 RSA 低于 2048 位下限
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	tests := []struct {
+		name        string
+		clientData  []byte
+		attestation []byte
+		want        error
+	}{
+		{"get instead of create", clientData(TypeGet, testChallenge, testOrigin, false), attestationObject(valid), ErrInvalidClientData},
+		{"malformed attestation object", create, []byte{0xa3, 0x63, 'f', 'm'}, ErrInvalidAuthenticatorData},
+		{"attestation object is not a map", create, encodeCBOR("authData"), ErrInvalidAuthenticatorData},
+		{"missing authData", create, encodeCBOR(cborMap{{"fmt", "none"}}), ErrInvalidAuthenticatorData},
+		{"no attested credential data", create, attestationObject(authData(testRPID, flagUserPresent|flagUserVerified, 0, nil, nil)), ErrInvalidAuthenticatorData},
+		{"truncated attested credential data", create, attestationObject(valid[:37+10]), ErrInvalidAuthenticatorData},
+		{"truncated public key", create, attestationObject(valid[:len(valid)-5]), ErrInvalidAuthenticatorData},
+		{"trailing bytes", create, attestationObject(append(append([]byte{}, valid...), 0x00)), ErrInvalidAuthenticatorData},
+		{"point not on curve", create, attestationObject(authData(testRPID, flags, 0, []byte("id"), offCurve)), ErrUnsupportedKey},
+		{"rsa key too small", create, attestationObject(authData(testRPID, flags, 0, []byte("id"), rsaKey(&weak.PublicKey))), ErrUnsupportedKey},
+		{"algorithm does not match key type", create, attestationObject(authData(testRPID, flags, 0, []byte("id"),
+			encodeCBOR(cborMap{{1, 2}, {3, -8}, {-1, 1}, {-2, make([]byte, 32)}, {-3, make([]byte, 32)}}))), ErrUnsupportedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testRP.VerifyRegistration(tt.clientData, tt.attestation, testChallenge)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionES256(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cose := ec2Key(&key.PublicKey)
+	data := authData(testRPID, flagUserPresent|flagUserVerified, 42, nil, nil)
+	cd := clientData(TypeGet, testChallenge, testOrigin, false)
+
+	got, err := testRP.VerifyAssertion(cd, data, signES256(t, key, data, cd), cose, testChallenge)
+	if err != nil {
+		t.Fatalf("VerifyAssertion: %v", err)
+	}
+	if got.SignCount != 42 || !got.UserVerified() {
+		t.Errorf("authData = signCount %d, uv %v", got.SignCount, got.UserVerified())
+	}
+
+	// 第二个允许的 origin
+	other := clientData(TypeGet, testChallenge, "https://dedata.io", false)
+	if _, err := testRP.VerifyAssertion(other, data, signES256(t, key, data, other), cose, testChallenge); err != nil {
+		t.Errorf("second origin: %v", err)
+	}
+}
+
+func TestVerifyAssertionEdDSA(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	data := authData(testRPID, flagUserPresent|flagUserVerified, 0, nil, nil)
+	cd := clientData(TypeGet, testChallenge, testOrigin, false)
+	hash := sha256.Sum256(cd)
+	sig := ed25519.Sign(priv, append(append([]byte{}, data...), hash[:]...))
+
+	if _, err := testRP.VerifyAssertion(cd, data, sig, okpKey(pub), testChallenge); err != nil {
+		t.Fatalf("VerifyAssertion: %v", err)
+	}
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cose := ec2Key(&key.PublicKey)
+	uvFlags := byte(flagUserPresent | flagUserVerified)
+	valid := authData(testRPID, uvFlags, 1, nil, nil)
+	get := clientData(TypeGet, testChallenge, testOrigin, false)
+
+	tests := []struct {
+		name       string
+		clientData []byte
+		authData   []byte
+		signer     *ecdsa.PrivateKey
+		challenge  string
+		want       error
+	}{
+		{"wrong origin", clientData(TypeGet, testChallenge, "https://evil.example", false), valid, key, testChallenge, ErrInvalidClientData},
+		{"origin with trailing slash", clientData(TypeGet, testChallenge, testOrigin+"/", false), valid, key, testChallenge, ErrInvalidClientData},
+		{"cross origin", clientData(TypeGet, testChallenge, testOrigin, true), valid, key, testChallenge, ErrInvalidClientData},
+		{"create instead of get", clientData(TypeCreate, testChallenge, testOrigin, false), valid, key, testChallenge, ErrInvalidClientData},
+		{"challenge mismatch", get, valid, key, "b3RoZXItY2hhbGxlbmdl", ErrInvalidClientData},
+		{"malformed client data", []byte(`{"type":`), valid, key, testChallenge, ErrInvalidClientData},
+		{"client data missing origin", []byte(`{"type":"webauthn.get","challenge":"` + testChallenge + `"}`), valid, key, testChallenge, ErrInvalidClientData},
+		{"wrong rp id", get, authData("evil.example", uvFlags, 1, nil, nil), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"parent domain rp id", get, authData("dedata.io", uvFlags, 1, nil, nil), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"user not present", get, authData(testRPID, flagUserVerified, 1, nil, nil), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"user not verified", get, authData(testRPID, flagUserPresent, 1, nil, nil), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"truncated authenticator data", get, valid[:36], key, testChallenge, ErrInvalidAuthenticatorData},
+		{"trailing bytes", get, append(append([]byte{}, valid...), 0x01), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"malformed extensions", get, append(authData(testRPID, uvFlags|flagExtensionData, 1, nil, nil), 0xa1), key, testChallenge, ErrInvalidAuthenticatorData},
+		{"signed by another key", get, valid, other, testChallenge, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := signES256(t, tt.signer, tt.authData, tt.clientData)
+			_, err := testRP.VerifyAssertion(tt.clientData, tt.authData, sig, cose, tt.challenge)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejectsTamperedData(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cose := ec2Key(&key.PublicKey)
+	data := authData(testRPID, flagUserPresent|flagUserVerified, 5, nil, nil)
+	cd := clientData(TypeGet, testChallenge, testOrigin, false)
+	sig := signES256(t, key, data, cd)
+
+	// 签名覆盖 signCount：修改计数器后签名失效
+	tampered := append([]byte{}, data...)
+	tampered[36]++
+	if _, err := testRP.VerifyAssertion(cd, tampered, sig, cose, testChallenge); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered sign count: error = %v, want ErrInvalidSignature", err)
+	}
+
+	// 签名覆盖 clientDataJSON 的摘要：空白不同的 JSON 同样失效
+	reformatted := append([]byte{' '}, cd...)
+	if _, err := testRP.VerifyAssertion(reformatted, data, sig, cose, testChallenge); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("reformatted client data: error = %v, want ErrInvalidSignature", err)
+	}
+
+	// 非 DER 编码的签名
+	if _, err := testRP.VerifyAssertion(cd, data, sig[:len(sig)-1], cose, testChallenge); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("truncated signature: error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	rsaPriv, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	for name, tt := range map[string]struct {
+		cose []byte
+		alg  int64
+	}{
+		"ES256": {ec2Key(&ec.PublicKey), AlgES256},
+		"EdDSA": {okpKey(edPub), AlgEdDSA},
+		"RS256": {rsaKey(&rsaPriv.PublicKey), AlgRS256},
+	} {
+		key, err := parsePublicKey(tt.cose)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if key.alg != tt.alg {
+			t.Errorf("%s: alg = %d, want %d", name, key.alg, tt.alg)
+		}
+	}
+
+	x := make([]byte, 32)
+	ec.PublicKey.X.FillBytes(x)
+	y := make([]byte, 32)
+	ec.PublicKey.Y.FillBytes(y)
+	rejects := map[string][]byte{
+		"P-384 curve":        encodeCBOR(cborMap{{1, 2}, {3, -7}, {-1, 2}, {-2, x}, {-3, y}}),
+		"short x coordinate": encodeCBOR(cborMap{{1, 2}, {3, -7}, {-1, 1}, {-2, x[1:]}, {-3, y}}),
+		"X25519 curve":       encodeCBOR(cborMap{{1, 1}, {3, -8}, {-1, 4}, {-2, []byte(edPub)}}),
+		"unknown algorithm":  encodeCBOR(cborMap{{1, 2}, {3, -35}, {-1, 1}, {-2, x}, {-3, y}}),
+		"not a map":          encodeCBOR([]byte{1, 2, 3}),
+	}
+	for name, cose := range rejects {
+		if _, err := parsePublicKey(cose); !errors.Is(err, ErrUnsupportedKey) {
+			t.Errorf("%s: error = %v, want ErrUnsupportedKey", name, err)
+		}
+	}
+
+	// COSE_Key 之后不能有多余字节
+	if _, err := parsePublicKey(append(ec2Key(&ec.PublicKey), 0x00)); !errors.Is(err, errMalformedCBOR) {
+		t.Errorf("trailing bytes: error = %v, want errMalformedCBOR", err)
+	}
+}
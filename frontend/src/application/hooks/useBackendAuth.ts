@@ -45,14 +45,15 @@ export function useBackendAuth() {
         setLoadingStep('nonce')
         const nonceResponse = await backendAuthApi.getNonce({
           walletAddress: address,
+          chainId,
         })
         const currentNonce = nonceResponse.nonce
         setNonce(currentNonce)
 
         console.log('[useBackendAuth] Got nonce:', currentNonce)
 
-        // Step 2: 构造签名消息
-        const message = `Sign this message to authenticate with DeData Protocol\n\nNonce: ${currentNonce}`
+        // Step 2: 使用后端返回的 SIWE 消息
+        const message = nonceResponse.message
         console.log('[useBackendAuth] Message to sign:', message)
 
         // Step 3: Sign message
@@ -73,11 +74,13 @@ export function useBackendAuth() {
           walletAddress: address,
           nonce: currentNonce,
           signature,
+          message,
         })
 
         // 保存 JWT token 到 localStorage
         if (verifyResponse.token) {
           localStorage.setItem('jwt_token', verifyResponse.token)
+          localStorage.setItem('refresh_token', verifyResponse.refreshToken)
         }
 
         return verifyResponse
@@ -115,6 +118,7 @@ export function useBackendAuth() {
       // 清除可能的脏数据
       setNonce(null)
       localStorage.removeItem('jwt_token')
+      localStorage.removeItem('refresh_token')
     },
   })
 
@@ -133,6 +137,7 @@ export function useBackendAuth() {
     onSuccess: () => {
       // 清除本地存储
       localStorage.removeItem('jwt_token')
+      localStorage.removeItem('refresh_token')
 
       // 清除所有 query 缓存
       queryClient.clear()
@@ -144,6 +149,7 @@ export function useBackendAuth() {
     onError: () => {
       // 即使出错也清除本地状态
       localStorage.removeItem('jwt_token')
+      localStorage.removeItem('refresh_token')
       queryClient.clear()
       router.push('/')
     },
@@ -16,6 +16,36 @@ export interface BackendResponse<T = any> {
   data: T
 }
 
+// 使用 refresh token 换取新的 access token (后端会轮换 refresh token)
+// 并发的 401 共享同一个刷新请求，避免同一个 refresh token 被重复使用而触发会话吊销
+let refreshPromise: Promise<string | null> | null = null
+
+const refreshAccessToken = (): Promise<string | null> => {
+  if (refreshPromise) return refreshPromise
+
+  const refreshToken = localStorage.getItem('refresh_token')
+  if (!refreshToken) return Promise.resolve(null)
+
+  refreshPromise = axios
+    .post<BackendResponse<{ token: string; refreshToken: string }>>(
+      `${BACKEND_API_URL}/auth/refresh`,
+      { refreshToken },
+      { timeout: API_TIMEOUT }
+    )
+    .then((res) => {
+      if (res.data.code !== 0) return null
+      localStorage.setItem('jwt_token', res.data.data.token)
+      localStorage.setItem('refresh_token', res.data.data.refreshToken)
+      return res.data.data.token
+    })
+    .catch(() => null)
+    .finally(() => {
+      refreshPromise = null
+    })
+
+  return refreshPromise
+}
+
 // 创建 axios 实例连接 Go 后端
 const createBackendApiInstance = (): AxiosInstance => {
   const instance = axios.create({
@@ -84,12 +114,27 @@ const createBackendApiInstance = (): AxiosInstance => {
             })
 
           case 401:
-            // 未认证
+            // 未认证: 先尝试用 refresh token 刷新一次并重试原请求
+            if (
+              typeof window !== 'undefined' &&
+              error.config &&
+              !(error.config as any)._retried &&
+              !error.config.url?.includes('/auth/refresh')
+            ) {
+              const newToken = await refreshAccessToken()
+              if (newToken) {
+                const retryConfig = { ...error.config, _retried: true } as any
+                retryConfig.headers = { ...error.config.headers, Authorization: `Bearer ${newToken}` }
+                return instance.request(retryConfig)
+              }
+            }
+
             console.warn('[Backend API] Unauthorized - clearing auth state')
 
             // 清除认证状态
             if (typeof window !== 'undefined') {
               localStorage.removeItem('jwt_token')
+              localStorage.removeItem('refresh_token')
 
               // 清除 React Query 缓存
               try {
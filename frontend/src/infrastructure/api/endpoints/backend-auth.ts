@@ -7,13 +7,18 @@ import { backendRequest } from '../backend-client'
 
 // ============ Request/Response Types ============
 
-// Nonce 请求 (简化版)
+// Nonce 请求
 export interface GetNonceRequest {
   walletAddress: string
+  chainId?: number
 }
 
+// message 是后端构造的 EIP-4361 (SIWE) 消息，需原样签名
 export interface GetNonceResponse {
   nonce: string
+  message: string
+  issuedAt: string
+  expiresAt: string
 }
 
 // 验证签名请求
@@ -21,6 +26,7 @@ export interface VerifySignatureRequest {
   walletAddress: string
   nonce: string
   signature: string
+  message: string
 }
 
 export interface User {
@@ -35,6 +41,8 @@ export interface User {
 
 export interface VerifySignatureResponse {
   token: string
+  refreshToken: string
+  expiresAt: string
   user:  {
     id: string
     did: string